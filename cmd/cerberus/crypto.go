@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Decentr-net/cerberus/internal/crypto"
+	"github.com/Decentr-net/cerberus/internal/crypto/envelope"
+	"github.com/Decentr-net/cerberus/internal/crypto/local"
+	"github.com/Decentr-net/cerberus/internal/crypto/sio"
+	"github.com/Decentr-net/cerberus/internal/storage"
+)
+
+type CryptoOpts struct {
+	KeyProvider                string            `long:"key-provider" env:"KEY_PROVIDER" default:"local" choice:"local" description:"provider which wraps and unwraps encryption keys"`
+	KeyProviderLocalFile       string            `long:"key-provider.local.file" env:"KEY_PROVIDER_LOCAL_FILE" default:"configs/key.json" description:"path to passphrase-protected key file which wraps new keys"`
+	KeyProviderLocalKeyID      string            `long:"key-provider.local.key-id" env:"KEY_PROVIDER_LOCAL_KEY_ID" default:"1" description:"id of the key file which wraps new keys, it's written into wrapped keys"`
+	KeyProviderLocalFiles      map[string]string `long:"key-provider.local.files" env:"KEY_PROVIDER_LOCAL_FILES" env-delim:"," description:"previous key files by id, they unwrap keys until keys are rewrapped, e.g. 1:configs/key.json"`
+	KeyProviderLocalPassphrase string            `long:"key-provider.local.passphrase" env:"KEY_PROVIDER_LOCAL_PASSPHRASE" description:"passphrase of the key files"`
+	EncryptKey                 string            `long:"encrypt-key" env:"ENCRYPT_KEY" description:"deprecated: use encrypt-keys; legacy encrypt key in hex, it has 'legacy' id in encrypt keys"`
+	EncryptKeys                map[string]string `long:"encrypt-keys" env:"ENCRYPT_KEYS" env-delim:"," description:"encrypt keys wrapped by key provider in base64 by id, they decrypt user's data encrypted before users' data keys were introduced, e.g. legacy:<base64>"`
+	KeyRotationInterval        time.Duration     `long:"key-rotation.interval" env:"KEY_ROTATION_INTERVAL" default:"24h" description:"how often to re-encrypt user's data with user's data key and to rewrap data keys with the active key file, 0 disables rotation"`
+}
+
+func mustGetCrypto(kp crypto.KeyProvider, is storage.IndexStorage) crypto.OwnerCrypto {
+	keys := make(map[string][]byte, len(opts.EncryptKeys)+1)
+	for id, key := range opts.EncryptKeys {
+		b, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			logrus.WithError(err).WithField("id", id).Fatal("failed to decode encrypt key")
+		}
+		keys[id] = b
+	}
+
+	if opts.EncryptKey != "" {
+		logrus.Warn("encrypt-key is deprecated, wrap it with keytool and pass it to encrypt-keys with 'legacy' id")
+
+		if _, ok := keys[sio.LegacyKeyID]; ok {
+			logrus.Fatal("legacy key is passed to both encrypt-key and encrypt-keys")
+		}
+		keys[sio.LegacyKeyID] = mustWrapEncryptKey(kp, opts.EncryptKey)
+	}
+
+	// data is encrypted with users' data keys, so the keyring is only used for decryption
+	master, err := sio.NewCrypto(context.Background(), kp, keys, "")
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to create crypto")
+	}
+
+	return envelope.New(master, kp, is)
+}
+
+// mustWrapEncryptKey wraps plain encrypt key in hex, so it could be put into keyring with wrapped keys.
+func mustWrapEncryptKey(kp crypto.KeyProvider, s string) []byte {
+	k, err := hex.DecodeString(s)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to decode encrypt key")
+	}
+
+	if len(k) != 32 {
+		logrus.Fatal("encrypt key must be 32 bytes slice")
+	}
+
+	wrapped, err := kp.WrapKey(context.Background(), k)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to wrap encrypt key")
+	}
+
+	return wrapped
+}
+
+func mustGetKeyProvider() crypto.KeyProvider {
+	switch opts.KeyProvider {
+	default:
+		files := make(map[string]string, len(opts.KeyProviderLocalFiles)+1)
+		for id, path := range opts.KeyProviderLocalFiles {
+			files[id] = path
+		}
+		files[opts.KeyProviderLocalKeyID] = opts.KeyProviderLocalFile
+
+		kp, err := local.NewKeyProvider(files, opts.KeyProviderLocalPassphrase, opts.KeyProviderLocalKeyID)
+		if err != nil {
+			logrus.WithError(err).Fatal("failed to create local key provider")
+		}
+
+		return kp
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Decentr-net/cerberus/internal/hades"
+	"github.com/Decentr-net/cerberus/internal/hades/rules"
+)
+
+type HadesOpts struct {
+	HadesURL               string        `long:"hades.url" env:"HADES_URL"  description:"Hades service url"`
+	HadesTimeout           time.Duration `long:"hades.timeout" env:"HADES_TIMEOUT" default:"10s" description:"maximal Hades request timeout, the timeout is reduced to the half of time left before request deadline"`
+	HadesPolicy            string        `long:"hades.policy" env:"HADES_POLICY" default:"fail-open" choice:"fail-open" choice:"fail-closed" choice:"review" description:"what to do with PDV when Hades fails: save and recheck later, reject, or hold until recheck"`
+	HadesBreakerFailures   int           `long:"hades.breaker.failures" env:"HADES_BREAKER_FAILURES" default:"5" description:"consecutive Hades failures which open circuit breaker, 0 disables circuit breaker"`
+	HadesBreakerCooldown   time.Duration `long:"hades.breaker.cooldown" env:"HADES_BREAKER_COOLDOWN" default:"30s" description:"how long circuit breaker stays open before Hades is probed"`
+	HadesRecheckInterval   time.Duration `long:"hades.recheck-interval" env:"HADES_RECHECK_INTERVAL" default:"1m" description:"how often to recheck PDV which skipped antifraud check"`
+	HadesRulesConfig       string        `long:"hades.rules-config" env:"HADES_RULES_CONFIG" description:"path to built-in antifraud rules config, rules are checked before Hades"`
+	HadesShadowRulesConfig string        `long:"hades.shadow-rules-config" env:"HADES_SHADOW_RULES_CONFIG" description:"path to built-in antifraud rules config checked in shadow mode, verdicts are saved without bans"`
+}
+
+func mustGetHades() hades.Hades {
+	var hh []hades.Hades
+
+	if opts.HadesRulesConfig != "" {
+		r, err := rules.Load(opts.HadesRulesConfig)
+		if err != nil {
+			logrus.WithError(err).Fatal("failed to load hades rules")
+		}
+		hh = append(hh, r)
+	}
+
+	// remote Hades is a second opinion when rules are configured
+	if opts.HadesURL != "" || len(hh) == 0 {
+		h := hades.New(opts.HadesURL, opts.HadesTimeout)
+
+		if opts.HadesBreakerFailures > 0 {
+			h = hades.WithCircuitBreaker(h, opts.HadesBreakerFailures, opts.HadesBreakerCooldown)
+		}
+
+		hh = append(hh, h)
+	}
+
+	return hades.Chain(hh...)
+}
+
+// mustGetShadowHades returns nil if shadow mode is disabled.
+func mustGetShadowHades() hades.Hades {
+	if opts.HadesShadowRulesConfig == "" {
+		return nil
+	}
+
+	r, err := rules.Load(opts.HadesShadowRulesConfig)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to load hades shadow rules")
+	}
+
+	return r
+}
@@ -2,11 +2,8 @@ package main
 
 import (
 	"context"
-	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"os"
 	"os/signal"
@@ -21,10 +18,13 @@ import (
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 
-	"github.com/Decentr-net/cerberus/internal/crypto/sio"
+	"github.com/Decentr-net/cerberus/internal/crypto"
 	"github.com/Decentr-net/cerberus/internal/hades"
 	"github.com/Decentr-net/cerberus/internal/health"
+	"github.com/Decentr-net/cerberus/internal/keyrotation"
+	leaderpg "github.com/Decentr-net/cerberus/internal/leader/postgres"
 	"github.com/Decentr-net/cerberus/internal/producer"
+	"github.com/Decentr-net/cerberus/internal/refine"
 	"github.com/Decentr-net/cerberus/internal/server"
 	"github.com/Decentr-net/cerberus/internal/service"
 	"github.com/Decentr-net/cerberus/internal/storage"
@@ -42,17 +42,27 @@ var opts = struct {
 	SentryDSN string `long:"sentry.dsn" env:"SENTRY_DSN" description:"sentry dsn"`
 	LogLevel  string `long:"log.level" env:"LOG_LEVEL" default:"info" description:"Log level" choice:"debug" choice:"info" choice:"warning" choice:"error"`
 
-	SavePDVThrottlePeriod time.Duration `long:"save-pdv-throttle-period" env:"SAVE_PDV_THROTTLE_PERIOD" default:"10m" description:"how often the user can send PDV to save"`
-	RewardMapConfig       string        `long:"reward-map-config" env:"REWARD_MAP_CONFIG" default:"configs/rewards.yml" description:"path to yaml config with pdv rewards"`
-	MinPDVCount           uint16        `long:"min-pdv-count" env:"MIN_PDV_COUNT" default:"100" description:"minimal count of pdv to save"`
-	MaxPDVCount           uint16        `long:"max-pdv-count" env:"MAX_PDV_COUNT" default:"100" description:"maximal count of pdv to save"`
-	EncryptKey            string        `long:"encrypt-key" env:"ENCRYPT_KEY" description:"encrypt key in hex which will be used for encrypting and decrypting user's data"`
+	SavePDVThrottlePeriod   time.Duration `long:"save-pdv-throttle-period" env:"SAVE_PDV_THROTTLE_PERIOD" default:"10m" description:"how often the user can send PDV to save"`
+	RewardMapConfig         string        `long:"reward-map-config" env:"REWARD_MAP_CONFIG" default:"configs/rewards.yml" description:"path to yaml config with pdv rewards"`
+	RewardMapReloadInterval time.Duration `long:"reward-map-reload-interval" env:"REWARD_MAP_RELOAD_INTERVAL" default:"1m" description:"how often the reward map config is checked for changes, it's also reloaded on SIGHUP"`
+	RefineConfig            string        `long:"refine-config" env:"REFINE_CONFIG" default:"configs/refine.yml" description:"path to yaml config with pdv quality rules"`
+	MinPDVCount             uint16        `long:"min-pdv-count" env:"MIN_PDV_COUNT" default:"100" description:"minimal count of pdv to save"`
+	MaxPDVCount             uint16        `long:"max-pdv-count" env:"MAX_PDV_COUNT" default:"100" description:"maximal count of pdv to save"`
+	FingerprintRetention    time.Duration `long:"pdv-fingerprint-retention" env:"PDV_FINGERPRINT_RETENTION" default:"720h" description:"how long the same data sent by the user isn't rewarded again, 0 disables the check"`
 
 	PDVRewardsPoolSize int64         `long:"pdv-rewards.pool-size" env:"PDV_REWARDS_POOL_SIZE" default:"100000000000" description:"PDV rewards (uDEC)"`
 	PDVRewardsInterval time.Duration `long:"pdv-rewards.interval" env:"PDV_REWARDS_INTERVAL" default:"720h" description:"how often to pay PDV rewards"`
 
-	HadesURL string `long:"hades.url" env:"HADES_URL"  description:"Hades service url"`
+	BlacklistRefreshInterval time.Duration `long:"blacklist.refresh-interval" env:"BLACKLIST_REFRESH_INTERVAL" default:"1m" description:"how often the blacklist is reloaded from the database"`
 
+	LeaderElectionInterval time.Duration `long:"leader-election.interval" env:"LEADER_ELECTION_INTERVAL" default:"10s" description:"how often a follower tries to become the leader which rechecks PDV"`
+
+	AdminTokens map[string]string `long:"admin.tokens" env:"ADMIN_TOKENS" env-delim:"," description:"admin API tokens in name:token format, admin API is disabled if empty"`
+
+	CryptoOpts
+	HadesOpts
+	StorageOpts
+	QueueOpts
 	S3Opts
 	SQSOpts
 	DBOpts
@@ -60,10 +70,18 @@ var opts = struct {
 
 var errTerminated = errors.New("terminated")
 
+const fingerprintCleanupInterval = time.Hour
+
 func main() {
 	parser := flags.NewParser(&opts, flags.Default)
 	parser.ShortDescription = "Cerberus"
 	parser.LongDescription = "Cerberus"
+	parser.SubcommandsOptional = true
+
+	if _, err := parser.AddCommand("shadow-report", "make shadow antifraud report",
+		"Prints shadow antifraud verdicts compared with live outcomes.", &ShadowReportCommand{}); err != nil {
+		logrus.WithError(err).Fatal("failed to add shadow-report command")
+	}
 
 	_, err := parser.Parse()
 
@@ -72,9 +90,17 @@ func main() {
 			parser.WriteHelp(os.Stdout)
 			os.Exit(0)
 		}
+		if parser.Active != nil {
+			os.Exit(1)
+		}
 		logrus.WithError(err).Warn("error occurred while parsing flags")
 	}
 
+	// command is already executed
+	if parser.Active != nil {
+		return
+	}
+
 	lvl, _ := logrus.ParseLevel(opts.LogLevel) // err will always be nil
 	logrus.SetLevel(lvl)
 
@@ -103,19 +129,63 @@ func main() {
 	db := mustGetDB()
 	is := postgres.New(db)
 	fs := mustGetFileStorage()
+	kp := mustGetKeyProvider()
+	c := mustGetCrypto(kp, is)
+
+	h := mustGetHades()
+	p := mustGetProducer(db)
+	blacklist := service.NewBlacklistCache(is)
+	if err := blacklist.Refresh(context.Background()); err != nil {
+		logrus.WithError(err).Fatal("failed to load blacklist")
+	}
+
+	rewards, err := service.LoadRewardSchedule(opts.RewardMapConfig)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to load reward map config")
+	}
 
-	server.SetupRouter(newServiceOrDie(fs, is, mustGetProducer()), r,
+	s := newServiceOrDie(c, fs, is, p, h, mustGetShadowHades(), rewards, blacklist)
+
+	server.SetupRouter(s, r,
 		opts.RequestTimeout, opts.MaxBodySize, throttler.New(opts.SavePDVThrottlePeriod),
 		opts.MinPDVCount, opts.MaxPDVCount,
 		sdk.NewDec(opts.PDVRewardsPoolSize))
-	health.SetupRouter(r, fs, health.PingFunc(db.PingContext))
+	if len(opts.AdminTokens) > 0 {
+		server.SetupAdminRouter(s, r, opts.AdminTokens)
+	} else {
+		logrus.Warn("empty admin tokens, skip admin API initialization")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// only one instance rechecks pdv, deletes expired fingerprints and re-encrypts pdv
+	elector := leaderpg.New(db, "cerberusd")
+	elector.RunAsync(ctx, opts.LeaderElectionInterval)
+
+	health.SetupRouter(r, fs, elector, health.PingFunc(db.PingContext))
 
 	srv := http.Server{
 		Addr:    fmt.Sprintf("%s:%d", opts.Host, opts.Port),
 		Handler: r,
 	}
 
-	gr, _ := errgroup.WithContext(context.Background())
+	blacklist.RunAsync(ctx, opts.BlacklistRefreshInterval)
+	rewards.RunAsync(ctx, opts.RewardMapReloadInterval)
+	reloadRewardsOnSIGHUP(ctx, rewards)
+
+	service.NewFraudRechecker(c, fs, is, p, h, elector).RunAsync(ctx, opts.HadesRecheckInterval)
+
+	if opts.FingerprintRetention > 0 {
+		service.NewFingerprintCleaner(is, elector, opts.FingerprintRetention).RunAsync(ctx, fingerprintCleanupInterval)
+	}
+
+	if opts.KeyRotationInterval > 0 {
+		keyrotation.NewRotator(c, fs, is, elector).RunAsync(ctx, opts.KeyRotationInterval)
+		keyrotation.NewRewrapper(kp, is, elector).RunAsync(ctx, opts.KeyRotationInterval)
+	}
+
+	gr, _ := errgroup.WithContext(ctx)
 	gr.Go(srv.ListenAndServe)
 
 	gr.Go(func() error {
@@ -140,32 +210,48 @@ func main() {
 	}
 }
 
-func newServiceOrDie(fs storage.FileStorage, is storage.IndexStorage, p producer.Producer) service.Service {
-	rewardMap := make(service.RewardMap)
-	b, err := ioutil.ReadFile(opts.RewardMapConfig)
-	if err != nil {
-		logrus.WithError(err).Fatal("failed to read reward map config")
-	}
-	if err := json.Unmarshal(b, &rewardMap); err != nil {
-		logrus.WithError(err).Fatal("failed to unmarshal reward map config")
+func newServiceOrDie(
+	c crypto.OwnerCrypto,
+	fs storage.FileStorage,
+	is storage.IndexStorage,
+	p producer.Producer,
+	h, shadow hades.Hades,
+	rewards *service.RewardSchedule,
+	blacklist *service.BlacklistCache,
+) service.Service {
+	refiner, err := refine.Load(opts.RefineConfig)
+	if errors.Is(err, os.ErrNotExist) {
+		logrus.WithField("path", opts.RefineConfig).Warn("refine config isn't found, default rules are used")
+		refiner, err = refine.New(refine.DefaultConfig)
 	}
-	return service.New(sio.NewCrypto(mustExtractEncryptKey()), fs, is, p,
-		hades.New(opts.HadesURL),
-		rewardMap, opts.PDVRewardsInterval)
-}
-
-func mustExtractEncryptKey() [32]byte {
-	k, err := hex.DecodeString(opts.EncryptKey)
 	if err != nil {
-		logrus.WithError(err).Fatal("failed to decode encrypt key")
+		logrus.WithError(err).Fatal("failed to load refine config")
 	}
 
-	if len(k) != 32 {
-		logrus.Fatal("encrypt key must be 32 bytes slice")
-	}
-
-	r := [32]byte{}
-	copy(r[:], k)
+	return service.New(c, fs, is, p, h, shadow,
+		rewards, refiner, blacklist, opts.PDVRewardsInterval, service.FraudCheckPolicy(opts.HadesPolicy), opts.FingerprintRetention)
+}
 
-	return r
+// reloadRewardsOnSIGHUP reloads the reward map config on SIGHUP until ctx is done.
+func reloadRewardsOnSIGHUP(ctx context.Context, rewards *service.RewardSchedule) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(sigs)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sigs:
+			}
+
+			if err := rewards.Reload(); err != nil {
+				logrus.WithError(err).Error("failed to reload reward map config")
+				continue
+			}
+			logrus.Info("reward map config reloaded")
+		}
+	}()
 }
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"database/sql"
+
+	"github.com/Decentr-net/cerberus/internal/producer"
+	"github.com/Decentr-net/cerberus/internal/producer/postgres"
+)
+
+type QueueOpts struct {
+	QueueDriver string `long:"queue.driver" env:"QUEUE_DRIVER" default:"sqs" choice:"sqs" choice:"postgres" description:"pdv queue driver, postgres uses pdv_outbox table"`
+}
+
+func mustGetProducer(db *sql.DB) producer.Producer {
+	switch opts.QueueDriver {
+	case "postgres":
+		return postgres.New(db)
+	default:
+		return mustGetSQSProducer()
+	}
+}
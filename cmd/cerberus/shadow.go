@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Decentr-net/cerberus/internal/service"
+	"github.com/Decentr-net/cerberus/internal/storage/postgres"
+)
+
+// ShadowReportCommand prints shadow antifraud verdicts compared with live outcomes.
+type ShadowReportCommand struct {
+	Period time.Duration `long:"period" default:"168h" description:"report verdicts made during the period"`
+	Format string        `long:"format" default:"json" choice:"json" choice:"csv" description:"report format, csv report contains only mismatches"`
+	Output string        `long:"output" description:"report file, stdout is used if empty"`
+}
+
+// Execute implements flags.Commander interface.
+func (c *ShadowReportCommand) Execute([]string) error {
+	r, err := service.MakeShadowReport(context.Background(), postgres.New(mustGetDB()), time.Now().UTC().Add(-c.Period))
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if c.Output != "" {
+		f, err := os.Create(c.Output)
+		if err != nil {
+			return fmt.Errorf("failed to create report: %w", err)
+		}
+		defer f.Close() // nolint
+
+		w = f
+	}
+
+	if c.Format == "csv" {
+		return r.WriteCSV(w)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(r)
+}
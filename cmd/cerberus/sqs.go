@@ -18,7 +18,7 @@ type SQSOpts struct {
 	SQSQueue          string `long:"sqs.queue" env:"SQS_QUEUE" default:"testnet" description:"SQS queue name"`
 }
 
-func mustGetProducer() producer.Producer {
+func mustGetSQSProducer() producer.Producer {
 	sess := session.Must(session.NewSession(&aws.Config{
 		Region:      aws.String(opts.SQSRegion),
 		Credentials: credentials.NewStaticCredentials(opts.SQSAccessKeyID, opts.SQSecretAccessKey, ""),
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/Decentr-net/cerberus/internal/storage"
+	"github.com/Decentr-net/cerberus/internal/storage/local"
+)
+
+type StorageOpts struct {
+	StorageDriver    string `long:"storage.driver" env:"STORAGE_DRIVER" default:"s3" choice:"s3" choice:"local" description:"file storage driver"`
+	StorageLocalRoot string `long:"storage.local.root" env:"STORAGE_LOCAL_ROOT" default:"data" description:"root directory for local file storage"`
+}
+
+func mustGetFileStorage() storage.FileStorage {
+	switch opts.StorageDriver {
+	case "local":
+		return mustGetLocalFileStorage()
+	default:
+		return mustGetS3FileStorage()
+	}
+}
+
+func mustGetLocalFileStorage() storage.FileStorage {
+	fs, err := local.NewStorage(opts.StorageLocalRoot)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to create local storage")
+	}
+
+	return fs
+}
@@ -4,8 +4,11 @@ import (
 	cliflags "github.com/cosmos/cosmos-sdk/client/flags"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/sirupsen/logrus"
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
 
 	"github.com/Decentr-net/go-broadcaster"
+
+	"github.com/Decentr-net/cerberus/internal/blockchain"
 )
 
 type BlockchainOpts struct {
@@ -45,3 +48,12 @@ func mustGetBroadcaster() broadcaster.Broadcaster {
 
 	return b
 }
+
+func mustGetBlockchain(b broadcaster.Broadcaster) blockchain.Blockchain {
+	c, err := rpchttp.New(opts.BlockchainNode, "/websocket")
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to create tendermint client")
+	}
+
+	return blockchain.New(b, c)
+}
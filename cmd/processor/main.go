@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/go-chi/chi"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
@@ -18,6 +19,7 @@ import (
 
 	"github.com/Decentr-net/logrus/sentry"
 
+	"github.com/Decentr-net/cerberus/internal/consumer/pdv"
 	"github.com/Decentr-net/cerberus/internal/health"
 	"github.com/Decentr-net/cerberus/internal/storage/postgres"
 )
@@ -28,6 +30,10 @@ var opts = struct {
 	SentryDSN string `long:"sentry.dsn" env:"SENTRY_DSN" description:"sentry dsn"`
 	LogLevel  string `long:"log.level" env:"LOG_LEVEL" default:"info" description:"Log level" choice:"debug" choice:"info" choice:"warning" choice:"error"`
 
+	RewardTxReconcileInterval time.Duration `long:"reward-tx.reconcile-interval" env:"REWARD_TX_RECONCILE_INTERVAL" default:"1m" description:"how often unsettled reward transactions are checked in the blockchain"`
+
+	StorageOpts
+	QueueOpts
 	S3opts
 	SQSOpts
 	DBOpts
@@ -63,7 +69,8 @@ func main() {
 	fs := mustGetFileStorage()
 	is := postgres.New(db)
 	b := mustGetBroadcaster()
-	c := mustGetConsumer(fs, is, b)
+	bc := mustGetBlockchain(b)
+	c := mustGetConsumer(db, fs, is, bc)
 
 	r := chi.NewMux()
 	health.SetupRouter(r, fs, health.PingFunc(b.PingContext), health.PingFunc(db.PingContext))
@@ -75,6 +82,8 @@ func main() {
 	gr, ctx := errgroup.WithContext(context.Background())
 	gr.Go(srv.ListenAndServe)
 
+	pdv.NewReconciler(is, bc).RunAsync(ctx, opts.RewardTxReconcileInterval)
+
 	gr.Go(func() error {
 		sigs := make(chan os.Signal, 1)
 		signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
@@ -91,8 +100,9 @@ func main() {
 	})
 
 	gr.Go(func() error {
+		// consumer finishes processing of received messages before it returns
 		if err := c.Run(ctx); err != nil {
-			if errors.Is(ctx.Err(), errTerminated) {
+			if ctx.Err() != nil {
 				return err
 			}
 
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Decentr-net/cerberus/internal/blockchain"
+	"github.com/Decentr-net/cerberus/internal/consumer"
+	"github.com/Decentr-net/cerberus/internal/consumer/postgres"
+	"github.com/Decentr-net/cerberus/internal/storage"
+)
+
+type QueueOpts struct {
+	QueueDriver string `long:"queue.driver" env:"QUEUE_DRIVER" default:"sqs" choice:"sqs" choice:"postgres" description:"pdv queue driver, postgres uses pdv_outbox table"`
+
+	OutboxMaxAttempts int `long:"outbox.max-attempts" env:"OUTBOX_MAX_ATTEMPTS" default:"10" description:"how many times outbox message could be processed before it's moved to pdv_quarantine table, 0 disables quarantine"`
+	OutboxConcurrency int `long:"outbox.concurrency" env:"OUTBOX_CONCURRENCY" default:"8" description:"count of goroutines storing pdv"`
+	OutboxBatchSize   int `long:"outbox.batch-size" env:"OUTBOX_BATCH_SIZE" default:"10" description:"count of outbox messages processed at once"`
+}
+
+func mustGetConsumer(db *sql.DB, fs storage.FileStorage, is storage.IndexStorage, b blockchain.Blockchain) consumer.Consumer {
+	switch opts.QueueDriver {
+	case "postgres":
+		if opts.OutboxConcurrency <= 0 || opts.OutboxBatchSize <= 0 {
+			logrus.Fatal("outbox.concurrency and outbox.batch-size should be positive")
+		}
+
+		return postgres.New(db, fs, is, b, opts.OutboxMaxAttempts, opts.OutboxConcurrency, opts.OutboxBatchSize)
+	default:
+		return mustGetSQSConsumer(fs, is, b)
+	}
+}
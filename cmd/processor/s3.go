@@ -18,7 +18,7 @@ type S3opts struct {
 	S3Bucket          string `long:"s3.bucket" env:"S3_BUCKET" default:"cerberus" description:"S3 bucket for Cerberus files"`
 }
 
-func mustGetFileStorage() storage.FileStorage {
+func mustGetS3FileStorage() storage.FileStorage {
 	s3client, err := minio.New(opts.S3Endpoint, &minio.Options{
 		Region: opts.S3Region,
 		Creds:  credentials.NewStaticV4(opts.S3AccessKeyID, opts.S3SecretAccessKey, ""),
@@ -1,14 +1,14 @@
 package main
 
 import (
+	"time"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	awssqs "github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/sirupsen/logrus"
 
-	"github.com/Decentr-net/go-broadcaster"
-
 	"github.com/Decentr-net/cerberus/internal/blockchain"
 	"github.com/Decentr-net/cerberus/internal/consumer"
 	"github.com/Decentr-net/cerberus/internal/consumer/sqs"
@@ -16,13 +16,21 @@ import (
 )
 
 type SQSOpts struct {
-	SQSRegion         string `long:"sqs.region" env:"SQS_REGION" default:"" description:"sqs region"`
-	SQSAccessKeyID    string `long:"sqs.access-key-id" env:"SQS_ACCESS_KEY_ID" description:"access key id for SQS"`
-	SQSecretAccessKey string `long:"sqs.secret-access-key" env:"SQS_SECRET_ACCESS_KEY" description:"secret access key for SQS"`
-	SQSQueue          string `long:"sqs.queue" env:"SQS_QUEUE" default:"testnet" description:"SQS queue name"`
+	SQSRegion          string        `long:"sqs.region" env:"SQS_REGION" default:"" description:"sqs region"`
+	SQSAccessKeyID     string        `long:"sqs.access-key-id" env:"SQS_ACCESS_KEY_ID" description:"access key id for SQS"`
+	SQSecretAccessKey  string        `long:"sqs.secret-access-key" env:"SQS_SECRET_ACCESS_KEY" description:"secret access key for SQS"`
+	SQSQueue           string        `long:"sqs.queue" env:"SQS_QUEUE" default:"testnet" description:"SQS queue name"`
+	SQSMaxReceiveCount int           `long:"sqs.max-receive-count" env:"SQS_MAX_RECEIVE_COUNT" default:"10" description:"how many times message could be received before it's moved to pdv_quarantine table, 0 disables quarantine"`
+	SQSConcurrency     int           `long:"sqs.concurrency" env:"SQS_CONCURRENCY" default:"8" description:"count of goroutines storing pdv"`
+	SQSBatchSize       int           `long:"sqs.batch-size" env:"SQS_BATCH_SIZE" default:"10" description:"count of messages processed at once"`
+	SQSShutdownTimeout time.Duration `long:"sqs.shutdown-timeout" env:"SQS_SHUTDOWN_TIMEOUT" default:"30s" description:"how long the current batch could be processed after termination"`
 }
 
-func mustGetConsumer(fs storage.FileStorage, is storage.IndexStorage, b broadcaster.Broadcaster) consumer.Consumer {
+func mustGetSQSConsumer(fs storage.FileStorage, is storage.IndexStorage, b blockchain.Blockchain) consumer.Consumer {
+	if opts.SQSConcurrency <= 0 || opts.SQSBatchSize <= 0 {
+		logrus.Fatal("sqs.concurrency and sqs.batch-size should be positive")
+	}
+
 	sess := session.Must(session.NewSession(&aws.Config{
 		Region:      aws.String(opts.SQSRegion),
 		Credentials: credentials.NewStaticCredentials(opts.SQSAccessKeyID, opts.SQSecretAccessKey, ""),
@@ -36,5 +44,6 @@ func mustGetConsumer(fs storage.FileStorage, is storage.IndexStorage, b broadcas
 		logrus.WithError(err).Fatal("failed to get queue url")
 	}
 
-	return sqs.New(fs, is, blockchain.New(b), c, *queue.QueueUrl)
+	return sqs.New(fs, is, b, c, *queue.QueueUrl,
+		opts.SQSMaxReceiveCount, opts.SQSConcurrency, opts.SQSBatchSize, opts.SQSShutdownTimeout)
 }
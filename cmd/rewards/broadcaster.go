@@ -4,13 +4,16 @@ import (
 	cliflags "github.com/cosmos/cosmos-sdk/client/flags"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/sirupsen/logrus"
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
 
 	"github.com/Decentr-net/go-broadcaster"
+
+	"github.com/Decentr-net/cerberus/internal/blockchain"
 )
 
 type BlockchainOpts struct {
 	BlockchainNode               string `long:"blockchain.node" env:"BLOCKCHAIN_NODE" default:"http://zeus.testnet.decentr.xyz:26657" description:"decentr node address"`
-	BlockchainFrom               string `long:"blockchain.from" env:"BLOCKCHAIN_FROM" description:"decentr account name to send stakes" required:"true"`
+	BlockchainFrom               string `long:"blockchain.from" env:"BLOCKCHAIN_FROM" description:"decentr account name to send stakes, required by the daemon"`
 	BlockchainTxMemo             string `long:"blockchain.tx_memo" env:"BLOCKCHAIN_TX_MEMO" description:"decentr tx's memo'"`
 	BlockchainChainID            string `long:"blockchain.chain_id" env:"BLOCKCHAIN_CHAIN_ID" default:"testnet" description:"decentr chain id"`
 	BlockchainClientHome         string `long:"blockchain.client_home" env:"BLOCKCHAIN_CLIENT_HOME" default:"~/.decentrcli" description:"decentrcli home directory"`
@@ -21,6 +24,10 @@ type BlockchainOpts struct {
 }
 
 func mustGetBroadcaster() broadcaster.Broadcaster {
+	if opts.BlockchainFrom == "" {
+		logrus.Fatal("blockchain.from is required")
+	}
+
 	fee, err := sdk.ParseCoinNormalized(opts.BlockchainFee)
 	if err != nil {
 		logrus.WithError(err).Error("failed to parse fee")
@@ -45,3 +52,12 @@ func mustGetBroadcaster() broadcaster.Broadcaster {
 
 	return b
 }
+
+func mustGetBlockchain(b broadcaster.Broadcaster) blockchain.Blockchain {
+	c, err := rpchttp.New(opts.BlockchainNode, "/websocket")
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to create tendermint client")
+	}
+
+	return blockchain.New(b, c)
+}
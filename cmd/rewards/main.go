@@ -3,31 +3,39 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/go-chi/chi"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/jessevdk/go-flags"
 	_ "github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 
-	"github.com/Decentr-net/cerberus/internal/blockchain"
 	"github.com/Decentr-net/cerberus/internal/health"
+	leaderpg "github.com/Decentr-net/cerberus/internal/leader/postgres"
 	"github.com/Decentr-net/cerberus/internal/pdvrewards"
 	"github.com/Decentr-net/cerberus/internal/storage/postgres"
 	"github.com/Decentr-net/logrus/sentry"
 )
 
 var opts = struct {
+	Host      string `long:"http.host" env:"HTTP_HOST" default:"localhost" description:"IP to listen on"`
+	Port      int    `long:"http.port" env:"HTTP_PORT" default:"8080" description:"port to listen on for insecure connections, defaults to a random value"`
 	SentryDSN string `long:"sentry.dsn" env:"SENTRY_DSN" description:"sentry dsn"`
 	LogLevel  string `long:"log.level" env:"LOG_LEVEL" default:"info" description:"Log level" choice:"debug" choice:"info" choice:"warning" choice:"error"`
 
 	PDVRewardsPoolSize int64         `long:"pdv-rewards.pool-size" env:"PDV_REWARDS_POOL_SIZE" default:"100000000000" description:"PDV rewards (uDEC)"`
 	PDVRewardsInterval time.Duration `long:"pdv-rewards.interval" env:"PDV_REWARDS_INTERVAL" default:"720h" description:"how often to pay PDV rewards"`
+	PDVRewardsManual   bool          `long:"pdv-rewards.manual-approve" env:"PDV_REWARDS_MANUAL_APPROVE" description:"distribute only rewards approved with approve command"`
+	PDVRewardsPolicy   string        `long:"pdv-rewards.policy-config" env:"PDV_REWARDS_POLICY_CONFIG" default:"configs/rewards_policy.yml" description:"path to yaml config with PDV rewards policy"`
+
+	LeaderElectionInterval time.Duration `long:"leader-election.interval" env:"LEADER_ELECTION_INTERVAL" default:"10s" description:"how often a follower tries to become the leader which distributes rewards"`
 
 	DBOpts
 	BlockchainOpts
@@ -39,17 +47,43 @@ func main() {
 	parser := flags.NewParser(&opts, flags.Default)
 	parser.ShortDescription = "Rewards"
 	parser.LongDescription = "Rewards"
+	parser.SubcommandsOptional = true
 
-	_, err := parser.Parse()
+	if _, err := parser.AddCommand("plan", "make rewards plan", "Prints the next rewards payout.", &PlanCommand{}); err != nil {
+		logrus.WithError(err).Fatal("failed to add plan command")
+	}
+	if _, err := parser.AddCommand("approve", "approve rewards plan", "Puts rewards from the plan into the queue.", &ApproveCommand{}); err != nil {
+		logrus.WithError(err).Fatal("failed to add approve command")
+	}
+	rewards, err := parser.AddCommand("rewards", "manage rewards queue", "Lists rewards queue and resolves rewards which broadcast result is unknown.", &RewardsCommand{})
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to add rewards command")
+	}
+	if _, err := rewards.AddCommand("list", "list rewards queue", "Prints rewards queue with sending state.", &RewardsListCommand{}); err != nil {
+		logrus.WithError(err).Fatal("failed to add rewards list command")
+	}
+	if _, err := rewards.AddCommand("resolve", "resolve rewards", "Settles rewards by stakes tx or resets them to be sent again.", &RewardsResolveCommand{}); err != nil {
+		logrus.WithError(err).Fatal("failed to add rewards resolve command")
+	}
+
+	_, err = parser.Parse()
 
 	if err != nil {
 		if flagsErr, ok := err.(*flags.Error); ok && flagsErr.Type == flags.ErrHelp {
 			parser.WriteHelp(os.Stdout)
 			os.Exit(0)
 		}
+		if parser.Active != nil {
+			os.Exit(1)
+		}
 		logrus.WithError(err).Warn("error occurred while parsing flags")
 	}
 
+	// command is already executed
+	if parser.Active != nil {
+		return
+	}
+
 	lvl, _ := logrus.ParseLevel(opts.LogLevel) // err will always be nil
 	logrus.SetLevel(lvl)
 
@@ -63,6 +97,19 @@ func main() {
 
 	gr, ctx := errgroup.WithContext(context.Background())
 
+	// only one instance prepares and distributes rewards
+	elector := leaderpg.New(db, "rewardsd")
+	elector.RunAsync(ctx, opts.LeaderElectionInterval)
+
+	r := chi.NewMux()
+	health.SetupRouter(r, elector, health.PingFunc(b.PingContext), health.PingFunc(db.PingContext))
+	srv := http.Server{
+		Addr:    fmt.Sprintf("%s:%d", opts.Host, opts.Port),
+		Handler: r,
+	}
+
+	gr.Go(srv.ListenAndServe)
+
 	gr.Go(func() error {
 		sigs := make(chan os.Signal, 1)
 		signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
@@ -71,12 +118,16 @@ func main() {
 
 		logrus.Infof("terminating by %s signal", s)
 
+		if err := srv.Shutdown(context.Background()); err != nil {
+			logrus.WithError(err).Error("failed to gracefully shutdown server")
+		}
+
 		return errTerminated
 	})
 
 	gr.Go(func() error {
 		distributor := pdvrewards.NewDistributor(
-			opts.PDVRewardsPoolSize, blockchain.New(b), postgres.New(db))
+			opts.PDVRewardsPoolSize, mustGetPolicy(), opts.PDVRewardsManual, elector, mustGetBlockchain(b), postgres.New(db))
 		distributor.RunAsync(ctx, opts.PDVRewardsInterval)
 
 		return nil
@@ -89,6 +140,15 @@ func main() {
 	}
 }
 
+func mustGetPolicy() pdvrewards.RewardPolicy {
+	p, err := pdvrewards.LoadPolicy(opts.PDVRewardsPolicy)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to load rewards policy")
+	}
+
+	return p
+}
+
 func setupLogger() {
 	if opts.SentryDSN != "" {
 		hook, err := sentry.NewHook(sentry.Options{
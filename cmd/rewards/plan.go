@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Decentr-net/cerberus/internal/pdvrewards"
+	"github.com/Decentr-net/cerberus/internal/storage/postgres"
+)
+
+// PlanCommand prints the next rewards payout.
+type PlanCommand struct {
+	Format string `long:"format" default:"json" choice:"json" choice:"csv" description:"report format, only json report can be approved"`
+	Output string `long:"output" description:"report file, stdout is used if empty"`
+}
+
+// Execute implements flags.Commander interface.
+func (c *PlanCommand) Execute([]string) error {
+	p, err := pdvrewards.MakePlan(context.Background(), postgres.New(mustGetDB()), mustGetPolicy(), opts.PDVRewardsPoolSize)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if c.Output != "" {
+		f, err := os.Create(c.Output)
+		if err != nil {
+			return fmt.Errorf("failed to create report: %w", err)
+		}
+		defer f.Close() // nolint
+
+		w = f
+	}
+
+	if c.Format == "csv" {
+		return p.WriteCSV(w)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(p)
+}
+
+// ApproveCommand puts rewards from the plan into the queue.
+type ApproveCommand struct {
+	Plan string `long:"plan" required:"true" description:"json report made by plan command"`
+}
+
+// Execute implements flags.Commander interface.
+func (c *ApproveCommand) Execute([]string) error {
+	b, err := os.ReadFile(c.Plan)
+	if err != nil {
+		return fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	var p pdvrewards.Plan
+	if err := json.Unmarshal(b, &p); err != nil {
+		return fmt.Errorf("failed to unmarshal plan: %w", err)
+	}
+
+	if err := pdvrewards.ApprovePlan(context.Background(), postgres.New(mustGetDB()), &p); err != nil {
+		return err
+	}
+
+	logrus.Infof("plan with %d items is approved", len(p.Items))
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Decentr-net/cerberus/internal/pdvrewards"
+	"github.com/Decentr-net/cerberus/internal/storage/postgres"
+)
+
+// RewardsCommand manages rewards queue.
+type RewardsCommand struct{}
+
+// RewardsListCommand prints rewards queue.
+type RewardsListCommand struct{}
+
+// Execute implements flags.Commander interface.
+func (c *RewardsListCommand) Execute([]string) error {
+	items, err := postgres.New(mustGetDB()).GetRewardsQueueItemList(context.Background())
+	if err != nil {
+		return err
+	}
+
+	for _, v := range items {
+		state, sentAt := "queued", ""
+		if v.SentAt != nil {
+			state, sentAt = "sent", v.SentAt.Format("2006-01-02 15:04:05")
+			if v.TxHash == "" {
+				state = "unknown"
+			}
+		}
+
+		fmt.Printf("%s\t%d\t%s\t%s\t%s\n", v.Address, v.Reward, state, sentAt, v.TxHash)
+	}
+
+	return nil
+}
+
+// RewardsResolveCommand resolves rewards which broadcast result is unknown.
+type RewardsResolveCommand struct {
+	Addresses []string `long:"address" required:"true" description:"address of reward which broadcast result is unknown"`
+	Tx        string   `long:"tx" description:"hash of stakes tx which sent the rewards, rewards are settled by it"`
+	Reset     bool     `long:"reset" description:"rewards aren't sent, they are sent again by the next run"`
+}
+
+// Execute implements flags.Commander interface.
+func (c *RewardsResolveCommand) Execute([]string) error {
+	if (c.Tx == "") == !c.Reset {
+		return errors.New("either --tx or --reset should be specified")
+	}
+
+	if err := pdvrewards.ResolveRewards(context.Background(), postgres.New(mustGetDB()), c.Addresses, c.Tx); err != nil {
+		return err
+	}
+
+	logrus.Infof("%d rewards are resolved", len(c.Addresses))
+
+	return nil
+}
@@ -33,6 +33,8 @@ import (
 )
 
 var opts = struct {
+	StorageOpts
+
 	S3Endpoint        string `long:"s3.endpoint" env:"S3_ENDPOINT" default:"localhost:9000" description:"s3 endpoint'"`
 	S3Region          string `long:"s3.region" env:"S3_REGION" default:"" description:"s3 region"`
 	S3AccessKeyID     string `long:"s3.access-key-id" env:"S3_ACCESS_KEY_ID" description:"access key id for S3 storage'"`
@@ -95,20 +97,7 @@ func main() {
 		logrus.Warn("skip sentry initialization")
 	}
 
-	s3client, err := minio.New(opts.S3Endpoint, &minio.Options{
-		Region: opts.S3Region,
-		Creds:  credentials.NewStaticV4(opts.S3AccessKeyID, opts.S3SecretAccessKey, ""),
-		Secure: opts.S3UseSSL,
-	})
-	if err != nil {
-		logrus.WithError(err).Fatal("failed to connect to S3 storage")
-	}
-
-	fs, err := s3.NewStorage(s3client, opts.S3Bucket)
-	if err != nil {
-		logrus.WithError(err).Fatal("failed to create storage")
-	}
-
+	fs := mustGetFileStorage()
 	db := mustGetDB()
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -181,6 +170,24 @@ func mustGetDB() *sql.DB {
 	return db
 }
 
+func mustGetS3FileStorage() storage.FileStorage {
+	s3client, err := minio.New(opts.S3Endpoint, &minio.Options{
+		Region: opts.S3Region,
+		Creds:  credentials.NewStaticV4(opts.S3AccessKeyID, opts.S3SecretAccessKey, ""),
+		Secure: opts.S3UseSSL,
+	})
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to connect to S3 storage")
+	}
+
+	fs, err := s3.NewStorage(s3client, opts.S3Bucket)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to create storage")
+	}
+
+	return fs
+}
+
 func mustGetConsumer(fs storage.FileStorage, is storage.IndexStorage) consumer.Consumer {
 	fetcher, err := ariadne.New(context.Background(), opts.BlockchainNode, opts.BlockchainTimeout)
 	if err != nil {
@@ -2,15 +2,19 @@
 package blockchain
 
 import (
+	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
-	"github.com/avast/retry-go"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
 
 	"github.com/Decentr-net/decentr/config"
 	operationstypes "github.com/Decentr-net/decentr/x/operations/types"
@@ -26,10 +30,36 @@ var _ Blockchain = &blockchain{}
 
 var log = logrus.WithField("package", "blockchain")
 
+const (
+	// how often tx status is checked while waiting for tx
+	txPollInterval = time.Second
+	// tendermint responds unknown tx with json-rpc internal error
+	rpcInternalErrorCode = -32603
+	// how many latest broadcaster's transactions are looked through while searching tx by memo
+	txSearchPageSize = 100
+	txSearchPages    = 5
+)
+
 //go:generate mockgen -destination=./mock/blockchain.go -package=mock -source=blockchain.go
 
-// ErrInvalidAddress is returned when address is invalid. It is unexpected situation.
-var ErrInvalidAddress = errors.New("invalid address")
+var (
+	// ErrInvalidAddress is returned when address is invalid. It is unexpected situation, tx isn't broadcast.
+	ErrInvalidAddress = errors.New("invalid address")
+	// ErrInvalidMsg is returned when message doesn't pass basic validation. Tx isn't broadcast.
+	ErrInvalidMsg = errors.New("invalid msg")
+)
+
+// TxStatus is a status of transaction in the blockchain.
+type TxStatus int
+
+const (
+	// TxNotFound means that transaction isn't included into the blockchain.
+	TxNotFound TxStatus = iota
+	// TxSucceeded means that transaction is included into the blockchain and is executed successfully.
+	TxSucceeded
+	// TxFailed means that transaction is included into the blockchain but its execution is failed.
+	TxFailed
+)
 
 // Reward is a copy of operations.Reward but with string receiver instead of sdk.AccAddress.
 type Reward struct {
@@ -46,28 +76,40 @@ type Stake struct {
 
 // Blockchain is interface for interacting with the blockchain.
 type Blockchain interface {
-	DistributeRewards(rewards []Reward) (tx string, err error)
-	SendStakes(stakes []Stake, memo string) error
+	DistributeRewards(rewards []Reward, memo string) (tx string, err error)
+	SendStakes(stakes []Stake, memo string) (tx string, err error)
+	GetTxStatus(ctx context.Context, hash string) (TxStatus, error)
+	WaitForTx(ctx context.Context, hash string) (TxStatus, error)
+	FindTx(ctx context.Context, memo string) (hash string, status TxStatus, err error)
+}
+
+// IsNotBroadcast checks if err is returned before broadcast, so tx can't be included into the blockchain.
+// Other errors leave broadcast result unknown.
+func IsNotBroadcast(err error) bool {
+	return errors.Is(err, ErrInvalidAddress) || errors.Is(err, ErrInvalidMsg)
 }
 
 type blockchain struct {
 	b broadcaster.Broadcaster
+	c rpcclient.SignClient
 }
 
 // New returns new instance of Blockchain.
-func New(b broadcaster.Broadcaster) *blockchain { // nolint:golint
+func New(b broadcaster.Broadcaster, c rpcclient.SignClient) *blockchain { // nolint:golint
 	return &blockchain{
 		b: b,
+		c: c,
 	}
 }
 
-func (b blockchain) DistributeRewards(rewards []Reward) (string, error) {
+// DistributeRewards broadcasts MsgDistributeRewards with memo. Memo allows to find tx if broadcast result is lost.
+func (b blockchain) DistributeRewards(rewards []Reward, memo string) (string, error) {
 	rr := make([]operationstypes.Reward, len(rewards))
 
 	for i, v := range rewards { // nolint:gocritic
 		owner, err := sdk.AccAddressFromBech32(v.Receiver)
 		if err != nil {
-			return "", fmt.Errorf("invalid receiver: %w", err)
+			return "", fmt.Errorf("%w: %s", ErrInvalidAddress, v.Receiver)
 		}
 
 		rr[i] = operationstypes.Reward{
@@ -78,7 +120,7 @@ func (b blockchain) DistributeRewards(rewards []Reward) (string, error) {
 
 	msg := operationstypes.NewMsgDistributeRewards(b.b.From(), rr)
 	if err := msg.ValidateBasic(); err != nil {
-		return "", err
+		return "", fmt.Errorf("%w: %s", ErrInvalidMsg, err)
 	}
 
 	log := log.WithFields(logrus.Fields{
@@ -87,46 +129,144 @@ func (b blockchain) DistributeRewards(rewards []Reward) (string, error) {
 
 	log.Info("DistributeRewards")
 
-	var resp *sdk.TxResponse
-	if err := retry.Do(func() error {
-		var err1 error
-		resp, err1 = b.b.BroadcastMsg(&msg, "")
-		if err1 != nil {
-			log.WithError(err1).Warn("attempt to  broadcast MsgDistributeRewards failed")
-		}
-		return err1
-	}, retry.Attempts(3), retry.Delay(200*time.Millisecond)); err != nil {
+	// broadcast isn't retried: tx could reach the node even if error is returned, so retry could reward twice.
+	// Failed transactions are settled by reward tx ledger.
+	resp, err := b.b.BroadcastMsg(&msg, memo)
+	if err != nil {
 		return "", fmt.Errorf("failed to broadcast MsgDistributeRewards: %w", err)
 	}
 
 	return resp.TxHash, nil
 }
 
-// SendStakes ...
-func (b blockchain) SendStakes(stakes []Stake, memo string) error {
-	sendStakes := func() error {
-		messages := make([]sdk.Msg, len(stakes))
-		for idx, stake := range stakes {
-			to, err := sdk.AccAddressFromBech32(stake.Address)
+// SendStakes sends stakes in one tx. Broadcast isn't retried: tx could reach the node even if error is returned,
+// so caller should settle the result instead of sending stakes again.
+func (b blockchain) SendStakes(stakes []Stake, memo string) (string, error) {
+	messages := make([]sdk.Msg, len(stakes))
+	for idx, stake := range stakes {
+		to, err := sdk.AccAddressFromBech32(stake.Address)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", ErrInvalidAddress, stake.Address)
+		}
+
+		messages[idx] = banktypes.NewMsgSend(b.b.From(), to, sdk.Coins{sdk.Coin{
+			Denom:  config.DefaultBondDenom,
+			Amount: stake.Amount,
+		}})
+		if err := messages[idx].ValidateBasic(); err != nil {
+			return "", fmt.Errorf("%w: %s", ErrInvalidMsg, err)
+		}
+	}
+
+	resp, err := b.b.Broadcast(messages, memo)
+	if err != nil {
+		return "", fmt.Errorf("failed to broadcast msg: %w", err)
+	}
+
+	return resp.TxHash, nil
+}
+
+// GetTxStatus returns status of transaction by its hash.
+func (b blockchain) GetTxStatus(ctx context.Context, hash string) (TxStatus, error) {
+	h, err := hex.DecodeString(hash)
+	if err != nil {
+		return TxNotFound, fmt.Errorf("invalid hash: %w", err)
+	}
+
+	res, err := b.c.Tx(ctx, h, false)
+	if err != nil {
+		if isTxNotFound(err, h) {
+			return TxNotFound, nil
+		}
+		return TxNotFound, fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	if res.TxResult.Code != 0 {
+		return TxFailed, nil
+	}
+
+	return TxSucceeded, nil
+}
+
+// WaitForTx waits until transaction is included into the blockchain or ctx is done.
+// TxNotFound is returned with ctx error if transaction isn't included in time.
+func (b blockchain) WaitForTx(ctx context.Context, hash string) (TxStatus, error) {
+	ticker := time.NewTicker(txPollInterval)
+	defer ticker.Stop()
+
+	for {
+		status, err := b.GetTxStatus(ctx, hash)
+		if err != nil {
+			log.WithError(err).WithField("tx", hash).Warn("failed to get tx status")
+		} else if status != TxNotFound {
+			return status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return TxNotFound, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// FindTx looks for the latest broadcaster's transaction with memo. TxNotFound is returned if there is no such tx.
+func (b blockchain) FindTx(ctx context.Context, memo string) (string, TxStatus, error) {
+	query := fmt.Sprintf("message.sender='%s'", b.b.From())
+	perPage := txSearchPageSize
+
+	for page := 1; page <= txSearchPages; page++ {
+		res, err := b.c.TxSearch(ctx, query, false, &page, &perPage, "desc")
+		if err != nil {
+			return "", TxNotFound, fmt.Errorf("failed to search txs: %w", err)
+		}
+
+		for _, v := range res.Txs {
+			m, err := getMemo(v.Tx)
 			if err != nil {
-				return fmt.Errorf("%w: %s", ErrInvalidAddress, stake.Address)
+				log.WithError(err).WithField("tx", v.Hash.String()).Warn("failed to get tx memo")
+				continue
+			}
+
+			if m != memo {
+				continue
 			}
 
-			messages[idx] = banktypes.NewMsgSend(b.b.From(), to, sdk.Coins{sdk.Coin{
-				Denom:  config.DefaultBondDenom,
-				Amount: stake.Amount,
-			}})
-			if err := messages[idx].ValidateBasic(); err != nil {
-				return err
+			if v.TxResult.Code != 0 {
+				return v.Hash.String(), TxFailed, nil
 			}
+			return v.Hash.String(), TxSucceeded, nil
 		}
 
-		if _, err := b.b.Broadcast(messages, memo); err != nil {
-			return fmt.Errorf("failed to broadcast msg: %w", err)
+		if page*perPage >= res.TotalCount {
+			break
 		}
+	}
+
+	return "", TxNotFound, nil
+}
+
+// getMemo decodes memo of raw tx. Messages aren't unpacked, so tx of any type can be decoded.
+func getMemo(tx []byte) (string, error) {
+	var raw txtypes.TxRaw
+	if err := raw.Unmarshal(tx); err != nil {
+		return "", fmt.Errorf("failed to unmarshal tx: %w", err)
+	}
+
+	var body txtypes.TxBody
+	if err := body.Unmarshal(raw.BodyBytes); err != nil {
+		return "", fmt.Errorf("failed to unmarshal tx body: %w", err)
+	}
+
+	return body.Memo, nil
+}
 
-		return nil
+// isTxNotFound checks if err is tendermint's internal error about unknown tx.
+func isTxNotFound(err error, hash []byte) bool {
+	var rpcErr *rpctypes.RPCError
+	if !errors.As(err, &rpcErr) {
+		return false
 	}
 
-	return retry.Do(sendStakes, retry.Attempts(5), retry.Delay(time.Second))
+	return rpcErr.Code == rpcInternalErrorCode && rpcErr.Data == fmt.Sprintf("tx (%X) not found", hash)
 }
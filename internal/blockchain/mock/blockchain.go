@@ -5,6 +5,7 @@
 package mock
 
 import (
+	context "context"
 	blockchain "github.com/Decentr-net/cerberus/internal/blockchain"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
@@ -34,26 +35,27 @@ func (m *MockBlockchain) EXPECT() *MockBlockchainMockRecorder {
 }
 
 // DistributeRewards mocks base method
-func (m *MockBlockchain) DistributeRewards(rewards []blockchain.Reward) (string, error) {
+func (m *MockBlockchain) DistributeRewards(rewards []blockchain.Reward, memo string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DistributeRewards", rewards)
+	ret := m.ctrl.Call(m, "DistributeRewards", rewards, memo)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DistributeRewards indicates an expected call of DistributeRewards
-func (mr *MockBlockchainMockRecorder) DistributeRewards(rewards interface{}) *gomock.Call {
+func (mr *MockBlockchainMockRecorder) DistributeRewards(rewards, memo interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributeRewards", reflect.TypeOf((*MockBlockchain)(nil).DistributeRewards), rewards)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributeRewards", reflect.TypeOf((*MockBlockchain)(nil).DistributeRewards), rewards, memo)
 }
 
 // SendStakes mocks base method
-func (m *MockBlockchain) SendStakes(stakes []blockchain.Stake, memo string) error {
+func (m *MockBlockchain) SendStakes(stakes []blockchain.Stake, memo string) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SendStakes", stakes, memo)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // SendStakes indicates an expected call of SendStakes
@@ -61,3 +63,49 @@ func (mr *MockBlockchainMockRecorder) SendStakes(stakes, memo interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendStakes", reflect.TypeOf((*MockBlockchain)(nil).SendStakes), stakes, memo)
 }
+
+// GetTxStatus mocks base method
+func (m *MockBlockchain) GetTxStatus(ctx context.Context, hash string) (blockchain.TxStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTxStatus", ctx, hash)
+	ret0, _ := ret[0].(blockchain.TxStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTxStatus indicates an expected call of GetTxStatus
+func (mr *MockBlockchainMockRecorder) GetTxStatus(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTxStatus", reflect.TypeOf((*MockBlockchain)(nil).GetTxStatus), ctx, hash)
+}
+
+// WaitForTx mocks base method
+func (m *MockBlockchain) WaitForTx(ctx context.Context, hash string) (blockchain.TxStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForTx", ctx, hash)
+	ret0, _ := ret[0].(blockchain.TxStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForTx indicates an expected call of WaitForTx
+func (mr *MockBlockchainMockRecorder) WaitForTx(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForTx", reflect.TypeOf((*MockBlockchain)(nil).WaitForTx), ctx, hash)
+}
+
+// FindTx mocks base method
+func (m *MockBlockchain) FindTx(ctx context.Context, memo string) (string, blockchain.TxStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTx", ctx, memo)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(blockchain.TxStatus)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindTx indicates an expected call of FindTx
+func (mr *MockBlockchainMockRecorder) FindTx(ctx, memo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTx", reflect.TypeOf((*MockBlockchain)(nil).FindTx), ctx, memo)
+}
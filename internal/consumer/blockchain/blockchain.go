@@ -103,6 +103,11 @@ func processMsgResetAccount(ctx context.Context, is storage.IndexStorage, fs sto
 		return fmt.Errorf("failed to delete index: %w", err)
 	}
 
+	// data can't be decrypted without the key, so it's removed even if files deletion fails
+	if err := is.DeleteDataKey(ctx, msg.Address); err != nil {
+		return fmt.Errorf("failed to delete data key: %w", err)
+	}
+
 	go func() {
 		if err := fs.DeleteData(ctx, msg.Address); err != nil {
 			logrus.WithError(err).WithField("account", msg.Address).Error("failed to delete data")
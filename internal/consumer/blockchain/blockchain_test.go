@@ -75,6 +75,7 @@ func TestBlockchain_processBlockFunc(t *testing.T) {
 				fs.EXPECT().DeleteData(gomock.Any(), owner2.String()).Return(nil)
 				is.EXPECT().DeletePDV(gomock.Any(), owner2.String()).Return(nil)
 				is.EXPECT().DeleteProfile(gomock.Any(), owner2.String()).Return(nil)
+				is.EXPECT().DeleteDataKey(gomock.Any(), owner2.String()).Return(nil)
 			},
 		},
 	}
@@ -0,0 +1,254 @@
+// Package pdv contains PDV messages processing which is shared by consumers.
+package pdv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Decentr-net/cerberus/internal/blockchain"
+	"github.com/Decentr-net/cerberus/internal/producer"
+	"github.com/Decentr-net/cerberus/internal/storage"
+)
+
+var log = logrus.WithField("package", "pdv")
+
+var errTxFailed = errors.New("reward tx is failed")
+
+// how long processor waits for reward tx to be included into the blockchain
+const txWaitTimeout = time.Minute
+
+// Processor stores PDV and distributes rewards for it.
+type Processor struct {
+	fs storage.FileStorage
+	is storage.IndexStorage
+	b  blockchain.Blockchain
+
+	concurrency int
+}
+
+// NewProcessor creates a new instance of Processor which stores PDV using concurrency goroutines.
+func NewProcessor(fs storage.FileStorage, is storage.IndexStorage, b blockchain.Blockchain, concurrency int) *Processor {
+	return &Processor{
+		fs: fs,
+		is: is,
+		b:  b,
+
+		concurrency: concurrency,
+	}
+}
+
+// Process stores PDV data and distributes rewards for new PDV.
+// It returns processing error for every message, message can be removed from the queue if its error is nil.
+// Nothing should be removed from the queue when error is returned.
+func (p *Processor) Process(ctx context.Context, msgs []*producer.PDVMessage) ([]error, error) {
+	if len(msgs) == 0 {
+		return nil, nil
+	}
+
+	var (
+		errs     = make([]error, len(msgs))
+		toReward []*producer.PDVMessage
+
+		mu sync.Mutex
+	)
+
+	parallel(p.concurrency, func(idx int) {
+		pdv := msgs[idx]
+
+		stored, err := p.storePDV(ctx, pdv)
+		if err == nil && stored && pdv.Meta.Reward.IsPositive() {
+			// pdv could be already rewarded if the message is redelivered
+			var rewarded bool
+			if rewarded, err = p.is.HasActiveRewardTx(ctx, pdv.Address, pdv.ID); err != nil {
+				err = fmt.Errorf("failed to check reward tx: %w", err)
+			} else if rewarded {
+				log.WithField("id", pdv.ID).Info("pdv is already being rewarded")
+			}
+
+			mu.Lock()
+			if err == nil && !rewarded {
+				toReward = append(toReward, pdv)
+			}
+			mu.Unlock()
+		}
+
+		errs[idx] = err
+	}, len(msgs))
+
+	if len(toReward) > 0 {
+		if err := p.reward(ctx, toReward); err != nil {
+			return nil, fmt.Errorf("failed to process messages bulk: %w", err)
+		}
+	}
+
+	return errs, nil
+}
+
+// reward distributes rewards for pdv. Transaction is written into the ledger before broadcast,
+// so redelivered messages are not rewarded twice and transactions with unknown result can be reconciled.
+func (p *Processor) reward(ctx context.Context, msgs []*producer.PDVMessage) error {
+	var (
+		items = make([]*storage.RewardTxItem, len(msgs))
+		rr    = make([]blockchain.Reward, len(msgs))
+	)
+
+	for i, v := range msgs {
+		items[i] = &storage.RewardTxItem{
+			Address: v.Address,
+			ID:      v.ID,
+			Device:  v.Device,
+			Meta:    v.Meta,
+		}
+		rr[i] = blockchain.Reward{
+			Receiver: v.Address,
+			ID:       v.ID,
+			Reward:   v.Meta.Reward,
+		}
+	}
+
+	var id uint64
+	if err := p.is.InTx(ctx, func(s storage.IndexStorage) error {
+		var err error
+		id, err = s.CreateRewardTx(ctx, items)
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to create reward tx: %w", err)
+	}
+
+	log := log.WithField("reward_tx", id)
+
+	tx, err := p.b.DistributeRewards(rr, rewardTxMemo(id))
+	if err != nil {
+		if !blockchain.IsNotBroadcast(err) {
+			// tx could reach the node, so reward tx is left pending until reconciler finds it by memo
+			log.WithError(err).Warn("reward tx broadcast result is unknown")
+			return fmt.Errorf("failed to broadcast MsgDistributeRewards: %w", err)
+		}
+
+		if err := p.is.SetRewardTxStatus(ctx, id, storage.RewardTxFailed, "", err.Error()); err != nil {
+			log.WithError(err).Error("failed to set reward tx failed")
+		}
+		return fmt.Errorf("failed to broadcast MsgDistributeRewards: %w", err)
+	}
+
+	if err := p.is.SetRewardTxStatus(ctx, id, storage.RewardTxBroadcast, tx, ""); err != nil {
+		log.WithError(err).WithField("tx", tx).Error("failed to set reward tx broadcast")
+		return fmt.Errorf("failed to set reward tx broadcast: %w", err)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, txWaitTimeout)
+	defer cancel()
+
+	// reward tx is left broadcast when tx isn't found, reconciler will settle it
+	status, err := p.b.WaitForTx(waitCtx, tx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for tx: %w", err)
+	}
+
+	if status == blockchain.TxFailed {
+		if err := p.is.SetRewardTxStatus(ctx, id, storage.RewardTxFailed, "", "tx execution is failed"); err != nil {
+			log.WithError(err).Error("failed to set reward tx failed")
+		}
+		return errTxFailed
+	}
+
+	return confirmRewardTx(ctx, p.is, id, tx, items)
+}
+
+// confirmRewardTx saves pdv meta of rewarded pdv and marks transaction as confirmed.
+func confirmRewardTx(ctx context.Context, is storage.IndexStorage, id uint64, tx string, items []*storage.RewardTxItem) error {
+	if err := is.InTx(ctx, func(s storage.IndexStorage) error {
+		for _, v := range items {
+			if err := s.SetPDVMeta(ctx, v.Address, v.ID, tx, v.Device, v.Meta); err != nil {
+				return fmt.Errorf("failed to set meta in pg: %w", err)
+			}
+		}
+
+		return s.SetRewardTxStatus(ctx, id, storage.RewardTxConfirmed, tx, "")
+	}); err != nil {
+		return fmt.Errorf("failed to confirm reward tx: %w", err)
+	}
+
+	return nil
+}
+
+// rewardTxMemo returns memo of reward tx, it allows to find tx whose broadcast result is lost.
+func rewardTxMemo(id uint64) string {
+	return fmt.Sprintf("reward_tx:%d", id)
+}
+
+// storePDV writes pdv data into file storage. It returns false if pdv is already stored.
+func (p *Processor) storePDV(ctx context.Context, pdv *producer.PDVMessage) (bool, error) {
+	log := log.WithFields(logrus.Fields{
+		"id":   pdv.ID,
+		"meta": pdv.Meta,
+	})
+
+	if _, err := p.is.GetPDVMeta(ctx, pdv.Address, pdv.ID); err == nil {
+		return false, nil
+	} else if !errors.Is(err, storage.ErrNotFound) {
+		log.WithError(err).Error("failed to check pdv existence")
+		return false, fmt.Errorf("failed to check pdv existence: %w", err)
+	}
+
+	// data is already written by cerberus
+	if len(pdv.Data) == 0 {
+		return true, nil
+	}
+
+	if _, err := p.fs.Write(
+		ctx,
+		bytes.NewReader(pdv.Data),
+		int64(len(pdv.Data)),
+		getPDVFilePath(pdv.Address, pdv.ID),
+		"binary/octet-stream",
+		false,
+	); err != nil {
+		log.WithError(err).Error("failed to write data to storage")
+		return false, fmt.Errorf("failed to write data to storage: %w", err)
+	}
+
+	return true, nil
+}
+
+// parallel calls f for every index in [0, n) using routines goroutines.
+func parallel(routines int, f func(idx int), n int) {
+	var wg sync.WaitGroup
+
+	ch := make(chan int)
+
+	for i := 0; i < routines; i++ {
+		wg.Add(1)
+
+		go func() {
+			for idx := range ch {
+				f(idx)
+			}
+			wg.Done()
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		ch <- i
+	}
+	close(ch)
+
+	wg.Wait()
+}
+
+func getPDVOwnerPrefix(owner string) string {
+	return fmt.Sprintf("%s/pdv", owner)
+}
+
+func getPDVFilePath(owner string, id uint64) string {
+	// once we needed to have descending sort on s3 side, that's why we revert id and print it to hex
+	// now we have to support this or do a bit complicated migration
+	return fmt.Sprintf("%s/%016x", getPDVOwnerPrefix(owner), math.MaxUint64-id)
+}
@@ -0,0 +1,202 @@
+package pdv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Decentr-net/cerberus/internal/blockchain"
+	blockchainmock "github.com/Decentr-net/cerberus/internal/blockchain/mock"
+	"github.com/Decentr-net/cerberus/internal/entities"
+	"github.com/Decentr-net/cerberus/internal/producer"
+	"github.com/Decentr-net/cerberus/internal/storage"
+	storagemock "github.com/Decentr-net/cerberus/internal/storage/mock"
+	"github.com/Decentr-net/cerberus/pkg/schema"
+)
+
+var (
+	ctx     = context.Background()
+	errTest = errors.New("test")
+)
+
+func newMessage(addr string, id uint64, reward int64) *producer.PDVMessage {
+	return &producer.PDVMessage{
+		ID:      id,
+		Address: addr,
+		Device:  "android",
+		Meta: &entities.PDVMeta{
+			ObjectTypes: map[schema.Type]uint16{
+				schema.PDVCookieType: 1,
+			},
+			Reward: sdk.NewDecWithPrec(reward, 6),
+		},
+		Data: []byte(`{"id": 1}`),
+	}
+}
+
+func TestProcessor_Process(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fs := storagemock.NewMockFileStorage(ctrl)
+	is := storagemock.NewMockIndexStorage(ctrl)
+	b := blockchainmock.NewMockBlockchain(ctrl)
+
+	existing, failed, stored := newMessage("addr1", 1, 1), newMessage("addr2", 2, 2), newMessage("addr3", 3, 3)
+
+	is.EXPECT().GetPDVMeta(gomock.Any(), "addr1", uint64(1)).Return(&entities.PDVMeta{}, nil)
+	is.EXPECT().GetPDVMeta(gomock.Any(), "addr2", uint64(2)).Return(nil, errTest)
+	is.EXPECT().GetPDVMeta(gomock.Any(), "addr3", uint64(3)).Return(nil, storage.ErrNotFound)
+	fs.EXPECT().Write(
+		gomock.Any(),
+		gomock.Any(),
+		int64(9),
+		"addr3/pdv/fffffffffffffffc",
+		"binary/octet-stream",
+		false,
+	).DoAndReturn(func(_ context.Context, data io.Reader, _ int64, _, _ string, _ bool) (string, error) {
+		b, err := io.ReadAll(data)
+		require.NoError(t, err)
+		require.Equal(t, `{"id": 1}`, string(b))
+		return "3", nil
+	})
+	is.EXPECT().HasActiveRewardTx(gomock.Any(), "addr3", uint64(3)).Return(false, nil)
+	is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(s storage.IndexStorage) error) error {
+		return f(is)
+	}).Times(2)
+	is.EXPECT().CreateRewardTx(gomock.Any(), []*storage.RewardTxItem{{
+		Address: "addr3",
+		ID:      3,
+		Device:  "android",
+		Meta:    stored.Meta,
+	}}).Return(uint64(10), nil)
+	b.EXPECT().DistributeRewards([]blockchain.Reward{{
+		Receiver: "addr3",
+		ID:       3,
+		Reward:   sdk.NewDecWithPrec(3, 6),
+	}}, "reward_tx:10").Return("tx", nil)
+	is.EXPECT().SetRewardTxStatus(gomock.Any(), uint64(10), storage.RewardTxBroadcast, "tx", "").Return(nil)
+	b.EXPECT().WaitForTx(gomock.Any(), "tx").Return(blockchain.TxSucceeded, nil)
+	is.EXPECT().SetPDVMeta(gomock.Any(), "addr3", uint64(3), "tx", "android", stored.Meta).Return(nil)
+	is.EXPECT().SetRewardTxStatus(gomock.Any(), uint64(10), storage.RewardTxConfirmed, "tx", "").Return(nil)
+
+	errs, err := NewProcessor(fs, is, b, 2).Process(ctx, []*producer.PDVMessage{existing, failed, stored})
+	require.NoError(t, err)
+	require.Len(t, errs, 3)
+	require.NoError(t, errs[0])
+	require.ErrorIs(t, errs[1], errTest)
+	require.NoError(t, errs[2])
+}
+
+func TestProcessor_Process_DataStored(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fs := storagemock.NewMockFileStorage(ctrl)
+	is := storagemock.NewMockIndexStorage(ctrl)
+	b := blockchainmock.NewMockBlockchain(ctrl)
+
+	msg := newMessage("addr1", 1, 1)
+	msg.Data = nil
+
+	// data is written by cerberus, so nothing is written into the file storage
+	is.EXPECT().GetPDVMeta(gomock.Any(), "addr1", uint64(1)).Return(nil, storage.ErrNotFound)
+	is.EXPECT().HasActiveRewardTx(gomock.Any(), "addr1", uint64(1)).Return(true, nil)
+
+	errs, err := NewProcessor(fs, is, b, 2).Process(ctx, []*producer.PDVMessage{msg})
+	require.NoError(t, err)
+	require.Equal(t, []error{nil}, errs)
+}
+
+func TestProcessor_Process_DistributeRewardsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fs := storagemock.NewMockFileStorage(ctrl)
+	is := storagemock.NewMockIndexStorage(ctrl)
+	b := blockchainmock.NewMockBlockchain(ctrl)
+
+	is.EXPECT().GetPDVMeta(gomock.Any(), "addr1", uint64(1)).Return(nil, storage.ErrNotFound)
+	fs.EXPECT().Write(gomock.Any(), gomock.Any(), int64(9), "addr1/pdv/fffffffffffffffe", "binary/octet-stream", false).Return("1", nil)
+	is.EXPECT().HasActiveRewardTx(gomock.Any(), "addr1", uint64(1)).Return(false, nil)
+	is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(s storage.IndexStorage) error) error {
+		return f(is)
+	})
+	is.EXPECT().CreateRewardTx(gomock.Any(), gomock.Any()).Return(uint64(10), nil)
+	// broadcast result is unknown, reward tx is left pending for reconciler
+	b.EXPECT().DistributeRewards(gomock.Any(), "reward_tx:10").Return("", errTest)
+
+	_, err := NewProcessor(fs, is, b, 2).Process(ctx, []*producer.PDVMessage{newMessage("addr1", 1, 1)})
+	require.ErrorIs(t, err, errTest)
+}
+
+func TestProcessor_Process_DistributeRewardsNotBroadcast(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fs := storagemock.NewMockFileStorage(ctrl)
+	is := storagemock.NewMockIndexStorage(ctrl)
+	b := blockchainmock.NewMockBlockchain(ctrl)
+
+	err := fmt.Errorf("%w: addr1", blockchain.ErrInvalidAddress)
+
+	is.EXPECT().GetPDVMeta(gomock.Any(), "addr1", uint64(1)).Return(nil, storage.ErrNotFound)
+	fs.EXPECT().Write(gomock.Any(), gomock.Any(), int64(9), "addr1/pdv/fffffffffffffffe", "binary/octet-stream", false).Return("1", nil)
+	is.EXPECT().HasActiveRewardTx(gomock.Any(), "addr1", uint64(1)).Return(false, nil)
+	is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(s storage.IndexStorage) error) error {
+		return f(is)
+	})
+	is.EXPECT().CreateRewardTx(gomock.Any(), gomock.Any()).Return(uint64(10), nil)
+	b.EXPECT().DistributeRewards(gomock.Any(), "reward_tx:10").Return("", err)
+	is.EXPECT().SetRewardTxStatus(gomock.Any(), uint64(10), storage.RewardTxFailed, "", err.Error()).Return(nil)
+
+	_, err = NewProcessor(fs, is, b, 2).Process(ctx, []*producer.PDVMessage{newMessage("addr1", 1, 1)})
+	require.ErrorIs(t, err, blockchain.ErrInvalidAddress)
+}
+
+func TestProcessor_Process_AlreadyRewarded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fs := storagemock.NewMockFileStorage(ctrl)
+	is := storagemock.NewMockIndexStorage(ctrl)
+	b := blockchainmock.NewMockBlockchain(ctrl)
+
+	is.EXPECT().GetPDVMeta(gomock.Any(), "addr1", uint64(1)).Return(nil, storage.ErrNotFound)
+	fs.EXPECT().Write(gomock.Any(), gomock.Any(), int64(9), "addr1/pdv/fffffffffffffffe", "binary/octet-stream", false).Return("1", nil)
+	is.EXPECT().HasActiveRewardTx(gomock.Any(), "addr1", uint64(1)).Return(true, nil)
+
+	errs, err := NewProcessor(fs, is, b, 2).Process(ctx, []*producer.PDVMessage{newMessage("addr1", 1, 1)})
+	require.NoError(t, err)
+	require.Equal(t, []error{nil}, errs)
+}
+
+func TestProcessor_Process_TxFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fs := storagemock.NewMockFileStorage(ctrl)
+	is := storagemock.NewMockIndexStorage(ctrl)
+	b := blockchainmock.NewMockBlockchain(ctrl)
+
+	is.EXPECT().GetPDVMeta(gomock.Any(), "addr1", uint64(1)).Return(nil, storage.ErrNotFound)
+	fs.EXPECT().Write(gomock.Any(), gomock.Any(), int64(9), "addr1/pdv/fffffffffffffffe", "binary/octet-stream", false).Return("1", nil)
+	is.EXPECT().HasActiveRewardTx(gomock.Any(), "addr1", uint64(1)).Return(false, nil)
+	is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(s storage.IndexStorage) error) error {
+		return f(is)
+	})
+	is.EXPECT().CreateRewardTx(gomock.Any(), gomock.Any()).Return(uint64(10), nil)
+	b.EXPECT().DistributeRewards(gomock.Any(), "reward_tx:10").Return("tx", nil)
+	is.EXPECT().SetRewardTxStatus(gomock.Any(), uint64(10), storage.RewardTxBroadcast, "tx", "").Return(nil)
+	b.EXPECT().WaitForTx(gomock.Any(), "tx").Return(blockchain.TxFailed, nil)
+	is.EXPECT().SetRewardTxStatus(gomock.Any(), uint64(10), storage.RewardTxFailed, "", gomock.Any()).Return(nil)
+
+	_, err := NewProcessor(fs, is, b, 2).Process(ctx, []*producer.PDVMessage{newMessage("addr1", 1, 1)})
+	require.ErrorIs(t, err, errTxFailed)
+}
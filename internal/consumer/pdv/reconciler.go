@@ -0,0 +1,130 @@
+package pdv
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Decentr-net/cerberus/internal/blockchain"
+	"github.com/Decentr-net/cerberus/internal/storage"
+)
+
+const (
+	// how long reward tx is left to processor before it's reconciled
+	settleDelay = time.Minute
+	// how long reward tx could stay unsettled before it's considered failed
+	abandonTimeout = time.Hour
+)
+
+// Reconciler settles reward transactions which result wasn't saved by processor.
+type Reconciler struct {
+	is storage.IndexStorage
+	b  blockchain.Blockchain
+}
+
+// NewReconciler creates a new instance of Reconciler.
+func NewReconciler(is storage.IndexStorage, b blockchain.Blockchain) *Reconciler {
+	return &Reconciler{
+		is: is,
+		b:  b,
+	}
+}
+
+// Run settles pending and broadcast reward transactions. It returns count of settled transactions.
+func (r *Reconciler) Run(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	txs, err := r.is.GetUnsettledRewardTxList(ctx, now.Add(-settleDelay))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get unsettled reward txs: %w", err)
+	}
+
+	var count int
+	for _, v := range txs {
+		if ctx.Err() != nil {
+			return count, ctx.Err()
+		}
+
+		settled, err := r.settle(ctx, v, now.Sub(v.UpdatedAt) > abandonTimeout)
+		if err != nil {
+			return count, fmt.Errorf("failed to settle reward tx %d: %w", v.ID, err)
+		}
+
+		if settled {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// RunAsync runs reconciliation in the background every interval until ctx is done.
+func (r *Reconciler) RunAsync(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			count, err := r.Run(ctx)
+			if err != nil {
+				log.WithError(err).Error("failed to reconcile reward txs")
+			}
+			if count > 0 {
+				log.Infof("%d reward txs reconciled", count)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// settle checks reward tx in the blockchain and updates its status. It returns false if tx is left unsettled.
+func (r *Reconciler) settle(ctx context.Context, tx *storage.RewardTx, abandoned bool) (bool, error) {
+	log := log.WithFields(logrus.Fields{
+		"reward_tx": tx.ID,
+		"tx":        tx.TxHash,
+		"items":     tx.Items,
+	})
+
+	var (
+		hash   = tx.TxHash
+		status blockchain.TxStatus
+		err    error
+	)
+
+	if tx.Status == storage.RewardTxPending {
+		// processor didn't save broadcast result, tx hash is unknown, so tx is searched by memo
+		hash, status, err = r.b.FindTx(ctx, rewardTxMemo(tx.ID))
+		if err != nil {
+			return false, fmt.Errorf("failed to find tx: %w", err)
+		}
+		log = log.WithField("tx", hash)
+	} else {
+		status, err = r.b.GetTxStatus(ctx, tx.TxHash)
+		if err != nil {
+			return false, fmt.Errorf("failed to get tx status: %w", err)
+		}
+	}
+
+	switch status {
+	case blockchain.TxSucceeded:
+		log.Info("reward tx is confirmed")
+		return true, confirmRewardTx(ctx, r.is, tx.ID, hash, tx.Items)
+	case blockchain.TxFailed:
+		log.Error("reward tx is failed")
+		return true, r.is.SetRewardTxStatus(ctx, tx.ID, storage.RewardTxFailed, hash, "tx execution is failed")
+	default:
+		if !abandoned {
+			return false, nil
+		}
+
+		log.Error("reward tx is not found")
+		return true, r.is.SetRewardTxStatus(ctx, tx.ID, storage.RewardTxFailed, "", "tx is not found")
+	}
+}
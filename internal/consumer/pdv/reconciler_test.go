@@ -0,0 +1,93 @@
+package pdv
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Decentr-net/cerberus/internal/blockchain"
+	blockchainmock "github.com/Decentr-net/cerberus/internal/blockchain/mock"
+	"github.com/Decentr-net/cerberus/internal/storage"
+	storagemock "github.com/Decentr-net/cerberus/internal/storage/mock"
+)
+
+func TestReconciler_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := storagemock.NewMockIndexStorage(ctrl)
+	b := blockchainmock.NewMockBlockchain(ctrl)
+
+	msg := newMessage("addr1", 1, 1)
+	item := &storage.RewardTxItem{
+		Address: msg.Address,
+		ID:      msg.ID,
+		Device:  msg.Device,
+		Meta:    msg.Meta,
+	}
+
+	fresh, old := time.Now().Add(-2*settleDelay), time.Now().Add(-2*abandonTimeout)
+
+	is.EXPECT().GetUnsettledRewardTxList(gomock.Any(), gomock.Any()).Return([]*storage.RewardTx{
+		{ID: 1, TxHash: "01", Status: storage.RewardTxBroadcast, UpdatedAt: fresh, Items: []*storage.RewardTxItem{item}},
+		{ID: 2, TxHash: "02", Status: storage.RewardTxBroadcast, UpdatedAt: fresh},
+		{ID: 3, TxHash: "03", Status: storage.RewardTxBroadcast, UpdatedAt: fresh},
+		{ID: 4, TxHash: "04", Status: storage.RewardTxBroadcast, UpdatedAt: old},
+		{ID: 5, Status: storage.RewardTxPending, UpdatedAt: fresh},
+		{ID: 6, Status: storage.RewardTxPending, UpdatedAt: old},
+		{ID: 7, Status: storage.RewardTxPending, UpdatedAt: fresh},
+	}, nil)
+
+	// confirmed
+	b.EXPECT().GetTxStatus(gomock.Any(), "01").Return(blockchain.TxSucceeded, nil)
+	is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(s storage.IndexStorage) error) error {
+		return f(is)
+	}).Times(2)
+	is.EXPECT().SetPDVMeta(gomock.Any(), "addr1", uint64(1), "01", "android", msg.Meta).Return(nil)
+	is.EXPECT().SetRewardTxStatus(gomock.Any(), uint64(1), storage.RewardTxConfirmed, "01", "").Return(nil)
+
+	// failed
+	b.EXPECT().GetTxStatus(gomock.Any(), "02").Return(blockchain.TxFailed, nil)
+	is.EXPECT().SetRewardTxStatus(gomock.Any(), uint64(2), storage.RewardTxFailed, "02", gomock.Any()).Return(nil)
+
+	// not found yet
+	b.EXPECT().GetTxStatus(gomock.Any(), "03").Return(blockchain.TxNotFound, nil)
+
+	// not found for too long
+	b.EXPECT().GetTxStatus(gomock.Any(), "04").Return(blockchain.TxNotFound, nil)
+	is.EXPECT().SetRewardTxStatus(gomock.Any(), uint64(4), storage.RewardTxFailed, "", gomock.Any()).Return(nil)
+
+	// pending is found by memo
+	b.EXPECT().FindTx(gomock.Any(), "reward_tx:5").Return("05", blockchain.TxSucceeded, nil)
+	is.EXPECT().SetRewardTxStatus(gomock.Any(), uint64(5), storage.RewardTxConfirmed, "05", "").Return(nil)
+
+	// abandoned pending
+	b.EXPECT().FindTx(gomock.Any(), "reward_tx:6").Return("", blockchain.TxNotFound, nil)
+	is.EXPECT().SetRewardTxStatus(gomock.Any(), uint64(6), storage.RewardTxFailed, "", gomock.Any()).Return(nil)
+
+	// pending isn't found yet
+	b.EXPECT().FindTx(gomock.Any(), "reward_tx:7").Return("", blockchain.TxNotFound, nil)
+
+	count, err := NewReconciler(is, b).Run(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 5, count)
+}
+
+func TestReconciler_Run_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := storagemock.NewMockIndexStorage(ctrl)
+	b := blockchainmock.NewMockBlockchain(ctrl)
+
+	is.EXPECT().GetUnsettledRewardTxList(gomock.Any(), gomock.Any()).Return([]*storage.RewardTx{
+		{ID: 1, TxHash: "01", Status: storage.RewardTxBroadcast, UpdatedAt: time.Now()},
+	}, nil)
+	b.EXPECT().GetTxStatus(gomock.Any(), "01").Return(blockchain.TxNotFound, errTest)
+
+	_, err := NewReconciler(is, b).Run(ctx)
+	require.ErrorIs(t, err, errTest)
+}
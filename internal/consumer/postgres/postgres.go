@@ -0,0 +1,216 @@
+// Package postgres is a postgres outbox implementation of consumer
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Decentr-net/cerberus/internal/blockchain"
+	"github.com/Decentr-net/cerberus/internal/consumer"
+	"github.com/Decentr-net/cerberus/internal/consumer/pdv"
+	"github.com/Decentr-net/cerberus/internal/producer"
+	"github.com/Decentr-net/cerberus/internal/storage"
+)
+
+var _ consumer.Consumer = &impl{}
+
+var log = logrus.WithField("package", "postgres")
+
+const (
+	// how long consumer will wait for the next messages when outbox is empty
+	pollInterval = time.Second
+	// how long claimed messages are locked from other consumers
+	lockTimeout = 10 * time.Minute
+	// how long failed message waits for the next attempt
+	retryDelay = time.Minute
+)
+
+type outboxDTO struct {
+	ID       int64           `db:"id"`
+	Body     json.RawMessage `db:"body"`
+	Attempts int             `db:"attempts"`
+}
+
+type impl struct {
+	p  *pdv.Processor
+	is storage.IndexStorage
+	db *sqlx.DB
+
+	maxAttempts int
+	batchSize   int
+}
+
+// New return new instance of impl.
+// PDV are processed by batches of batchSize messages using concurrency goroutines.
+// Message which fails maxAttempts times is moved to quarantine, 0 means that messages are never quarantined.
+func New(db *sql.DB,
+	fs storage.FileStorage,
+	is storage.IndexStorage,
+	b blockchain.Blockchain,
+	maxAttempts int,
+	concurrency int,
+	batchSize int,
+) *impl { // nolint:golint
+	return &impl{
+		p:           pdv.NewProcessor(fs, is, b, concurrency),
+		is:          is,
+		db:          sqlx.NewDb(db, "postgres"),
+		maxAttempts: maxAttempts,
+		batchSize:   batchSize,
+	}
+}
+
+// Run consumes messages from pdv_outbox table and sends pdv to users.
+func (i *impl) Run(ctx context.Context) error {
+	for {
+		n, err := i.processBatch()
+		if err != nil {
+			log.WithError(err).Error("failed to process messages")
+		}
+
+		// don't wait if there could be more messages
+		if n > 0 && err == nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// processBatch claims messages, so several consumers could work concurrently, and deletes processed ones.
+// Messages are claimed in a short statement, so neither row locks nor connection are held during processing.
+// It returns count of claimed messages.
+func (i *impl) processBatch() (int, error) {
+	// Background context is used to gracefully shutdown processor
+	ctx := context.Background()
+
+	rows, err := i.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	log.WithField("msgs", len(rows)).Info("start processing messages")
+
+	var (
+		messages = make([]*producer.PDVMessage, 0, len(rows))
+		decoded  = make([]*outboxDTO, 0, len(rows))
+
+		toDelete []int64
+		toRetry  []int64
+	)
+
+	for _, v := range rows {
+		var m producer.PDVMessage
+		if err := json.Unmarshal(v.Body, &m); err != nil {
+			log.WithError(err).WithField("id", v.ID).Error("failed to unmarshal message")
+
+			// message is malformed, it makes no sense to wait for the next attempt
+			if i.quarantine(ctx, v, fmt.Sprintf("failed to unmarshal message: %s", err), true) {
+				toDelete = append(toDelete, v.ID)
+			} else {
+				toRetry = append(toRetry, v.ID)
+			}
+			continue
+		}
+
+		messages = append(messages, &m)
+		decoded = append(decoded, v)
+	}
+
+	// quarantined messages should be deleted even if processing is failed
+	errs, processErr := i.p.Process(ctx, messages)
+	for idx, v := range decoded {
+		switch {
+		case processErr != nil:
+			toRetry = append(toRetry, v.ID)
+		case errs[idx] == nil || i.quarantine(ctx, v, errs[idx].Error(), false):
+			toDelete = append(toDelete, v.ID)
+		default:
+			toRetry = append(toRetry, v.ID)
+		}
+	}
+
+	if _, err := i.db.ExecContext(ctx, `DELETE FROM pdv_outbox WHERE id = ANY($1)`, pq.Array(toDelete)); err != nil {
+		return 0, fmt.Errorf("failed to delete messages: %w", err)
+	}
+
+	if _, err := i.db.ExecContext(ctx, `
+		UPDATE pdv_outbox SET locked_until = NOW() + $2 * INTERVAL '1 second'
+		WHERE id = ANY($1)
+	`, pq.Array(toRetry), retryDelay.Seconds()); err != nil {
+		return 0, fmt.Errorf("failed to release messages: %w", err)
+	}
+
+	if processErr != nil {
+		return 0, processErr
+	}
+
+	return len(rows), nil
+}
+
+// claim locks the oldest available messages for lockTimeout and increments their attempts.
+// Message is available again when the lock expires, e.g. consumer died while processing it.
+func (i *impl) claim(ctx context.Context) ([]*outboxDTO, error) {
+	var rows []*outboxDTO
+	if err := sqlx.SelectContext(ctx, i.db, &rows, `
+		UPDATE pdv_outbox SET attempts = attempts + 1, locked_until = NOW() + $2 * INTERVAL '1 second'
+		WHERE id IN (
+			SELECT id FROM pdv_outbox
+			WHERE locked_until IS NULL OR locked_until < NOW()
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, body, attempts
+	`, i.batchSize, lockTimeout.Seconds()); err != nil {
+		return nil, fmt.Errorf("failed to claim messages: %w", err)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].ID < rows[j].ID
+	})
+
+	return rows, nil
+}
+
+// quarantine puts message into quarantine if it exceeds max attempts or force is true.
+// It returns true if message is quarantined and should be deleted from the outbox.
+func (i *impl) quarantine(ctx context.Context, m *outboxDTO, reason string, force bool) bool {
+	if i.maxAttempts <= 0 {
+		return false
+	}
+
+	if m.Attempts < i.maxAttempts && !force {
+		return false
+	}
+
+	log := log.WithField("id", m.ID).WithField("reason", reason)
+
+	if err := i.is.CreateQuarantineItem(ctx, string(m.Body), reason, m.Attempts); err != nil {
+		log.WithError(err).Error("failed to quarantine message")
+		return false
+	}
+
+	log.Warn("message is quarantined")
+
+	return true
+}
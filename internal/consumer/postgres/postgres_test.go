@@ -0,0 +1,239 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	m "github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/Decentr-net/cerberus/internal/blockchain"
+	blockchainmock "github.com/Decentr-net/cerberus/internal/blockchain/mock"
+	"github.com/Decentr-net/cerberus/internal/entities"
+	"github.com/Decentr-net/cerberus/internal/producer"
+	pgproducer "github.com/Decentr-net/cerberus/internal/producer/postgres"
+	"github.com/Decentr-net/cerberus/internal/storage"
+	storagemock "github.com/Decentr-net/cerberus/internal/storage/mock"
+	"github.com/Decentr-net/cerberus/pkg/schema"
+)
+
+var (
+	db      *sqlx.DB
+	ctx     = context.Background()
+	errTest = errors.New("test")
+)
+
+func TestMain(m *testing.M) {
+	shutdown := setup()
+
+	code := m.Run()
+	shutdown()
+	os.Exit(code)
+}
+
+func setup() func() {
+	req := testcontainers.ContainerRequest{
+		Image:        "postgres:12",
+		Env:          map[string]string{"POSTGRES_PASSWORD": "root"},
+		ExposedPorts: []string{"5432/tcp"},
+		WaitingFor:   wait.ForListeningPort("5432/tcp"),
+	}
+	c, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+	})
+	if err != nil {
+		logrus.WithError(err).Fatalf("failed to create container")
+	}
+
+	if err := c.Start(ctx); err != nil {
+		logrus.WithError(err).Fatal("failed to start container")
+	}
+
+	host, err := c.Host(ctx)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to get host")
+	}
+
+	port, err := c.MappedPort(ctx, "5432")
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to map port")
+	}
+
+	dsn := fmt.Sprintf("host=%s port=%d user=postgres password=root sslmode=disable", host, port.Int())
+
+	db, err = sqlx.Open("postgres", dsn)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to open connection")
+	}
+
+	if err := db.Ping(); err != nil {
+		logrus.WithError(err).Fatal("failed to ping postgres")
+	}
+
+	shutdownFn := func() {
+		if c != nil {
+			c.Terminate(ctx)
+		}
+	}
+
+	migrate("postgres", "root", host, "postgres", port.Int())
+
+	return shutdownFn
+}
+
+func migrate(username, password, hostname, dbname string, port int) {
+	_, currFile, _, ok := runtime.Caller(0)
+	if !ok {
+		logrus.Fatal("failed to get current file location")
+	}
+
+	migrations := filepath.Join(currFile, "..", "..", "..", "..", "scripts", "migrations", "postgres")
+
+	migrator, err := m.New(
+		fmt.Sprintf("file://%s", migrations),
+		fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=disable",
+			username, password, hostname, port, dbname),
+	)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to create migrator")
+	}
+	defer migrator.Close()
+
+	if err := migrator.Up(); err != nil {
+		logrus.WithError(err).Fatal("failed to migrate")
+	}
+}
+
+func TestImpl_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fs := storagemock.NewMockFileStorage(ctrl)
+	is := storagemock.NewMockIndexStorage(ctrl)
+	b := blockchainmock.NewMockBlockchain(ctrl)
+
+	i := New(db.DB, fs, is, b, 10, 2, 10)
+	p := pgproducer.New(db.DB)
+
+	addr1, addr2 := "addr1", "addr2"
+	ctx, cancel := context.WithCancel(ctx)
+
+	require.NoError(t, p.Produce(ctx, &producer.PDVMessage{
+		ID:      1,
+		Address: addr1,
+		Meta: &entities.PDVMeta{
+			ObjectTypes: map[schema.Type]uint16{
+				schema.PDVCookieType: 1,
+			},
+			Reward: sdk.NewDecWithPrec(1, 6),
+		},
+		Data: []byte(`{"id": 1}`),
+	}))
+	require.NoError(t, p.Produce(ctx, &producer.PDVMessage{
+		ID:      2,
+		Address: addr2,
+		Device:  "android",
+		Meta: &entities.PDVMeta{
+			ObjectTypes: map[schema.Type]uint16{
+				schema.PDVCookieType: 2,
+			},
+			Reward: sdk.NewDecWithPrec(2, 6),
+		},
+		Data: []byte(`{"id": 2}`),
+	}))
+
+	is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(s storage.IndexStorage) error) error {
+		defer cancel()
+		return f(is)
+	}).Times(2)
+	is.EXPECT().GetPDVMeta(gomock.Any(), addr1, uint64(1)).Return(&entities.PDVMeta{}, nil)
+	is.EXPECT().GetPDVMeta(gomock.Any(), addr2, uint64(2)).Return(nil, storage.ErrNotFound)
+	fs.EXPECT().Write(
+		gomock.Any(),
+		gomock.Any(),
+		int64(9),
+		"addr2/pdv/fffffffffffffffd",
+		"binary/octet-stream",
+		false,
+	).Return("2", nil)
+	is.EXPECT().HasActiveRewardTx(gomock.Any(), addr2, uint64(2)).Return(false, nil)
+	is.EXPECT().CreateRewardTx(gomock.Any(), gomock.Any()).Return(uint64(1), nil)
+	b.EXPECT().DistributeRewards([]blockchain.Reward{{
+		Receiver: addr2,
+		ID:       2,
+		Reward:   sdk.NewDecWithPrec(2, 6),
+	}}, "reward_tx:1").Return("tx", nil)
+	is.EXPECT().SetRewardTxStatus(gomock.Any(), uint64(1), storage.RewardTxBroadcast, "tx", "")
+	b.EXPECT().WaitForTx(gomock.Any(), "tx").Return(blockchain.TxSucceeded, nil)
+	is.EXPECT().SetRewardTxStatus(gomock.Any(), uint64(1), storage.RewardTxConfirmed, "tx", "")
+	is.EXPECT().SetPDVMeta(gomock.Any(), addr2, uint64(2), "tx", "android", &entities.PDVMeta{
+		ObjectTypes: map[schema.Type]uint16{
+			schema.PDVCookieType: 2,
+		},
+		Reward: sdk.NewDecWithPrec(2, 6),
+	})
+
+	require.ErrorIs(t, i.Run(ctx), context.Canceled)
+
+	var count int
+	require.NoError(t, db.Get(&count, `SELECT COUNT(*) FROM pdv_outbox`))
+	require.Zero(t, count)
+}
+
+func TestImpl_Run_Quarantine(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fs := storagemock.NewMockFileStorage(ctrl)
+	is := storagemock.NewMockIndexStorage(ctrl)
+	b := blockchainmock.NewMockBlockchain(ctrl)
+
+	i := New(db.DB, fs, is, b, 2, 2, 10)
+	p := pgproducer.New(db.DB)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	require.NoError(t, p.Produce(ctx, &producer.PDVMessage{ID: 1, Address: "addr1", Meta: &entities.PDVMeta{}}))
+	_, err := db.Exec(`INSERT INTO pdv_outbox(body) VALUES('"malformed"')`)
+	require.NoError(t, err)
+
+	// malformed message is quarantined at once, failed one after max attempts
+	is.EXPECT().CreateQuarantineItem(gomock.Any(), `"malformed"`, gomock.Any(), 1).Return(nil)
+	is.EXPECT().GetPDVMeta(gomock.Any(), "addr1", uint64(1)).Return(nil, errTest).Times(2)
+	is.EXPECT().CreateQuarantineItem(gomock.Any(), gomock.Any(), errTest.Error(), 2).DoAndReturn(
+		func(context.Context, string, string, int) error {
+			cancel()
+			return nil
+		})
+
+	n, err := i.processBatch()
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	// failed message waits for retry delay
+	_, err = db.Exec(`UPDATE pdv_outbox SET locked_until = NULL`)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, i.Run(ctx), context.Canceled)
+
+	var count int
+	require.NoError(t, db.Get(&count, `SELECT COUNT(*) FROM pdv_outbox`))
+	require.Zero(t, count)
+}
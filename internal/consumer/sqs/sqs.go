@@ -2,13 +2,10 @@
 package sqs
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"math"
-	"sync"
+	"strconv"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -18,6 +15,7 @@ import (
 
 	"github.com/Decentr-net/cerberus/internal/blockchain"
 	"github.com/Decentr-net/cerberus/internal/consumer"
+	"github.com/Decentr-net/cerberus/internal/consumer/pdv"
 	"github.com/Decentr-net/cerberus/internal/producer"
 	"github.com/Decentr-net/cerberus/internal/storage"
 )
@@ -29,172 +27,210 @@ var log = logrus.WithField("package", "sqs")
 const (
 	// how long the message is locked from other consumers in seconds
 	visibilityTimeout int64 = 600
+	// how often visibility of messages in processing is extended
+	visibilityExtendInterval = time.Duration(visibilityTimeout/2) * time.Second
 	// how long consumer will wait for the next messages in seconds
 	waitTimeSeconds int64 = 20
-	// size of bulk
-	maxNumberOfMessages int64 = 10
+	// how long processor will wait for the next messages to fill the batch
+	batchLinger = time.Second
+	// sqs allows to receive and to change 10 or less messages per request
+	maxBatchEntries = 10
 )
 
 type impl struct {
-	fs storage.FileStorage
+	p  *pdv.Processor
 	is storage.IndexStorage
-	b  blockchain.Blockchain
 
-	sqs      *sqs.SQS
-	queueURL string
-
-	msgsCh chan *sqs.Message
+	sqs             *sqs.SQS
+	queueURL        string
+	maxReceiveCount int
+	batchSize       int
+	shutdownTimeout time.Duration
 }
 
 // New return new instance of impl.
+// Message which fails maxReceiveCount times is moved to quarantine, 0 means that messages are never quarantined.
+// PDV are processed by batches of batchSize messages using concurrency goroutines.
+// On shutdown the current batch is given shutdownTimeout to be processed and acknowledged.
 func New(fs storage.FileStorage,
 	is storage.IndexStorage,
 	b blockchain.Blockchain,
 	sqsClient *sqs.SQS,
 	queueURL string,
+	maxReceiveCount int,
+	concurrency int,
+	batchSize int,
+	shutdownTimeout time.Duration,
 ) *impl { // nolint:golint
 	return &impl{
-		fs:       fs,
-		is:       is,
-		b:        b,
-		sqs:      sqsClient,
-		queueURL: queueURL,
-
-		msgsCh: make(chan *sqs.Message, 200),
+		p:               pdv.NewProcessor(fs, is, b, concurrency),
+		is:              is,
+		sqs:             sqsClient,
+		queueURL:        queueURL,
+		maxReceiveCount: maxReceiveCount,
+		batchSize:       batchSize,
+		shutdownTimeout: shutdownTimeout,
 	}
 }
 
 // Run consumes messages from SQS and sends pdv to users.
+// When ctx is done it stops receiving and waits until received messages are processed.
 func (i *impl) Run(ctx context.Context) error {
-	wg, ctx := errgroup.WithContext(ctx)
+	// processing isn't bound to ctx, so the current batch isn't interrupted on shutdown
+	processCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-processCtx.Done():
+			return
+		}
+
+		select {
+		case <-time.After(i.shutdownTimeout):
+			log.Warn("shutdown timeout exceeded, cancel processing")
+			cancel()
+		case <-processCtx.Done():
+		}
+	}()
+
+	// channel's capacity limits count of received but not processed messages
+	msgs := make(chan *sqs.Message, i.batchSize)
+
+	var wg errgroup.Group
 
 	wg.Go(func() error {
-		return i.runConsumer(ctx)
+		defer close(msgs)
+		return i.runConsumer(ctx, msgs)
 	})
 	wg.Go(func() error {
-		return i.runProcessor(ctx)
+		i.runProcessor(processCtx, msgs)
+		return nil
 	})
 
 	return wg.Wait()
 }
 
 // runConsumer consumes messages from SQS, and put it into the channel.
-func (i *impl) runConsumer(ctx context.Context) error {
+func (i *impl) runConsumer(ctx context.Context, out chan<- *sqs.Message) error {
+	maxNumberOfMessages := i.batchSize
+	if maxNumberOfMessages > maxBatchEntries {
+		maxNumberOfMessages = maxBatchEntries
+	}
+
 	for {
-		select {
-		case <-ctx.Done():
+		if ctx.Err() != nil {
 			return ctx.Err()
-		default:
 		}
 
-		out, err := i.sqs.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
-			MaxNumberOfMessages: aws.Int64(maxNumberOfMessages),
+		res, err := i.sqs.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
+			AttributeNames:      []*string{aws.String(sqs.MessageSystemAttributeNameApproximateReceiveCount)},
+			MaxNumberOfMessages: aws.Int64(int64(maxNumberOfMessages)),
 			QueueUrl:            aws.String(i.queueURL),
 			VisibilityTimeout:   aws.Int64(visibilityTimeout),
 			WaitTimeSeconds:     aws.Int64(waitTimeSeconds),
 		})
 		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
 			log.WithError(err).Error("failed to receive messages")
 			continue
 		}
 
-		for _, v := range out.Messages {
-			i.msgsCh <- v
+		for idx, v := range res.Messages {
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				// let other consumers receive messages which won't be processed
+				i.release(res.Messages[idx:])
+				return ctx.Err()
+			}
 		}
 	}
 }
 
-// runProcessor consumes messages from the channel, and process it.
-func (i *impl) runProcessor(ctx context.Context) error {
+// runProcessor consumes messages from the channel, and process it by batches until the channel is closed.
+func (i *impl) runProcessor(ctx context.Context, in <-chan *sqs.Message) {
 	for {
-		var messages []*sqs.Message
+		m, ok := <-in
+		if !ok {
+			return
+		}
+
+		batch := []*sqs.Message{m}
+		timer := time.NewTimer(batchLinger)
 
 	collectMessages:
-		for {
+		for len(batch) < i.batchSize {
 			select {
-			case m := <-i.msgsCh:
-				messages = append(messages, m)
-			default:
+			case m, ok := <-in:
+				if !ok {
+					break collectMessages
+				}
+				batch = append(batch, m)
+			case <-timer.C:
 				break collectMessages
 			}
 		}
+		timer.Stop()
 
-		if len(messages) > 0 {
-			log.WithField("msgs", len(messages)).Info("start processing messages")
-
-			if err := i.processMessages(messages); err != nil {
-				log.WithError(err).Error("failed to process messages")
-			}
+		if ctx.Err() != nil {
+			i.release(batch)
+			continue
 		}
 
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			time.Sleep(time.Second)
+		log.WithField("msgs", len(batch)).Info("start processing messages")
+
+		if err := i.processMessages(ctx, batch); err != nil {
+			log.WithError(err).Error("failed to process messages")
 		}
 	}
 }
 
-func (i *impl) processMessages(msgs []*sqs.Message) error {
-	// Background context is used to gracefully shutdown processor
-	ctx := context.Background()
+func (i *impl) processMessages(ctx context.Context, msgs []*sqs.Message) error {
+	stop := i.extendVisibility(msgs)
+	defer stop()
 
 	var (
+		messages = make([]*producer.PDVMessage, 0, len(msgs))
+		decoded  = make([]*sqs.Message, 0, len(msgs))
 		toDelete []*sqs.DeleteMessageBatchRequestEntry
-		toReward []producer.PDVMessage
-
-		mu sync.Mutex
 	)
 
-	parallel(8, func(m *sqs.Message) {
-		var pdv producer.PDVMessage
-		if err := json.Unmarshal([]byte(*m.Body), &pdv); err != nil {
-			log.WithError(err).Error("failed to unmarshal message")
-			return
-		}
+	deleteMsg := func(m *sqs.Message) {
+		toDelete = append(toDelete, &sqs.DeleteMessageBatchRequestEntry{
+			Id:            m.MessageId,
+			ReceiptHandle: m.ReceiptHandle,
+		})
+	}
 
-		savePDV, deleteMsg := i.storePDV(ctx, i.is, &pdv)
-		mu.Lock()
-		if savePDV && pdv.Meta.Reward.IsPositive() {
-			toReward = append(toReward, pdv)
-		}
+	for _, m := range msgs {
+		var v producer.PDVMessage
+		if err := json.Unmarshal([]byte(*m.Body), &v); err != nil {
+			log.WithError(err).Error("failed to unmarshal message")
 
-		if deleteMsg {
-			toDelete = append(toDelete, &sqs.DeleteMessageBatchRequestEntry{
-				Id:            m.MessageId,
-				ReceiptHandle: m.ReceiptHandle,
-			})
-		}
-		mu.Unlock()
-	}, msgs)
-
-	if err := i.is.InTx(ctx, func(s storage.IndexStorage) error {
-		if len(toReward) > 0 {
-			rr := make([]blockchain.Reward, 0, len(toReward))
-			for _, v := range toReward { // nolint:gocritic
-				rr = append(rr, blockchain.Reward{
-					Receiver: v.Address,
-					ID:       v.ID,
-					Reward:   v.Meta.Reward,
-				})
+			// message is malformed, it makes no sense to wait for the next attempt
+			if i.quarantine(ctx, m, fmt.Sprintf("failed to unmarshal message: %s", err), true) {
+				deleteMsg(m)
 			}
+			continue
+		}
 
-			tx, err := i.b.DistributeRewards(rr)
-			if err != nil {
-				return fmt.Errorf("failed to broadcast MsgDistributeRewards: %w", err)
-			}
+		messages = append(messages, &v)
+		decoded = append(decoded, m)
+	}
 
-			for _, v := range toReward { // nolint:gocritic
-				if err := i.is.SetPDVMeta(ctx, v.Address, v.ID, tx, v.Device, v.Meta); err != nil {
-					return fmt.Errorf("failed to set meta in pg: %w", err)
-				}
+	// quarantined messages should be deleted even if processing is failed
+	errs, processErr := i.p.Process(ctx, messages)
+	if processErr == nil {
+		for idx, m := range decoded {
+			if errs[idx] == nil || i.quarantine(ctx, m, errs[idx].Error(), false) {
+				deleteMsg(m)
 			}
 		}
-
-		return nil
-	}); err != nil {
-		return fmt.Errorf("failed to process messages bulk: %w", err)
 	}
 
 	for len(toDelete) > 0 {
@@ -215,67 +251,98 @@ func (i *impl) processMessages(msgs []*sqs.Message) error {
 		}
 	}
 
-	return nil
+	return processErr
 }
 
-func (i *impl) storePDV(ctx context.Context, s storage.IndexStorage, pdv *producer.PDVMessage) (reward bool, deleteMsg bool) {
-	log := log.WithFields(logrus.Fields{
-		"id":   pdv.ID,
-		"meta": pdv.Meta,
-	})
+// quarantine puts message into quarantine if it exceeds max receive count or force is true.
+// It returns true if message is quarantined and should be deleted from the queue.
+func (i *impl) quarantine(ctx context.Context, m *sqs.Message, reason string, force bool) bool {
+	if i.maxReceiveCount <= 0 {
+		return false
+	}
 
-	if _, err := s.GetPDVMeta(ctx, pdv.Address, pdv.ID); err == nil {
-		return false, true
-	} else if !errors.Is(err, storage.ErrNotFound) {
-		log.WithError(err).Error("failed to check pdv existence")
-		return false, false
+	count := receiveCount(m)
+	if count < i.maxReceiveCount && !force {
+		return false
 	}
 
-	if _, err := i.fs.Write(
-		ctx,
-		bytes.NewReader(pdv.Data),
-		int64(len(pdv.Data)),
-		getPDVFilePath(pdv.Address, pdv.ID),
-		"binary/octet-stream",
-		false,
-	); err != nil {
-		log.WithError(err).Error("failed to write data to storage")
-		return false, false
+	log := log.WithField("id", aws.StringValue(m.MessageId)).WithField("reason", reason)
+
+	if err := i.is.CreateQuarantineItem(ctx, aws.StringValue(m.Body), reason, count); err != nil {
+		log.WithError(err).Error("failed to quarantine message")
+		return false
 	}
 
-	return true, true
+	log.Warn("message is quarantined")
+
+	return true
 }
 
-func parallel(routines int, f func(m *sqs.Message), batch []*sqs.Message) {
-	var wg sync.WaitGroup
+func receiveCount(m *sqs.Message) int {
+	v, err := strconv.Atoi(aws.StringValue(m.Attributes[sqs.MessageSystemAttributeNameApproximateReceiveCount]))
+	if err != nil {
+		return 0
+	}
+	return v
+}
 
-	ch := make(chan *sqs.Message)
+// extendVisibility periodically extends visibility timeout of messages, so they aren't received by other consumers
+// while the batch is processed. Returned func stops extending.
+func (i *impl) extendVisibility(msgs []*sqs.Message) func() {
+	done := make(chan struct{})
 
-	for i := 0; i < routines; i++ {
-		wg.Add(1)
+	go func() {
+		ticker := time.NewTicker(visibilityExtendInterval)
+		defer ticker.Stop()
 
-		go func() {
-			for m := range ch {
-				f(m)
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := i.changeVisibility(msgs, visibilityTimeout); err != nil {
+					log.WithError(err).Error("failed to extend messages visibility")
+				}
 			}
-			wg.Done()
-		}()
-	}
+		}
+	}()
 
-	for _, v := range batch {
-		ch <- v
+	return func() {
+		close(done)
 	}
-	close(ch)
-
-	wg.Wait()
 }
 
-func getPDVOwnerPrefix(owner string) string {
-	return fmt.Sprintf("%s/pdv", owner)
+// release makes messages visible for other consumers.
+func (i *impl) release(msgs []*sqs.Message) {
+	if err := i.changeVisibility(msgs, 0); err != nil {
+		log.WithError(err).Error("failed to release messages")
+	}
 }
 
-func getPDVFilePath(owner string, id uint64) string {
-	// once we needed to have descending sort on s3 side, that's why we revert id and print it to hex
-	// now we have to support this or do a bit complicated migration
-	return fmt.Sprintf("%s/%016x", getPDVOwnerPrefix(owner), math.MaxUint64-id)
+func (i *impl) changeVisibility(msgs []*sqs.Message, timeout int64) error {
+	for len(msgs) > 0 {
+		splitPos := len(msgs)
+		if splitPos > maxBatchEntries {
+			splitPos = maxBatchEntries
+		}
+
+		entries := make([]*sqs.ChangeMessageVisibilityBatchRequestEntry, 0, splitPos)
+		for _, m := range msgs[:splitPos] {
+			entries = append(entries, &sqs.ChangeMessageVisibilityBatchRequestEntry{
+				Id:                m.MessageId,
+				ReceiptHandle:     m.ReceiptHandle,
+				VisibilityTimeout: aws.Int64(timeout),
+			})
+		}
+		msgs = msgs[splitPos:]
+
+		if _, err := i.sqs.ChangeMessageVisibilityBatch(&sqs.ChangeMessageVisibilityBatchInput{
+			Entries:  entries,
+			QueueUrl: aws.String(i.queueURL),
+		}); err != nil {
+			return fmt.Errorf("failed to change messages visibility: %w", err)
+		}
+	}
+
+	return nil
 }
@@ -9,6 +9,7 @@ import (
 	"io"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
@@ -109,7 +110,7 @@ func TestImpl_ProcessMessage(t *testing.T) {
 	is := storagemock.NewMockIndexStorage(ctrl)
 	b := blockchainmock.NewMockBlockchain(ctrl)
 
-	i := New(fs, is, b, c, queueURL)
+	i := New(fs, is, b, c, queueURL, 5, 2, 10, time.Minute)
 	p := sqsproducer.New(c, queueURL)
 
 	addr1, addr2 := "addr1", "addr2"
@@ -141,7 +142,7 @@ func TestImpl_ProcessMessage(t *testing.T) {
 	is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(s storage.IndexStorage) error) error {
 		defer cancel()
 		return f(is)
-	})
+	}).Times(2)
 	is.EXPECT().GetPDVMeta(gomock.Any(), addr1, uint64(1)).Return(&entities.PDVMeta{}, nil)
 	is.EXPECT().GetPDVMeta(gomock.Any(), addr2, uint64(2)).Return(nil, storage.ErrNotFound)
 	fs.EXPECT().Write(
@@ -157,17 +158,59 @@ func TestImpl_ProcessMessage(t *testing.T) {
 		require.Equal(t, `{"id": 2}`, string(b))
 		return "2", nil
 	})
+	is.EXPECT().HasActiveRewardTx(gomock.Any(), addr2, uint64(2)).Return(false, nil)
+	is.EXPECT().CreateRewardTx(gomock.Any(), gomock.Any()).Return(uint64(1), nil)
 	b.EXPECT().DistributeRewards([]blockchain.Reward{{
 		Receiver: addr2,
 		ID:       2,
 		Reward:   sdk.NewDecWithPrec(2, 6),
-	}}).Return("tx", nil)
-	is.EXPECT().SetPDVMeta(gomock.Any(), addr2, uint64(2), "tx", &entities.PDVMeta{
+	}}, "reward_tx:1").Return("tx", nil)
+	is.EXPECT().SetRewardTxStatus(gomock.Any(), uint64(1), storage.RewardTxBroadcast, "tx", "")
+	b.EXPECT().WaitForTx(gomock.Any(), "tx").Return(blockchain.TxSucceeded, nil)
+	is.EXPECT().SetRewardTxStatus(gomock.Any(), uint64(1), storage.RewardTxConfirmed, "tx", "")
+	is.EXPECT().SetPDVMeta(gomock.Any(), addr2, uint64(2), "tx", "", &entities.PDVMeta{
 		ObjectTypes: map[schema.Type]uint16{
 			schema.PDVCookieType: 2,
 		},
 		Reward: sdk.NewDecWithPrec(2, 6),
-	}, "android")
+	})
+
+	require.ErrorIs(t, i.Run(ctx), context.Canceled)
+
+	attr, err := c.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+		AttributeNames: []*string{aws.String(sqs.QueueAttributeNameAll)},
+		QueueUrl:       aws.String(queueURL),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "0", *attr.Attributes[sqs.QueueAttributeNameApproximateNumberOfMessages])
+	require.Equal(t, "0", *attr.Attributes[sqs.QueueAttributeNameApproximateNumberOfMessagesNotVisible])
+}
+
+func TestImpl_Quarantine(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fs := storagemock.NewMockFileStorage(ctrl)
+	is := storagemock.NewMockIndexStorage(ctrl)
+	b := blockchainmock.NewMockBlockchain(ctrl)
+
+	i := New(fs, is, b, c, queueURL, 5, 2, 10, time.Minute)
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	_, err := c.SendMessage(&sqs.SendMessageInput{
+		MessageBody: aws.String("malformed"),
+		QueueUrl:    aws.String(queueURL),
+	})
+	require.NoError(t, err)
+
+	is.EXPECT().CreateQuarantineItem(gomock.Any(), "malformed", gomock.Any(), 1).DoAndReturn(
+		func(_ context.Context, _, reason string, _ int) error {
+			defer cancel()
+			require.Contains(t, reason, "failed to unmarshal message")
+			return nil
+		},
+	)
 
 	require.ErrorIs(t, i.Run(ctx), context.Canceled)
 
@@ -1,14 +1,53 @@
 // Package crypto contains encrypting and decrypting reader.
 package crypto
 
-import "io"
+import (
+	"context"
+	"errors"
+	"io"
+)
 
 //go:generate mockgen -destination=./mock/crypto.go -package=mock -source=crypto.go
 
+// ErrKeyNotFound means that owner doesn't have a data key, e.g. it was deleted on account reset.
+var ErrKeyNotFound = errors.New("key not found")
+
 // Crypto provide Reader and Writer wrappers.
 type Crypto interface {
 	// Encrypt returns reader with encrypted src data and size of encrypted data.
 	Encrypt([]byte) ([]byte, error)
+	// EncryptReader returns reader with encrypted src data and size of encrypted data.
+	// Size is -1 when it can't be calculated in advance.
+	EncryptReader(io.Reader) (io.Reader, int64)
 	// Decrypt returns reader with decrypted src data.
 	Decrypt(io.Reader) (io.Reader, error)
+
+	// ActiveKeyID returns id of the key which is used to encrypt data.
+	ActiveKeyID() string
+	// KeyID returns id of the key which src data was encrypted with.
+	KeyID(io.Reader) (string, error)
+}
+
+// OwnerCrypto provides Crypto bound to owner's data key.
+type OwnerCrypto interface {
+	// ForOwner returns Crypto which encrypts data with owner's data key, the key is created if owner doesn't have one.
+	// The returned Crypto is also able to decrypt owner's data encrypted with master keys.
+	ForOwner(ctx context.Context, owner string) (Crypto, error)
+	// ForOwnerRead returns Crypto which decrypts owner's data. It never creates a key, so Decrypt returns
+	// ErrKeyNotFound for data encrypted with owner's data key if owner doesn't have one.
+	ForOwnerRead(ctx context.Context, owner string) (Crypto, error)
+}
+
+// KeyProvider wraps and unwraps keys with master keys which never leave the provider, e.g. KMS.
+// Provider could hold several master keys, so keys wrapped with previous master key can be rewrapped with the active one.
+type KeyProvider interface {
+	// WrapKey returns key encrypted with master key.
+	WrapKey(ctx context.Context, key []byte) ([]byte, error)
+	// UnwrapKey returns key decrypted from wrapped one.
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error)
+
+	// ActiveKeyID returns id of the master key which wraps keys.
+	ActiveKeyID() string
+	// KeyID returns id of the master key which wrapped the key.
+	KeyID(wrapped []byte) (string, error)
 }
@@ -0,0 +1,132 @@
+// Package envelope contains per-owner implementation of crypto.OwnerCrypto interface.
+// Every owner has own data key which is wrapped by key provider and stored in index storage.
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	icrypto "github.com/Decentr-net/cerberus/internal/crypto"
+	"github.com/Decentr-net/cerberus/internal/crypto/sio"
+	"github.com/Decentr-net/cerberus/internal/storage"
+)
+
+// DataKeyID is id of owner's data key in envelope header.
+const DataKeyID = "owner"
+
+const keySize = 32
+
+var errInvalidKeyLength = errors.New("invalid key length")
+
+type envelope struct {
+	master icrypto.Crypto
+	kp     icrypto.KeyProvider
+	is     storage.IndexStorage
+}
+
+// New returns envelope implementation of crypto.OwnerCrypto interface.
+// master is sio keyring which decrypts data encrypted before owners' keys were introduced.
+// kp is used to wrap and unwrap owners' data keys.
+func New(master icrypto.Crypto, kp icrypto.KeyProvider, is storage.IndexStorage) icrypto.OwnerCrypto {
+	return &envelope{
+		master: master,
+		kp:     kp,
+		is:     is,
+	}
+}
+
+// ForOwner returns Crypto which encrypts data with owner's data key. The key is created if owner doesn't have one.
+func (e *envelope) ForOwner(ctx context.Context, owner string) (icrypto.Crypto, error) {
+	key, err := e.dataKey(ctx, owner, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return sio.WithKey(e.master, DataKeyID, key)
+}
+
+// ForOwnerRead returns Crypto which decrypts data with owner's data key or master keyring according to key id in header.
+// Key isn't created on read, so data of reset owner stays unreadable, but data encrypted with master keyring
+// is still decrypted if owner doesn't have a data key.
+func (e *envelope) ForOwnerRead(ctx context.Context, owner string) (icrypto.Crypto, error) {
+	key, err := e.dataKey(ctx, owner, false)
+	if errors.Is(err, icrypto.ErrKeyNotFound) {
+		return masterOnly{Crypto: e.master}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return sio.WithKey(e.master, DataKeyID, key)
+}
+
+func (e *envelope) dataKey(ctx context.Context, owner string, create bool) ([keySize]byte, error) {
+	var key [keySize]byte
+
+	wrapped, err := e.is.GetDataKey(ctx, owner)
+	if errors.Is(err, storage.ErrNotFound) {
+		if !create {
+			return key, icrypto.ErrKeyNotFound
+		}
+		wrapped, err = e.createDataKey(ctx, owner)
+	}
+	if err != nil {
+		return key, fmt.Errorf("failed to get data key: %w", err)
+	}
+
+	b, err := e.kp.UnwrapKey(ctx, wrapped)
+	if err != nil {
+		return key, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+
+	if len(b) != keySize {
+		return key, fmt.Errorf("%w: %d", errInvalidKeyLength, len(b))
+	}
+	copy(key[:], b)
+
+	return key, nil
+}
+
+func (e *envelope) createDataKey(ctx context.Context, owner string) ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	wrapped, err := e.kp.WrapKey(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap key: %w", err)
+	}
+
+	if err := e.is.CreateDataKey(ctx, owner, wrapped); err != nil {
+		return nil, fmt.Errorf("failed to create key: %w", err)
+	}
+
+	// the key could be created concurrently, so we return the stored one
+	return e.is.GetDataKey(ctx, owner)
+}
+
+// masterOnly decrypts data with master keyring when owner doesn't have a data key.
+type masterOnly struct {
+	icrypto.Crypto
+}
+
+// Decrypt returns ErrKeyNotFound if src is encrypted with owner's data key.
+func (c masterOnly) Decrypt(src io.Reader) (io.Reader, error) {
+	var h bytes.Buffer
+
+	id, err := c.KeyID(io.TeeReader(src, &h))
+	if err != nil {
+		return nil, err
+	}
+
+	if id == DataKeyID {
+		return nil, icrypto.ErrKeyNotFound
+	}
+
+	return c.Crypto.Decrypt(io.MultiReader(&h, src))
+}
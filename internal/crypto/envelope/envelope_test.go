@@ -0,0 +1,135 @@
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	icrypto "github.com/Decentr-net/cerberus/internal/crypto"
+	"github.com/Decentr-net/cerberus/internal/crypto/local"
+	"github.com/Decentr-net/cerberus/internal/crypto/sio"
+	"github.com/Decentr-net/cerberus/internal/storage"
+	"github.com/Decentr-net/cerberus/internal/storage/mock"
+)
+
+var (
+	ctx   = context.Background()
+	owner = "owner"
+)
+
+func newKeyProvider(t *testing.T) icrypto.KeyProvider {
+	path := filepath.Join(t.TempDir(), "key.json")
+	require.NoError(t, local.GenerateKeyFile(path, "passphrase"))
+
+	kp, err := local.NewKeyProvider(map[string]string{"1": path}, "passphrase", "1")
+	require.NoError(t, err)
+
+	return kp
+}
+
+func TestEnvelope_ForOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := mock.NewMockIndexStorage(ctrl)
+
+	kp := newKeyProvider(t)
+
+	legacyKey, err := kp.WrapKey(ctx, make([]byte, 32))
+	require.NoError(t, err)
+	mc, err := sio.NewCrypto(ctx, kp, map[string][]byte{sio.LegacyKeyID: legacyKey}, sio.LegacyKeyID)
+	require.NoError(t, err)
+
+	e := New(mc, kp, is)
+
+	var wrapped []byte
+	is.EXPECT().GetDataKey(ctx, owner).Return(nil, storage.ErrNotFound)
+	is.EXPECT().CreateDataKey(ctx, owner, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, key []byte) error {
+		wrapped = key
+		return nil
+	})
+	is.EXPECT().GetDataKey(ctx, owner).DoAndReturn(func(context.Context, string) ([]byte, error) {
+		return wrapped, nil
+	}).Times(2)
+
+	c, err := e.ForOwner(ctx, owner)
+	require.NoError(t, err)
+	require.Equal(t, DataKeyID, c.ActiveKeyID())
+
+	enc, err := c.Encrypt([]byte("data"))
+	require.NoError(t, err)
+
+	// the same key is returned for the next call
+	c, err = e.ForOwner(ctx, owner)
+	require.NoError(t, err)
+
+	dec, err := c.Decrypt(bytes.NewReader(enc))
+	require.NoError(t, err)
+	act, err := ioutil.ReadAll(dec)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data"), act)
+
+	// master keyring can't decrypt owner's data
+	_, err = mc.Decrypt(bytes.NewReader(enc))
+	require.ErrorIs(t, err, sio.ErrUnknownKey)
+
+	// data encrypted with master keyring is still readable
+	legacy, err := mc.Encrypt([]byte("legacy"))
+	require.NoError(t, err)
+
+	dec, err = c.Decrypt(bytes.NewReader(legacy))
+	require.NoError(t, err)
+	act, err = ioutil.ReadAll(dec)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("legacy"), act)
+}
+
+func TestEnvelope_ForOwnerRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := mock.NewMockIndexStorage(ctrl)
+
+	kp := newKeyProvider(t)
+
+	legacyKey, err := kp.WrapKey(ctx, make([]byte, 32))
+	require.NoError(t, err)
+	mc, err := sio.NewCrypto(ctx, kp, map[string][]byte{sio.LegacyKeyID: legacyKey}, sio.LegacyKeyID)
+	require.NoError(t, err)
+
+	e := New(mc, kp, is)
+
+	wrapped, err := kp.WrapKey(ctx, make([]byte, 32))
+	require.NoError(t, err)
+	is.EXPECT().GetDataKey(ctx, owner).Return(wrapped, nil)
+
+	c, err := e.ForOwner(ctx, owner)
+	require.NoError(t, err)
+	enc, err := c.Encrypt([]byte("data"))
+	require.NoError(t, err)
+
+	legacy, err := mc.Encrypt([]byte("legacy"))
+	require.NoError(t, err)
+
+	// key isn't created on read
+	is.EXPECT().GetDataKey(ctx, owner).Return(nil, storage.ErrNotFound)
+
+	c, err = e.ForOwnerRead(ctx, owner)
+	require.NoError(t, err)
+
+	// data encrypted with master keyring is readable without data key
+	dec, err := c.Decrypt(bytes.NewReader(legacy))
+	require.NoError(t, err)
+	act, err := ioutil.ReadAll(dec)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("legacy"), act)
+
+	_, err = c.Decrypt(bytes.NewReader(enc))
+	require.ErrorIs(t, err, icrypto.ErrKeyNotFound)
+}
@@ -0,0 +1,238 @@
+// Package local contains passphrase-protected key file implementation of crypto.KeyProvider interface.
+// It's a stand-in for KMS which could be used for development and testing.
+package local
+
+import (
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+
+	icrypto "github.com/Decentr-net/cerberus/internal/crypto"
+)
+
+// argon2id parameters for new key files.
+const (
+	argonTime    = 3
+	argonMemory  = 64 * 1024
+	argonThreads = 4
+	saltSize     = 16
+)
+
+// ErrInvalidPassphrase is returned when key file can't be opened with the passphrase.
+var ErrInvalidPassphrase = errors.New("invalid passphrase")
+
+// magic prefixes wrapped key header which contains id of master key.
+var magic = []byte{'C', 'K', 'P', 0x01}
+
+const maxKeyIDLength = 255
+
+var (
+	errInvalidKeyID      = errors.New("invalid key id")
+	errInvalidWrappedKey = errors.New("invalid wrapped key")
+	errUnknownKey        = errors.New("unknown key")
+)
+
+var _ icrypto.KeyProvider = &provider{}
+
+// keyFile is a key file content. Master key is sealed with a key derived from the passphrase.
+type keyFile struct {
+	Salt    []byte `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+	Nonce   []byte `json:"nonce"`
+	Key     []byte `json:"key"`
+}
+
+type provider struct {
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+// NewKeyProvider returns crypto.KeyProvider which uses master keys from the key files by id protected by the passphrase.
+// Keys are wrapped with active master key and its id is written into the wrapped key, so the key can be unwrapped
+// while the master key is in provider. Keys wrapped before ids were introduced are unwrapped with any master key.
+func NewKeyProvider(files map[string]string, passphrase, active string) (icrypto.KeyProvider, error) {
+	if _, ok := files[active]; !ok {
+		return nil, fmt.Errorf("active key %s: %w", active, errUnknownKey)
+	}
+
+	p := provider{
+		active: active,
+		keys:   make(map[string]cipher.AEAD, len(files)),
+	}
+
+	for id, path := range files {
+		if id == "" || len(id) > maxKeyIDLength {
+			return nil, fmt.Errorf("%w: %q", errInvalidKeyID, id)
+		}
+
+		aead, err := openKeyFile(path, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", id, err)
+		}
+
+		p.keys[id] = aead
+	}
+
+	return &p, nil
+}
+
+func openKeyFile(path, passphrase string) (cipher.AEAD, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	var f keyFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal key file: %w", err)
+	}
+
+	aead, err := chacha20poly1305.NewX(argon2.IDKey([]byte(passphrase), f.Salt, f.Time, f.Memory, f.Threads, chacha20poly1305.KeySize))
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := aead.Open(nil, f.Nonce, f.Key, nil)
+	if err != nil {
+		return nil, ErrInvalidPassphrase
+	}
+
+	return chacha20poly1305.NewX(key)
+}
+
+// GenerateKeyFile creates a key file with a new random master key protected by the passphrase.
+func GenerateKeyFile(path, passphrase string) error {
+	f := keyFile{
+		Salt:    make([]byte, saltSize),
+		Time:    argonTime,
+		Memory:  argonMemory,
+		Threads: argonThreads,
+		Nonce:   make([]byte, chacha20poly1305.NonceSizeX),
+	}
+
+	key := make([]byte, chacha20poly1305.KeySize)
+	for _, v := range [][]byte{f.Salt, f.Nonce, key} {
+		if _, err := rand.Read(v); err != nil {
+			return fmt.Errorf("failed to generate random: %w", err)
+		}
+	}
+
+	aead, err := chacha20poly1305.NewX(argon2.IDKey([]byte(passphrase), f.Salt, f.Time, f.Memory, f.Threads, chacha20poly1305.KeySize))
+	if err != nil {
+		return err
+	}
+	f.Key = aead.Seal(nil, f.Nonce, key, nil)
+
+	b, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("failed to marshal key file: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create key file: %w", err)
+	}
+	defer file.Close() // nolint
+
+	if _, err := file.Write(b); err != nil {
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+
+	return file.Sync()
+}
+
+// ActiveKeyID returns id of the master key which wraps keys.
+func (p *provider) ActiveKeyID() string {
+	return p.active
+}
+
+// KeyID returns id of the master key which wrapped the key. Empty id means that key was wrapped before ids were introduced.
+func (p *provider) KeyID(wrapped []byte) (string, error) {
+	id, _, err := readHeader(wrapped)
+	return id, err
+}
+
+// WrapKey returns key encrypted with active master key.
+func (p *provider) WrapKey(_ context.Context, key []byte) ([]byte, error) {
+	aead := p.keys[p.active]
+
+	h := header(p.active)
+
+	nonce := make([]byte, aead.NonceSize(), len(h)+aead.NonceSize()+len(key)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return append(h, aead.Seal(nonce, nonce, key, nil)...), nil
+}
+
+// UnwrapKey returns key decrypted from wrapped one.
+func (p *provider) UnwrapKey(_ context.Context, wrapped []byte) ([]byte, error) {
+	id, b, err := readHeader(wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	if id == "" {
+		for _, aead := range p.keys {
+			if key, err := open(aead, b); err == nil {
+				return key, nil
+			}
+		}
+		return nil, errInvalidWrappedKey
+	}
+
+	aead, ok := p.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("key %s: %w", id, errUnknownKey)
+	}
+
+	return open(aead, b)
+}
+
+func open(aead cipher.AEAD, b []byte) ([]byte, error) {
+	if len(b) < aead.NonceSize() {
+		return nil, errInvalidWrappedKey
+	}
+
+	key, err := aead.Open(nil, b[:aead.NonceSize()], b[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, errInvalidWrappedKey
+	}
+
+	return key, nil
+}
+
+// header returns wrapped key header: magic, length of key id and key id.
+func header(id string) []byte {
+	h := make([]byte, 0, len(magic)+1+len(id))
+	h = append(h, magic...)
+	h = append(h, byte(len(id)))
+	return append(h, id...)
+}
+
+// readHeader returns master key id and the rest of wrapped key. Id is empty if wrapped key doesn't have header.
+func readHeader(wrapped []byte) (string, []byte, error) {
+	if !bytes.HasPrefix(wrapped, magic) || len(wrapped) == len(magic) {
+		return "", wrapped, nil
+	}
+
+	b := wrapped[len(magic):]
+	l := int(b[0])
+	if len(b) < 1+l {
+		return "", nil, errInvalidWrappedKey
+	}
+
+	return string(b[1 : 1+l]), b[1+l:], nil
+}
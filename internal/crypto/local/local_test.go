@@ -0,0 +1,102 @@
+package local
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyProvider(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "key.json")
+
+	require.NoError(t, GenerateKeyFile(path, "passphrase"))
+	require.Error(t, GenerateKeyFile(path, "passphrase"), "key file shouldn't be overwritten")
+
+	_, err := NewKeyProvider(map[string]string{"1": path}, "wrong", "1")
+	require.ErrorIs(t, err, ErrInvalidPassphrase)
+
+	_, err = NewKeyProvider(map[string]string{"1": path}, "passphrase", "2")
+	require.ErrorIs(t, err, errUnknownKey)
+
+	kp, err := NewKeyProvider(map[string]string{"1": path}, "passphrase", "1")
+	require.NoError(t, err)
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	wrapped, err := kp.WrapKey(ctx, key)
+	require.NoError(t, err)
+	assert.NotContains(t, string(wrapped), string(key))
+
+	id, err := kp.KeyID(wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, "1", id)
+
+	// the same master key is used after reopening
+	kp, err = NewKeyProvider(map[string]string{"1": path}, "passphrase", "1")
+	require.NoError(t, err)
+
+	act, err := kp.UnwrapKey(ctx, wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, key, act)
+
+	wrapped[len(wrapped)-1] ^= 0xff
+	_, err = kp.UnwrapKey(ctx, wrapped)
+	require.ErrorIs(t, err, errInvalidWrappedKey)
+}
+
+func TestKeyProvider_Rotation(t *testing.T) {
+	ctx := context.Background()
+	path1 := filepath.Join(t.TempDir(), "key1.json")
+	path2 := filepath.Join(t.TempDir(), "key2.json")
+
+	require.NoError(t, GenerateKeyFile(path1, "passphrase"))
+	require.NoError(t, GenerateKeyFile(path2, "passphrase"))
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	kp, err := NewKeyProvider(map[string]string{"1": path1}, "passphrase", "1")
+	require.NoError(t, err)
+
+	wrapped, err := kp.WrapKey(ctx, key)
+	require.NoError(t, err)
+
+	// key wrapped before ids were introduced
+	nonce := make([]byte, kp.(*provider).keys["1"].NonceSize())
+	legacy := kp.(*provider).keys["1"].Seal(nonce, nonce, key, nil)
+
+	id, err := kp.KeyID(legacy)
+	require.NoError(t, err)
+	assert.Empty(t, id)
+
+	kp, err = NewKeyProvider(map[string]string{"1": path1, "2": path2}, "passphrase", "2")
+	require.NoError(t, err)
+	assert.Equal(t, "2", kp.ActiveKeyID())
+
+	for _, v := range [][]byte{wrapped, legacy} {
+		act, err := kp.UnwrapKey(ctx, v)
+		require.NoError(t, err)
+		assert.Equal(t, key, act)
+	}
+
+	rewrapped, err := kp.WrapKey(ctx, key)
+	require.NoError(t, err)
+
+	id, err = kp.KeyID(rewrapped)
+	require.NoError(t, err)
+	assert.Equal(t, "2", id)
+
+	// key can't be unwrapped when master key is removed from provider
+	kp, err = NewKeyProvider(map[string]string{"2": path2}, "passphrase", "2")
+	require.NoError(t, err)
+
+	_, err = kp.UnwrapKey(ctx, wrapped)
+	require.ErrorIs(t, err, errUnknownKey)
+
+	act, err := kp.UnwrapKey(ctx, rewrapped)
+	require.NoError(t, err)
+	assert.Equal(t, key, act)
+}
@@ -5,6 +5,8 @@
 package mock
 
 import (
+	context "context"
+	crypto "github.com/Decentr-net/cerberus/internal/crypto"
 	gomock "github.com/golang/mock/gomock"
 	io "io"
 	reflect "reflect"
@@ -48,6 +50,21 @@ func (mr *MockCryptoMockRecorder) Encrypt(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Encrypt", reflect.TypeOf((*MockCrypto)(nil).Encrypt), arg0)
 }
 
+// EncryptReader mocks base method
+func (m *MockCrypto) EncryptReader(arg0 io.Reader) (io.Reader, int64) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EncryptReader", arg0)
+	ret0, _ := ret[0].(io.Reader)
+	ret1, _ := ret[1].(int64)
+	return ret0, ret1
+}
+
+// EncryptReader indicates an expected call of EncryptReader
+func (mr *MockCryptoMockRecorder) EncryptReader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptReader", reflect.TypeOf((*MockCrypto)(nil).EncryptReader), arg0)
+}
+
 // Decrypt mocks base method
 func (m *MockCrypto) Decrypt(arg0 io.Reader) (io.Reader, error) {
 	m.ctrl.T.Helper()
@@ -62,3 +79,167 @@ func (mr *MockCryptoMockRecorder) Decrypt(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decrypt", reflect.TypeOf((*MockCrypto)(nil).Decrypt), arg0)
 }
+
+// ActiveKeyID mocks base method
+func (m *MockCrypto) ActiveKeyID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActiveKeyID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ActiveKeyID indicates an expected call of ActiveKeyID
+func (mr *MockCryptoMockRecorder) ActiveKeyID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActiveKeyID", reflect.TypeOf((*MockCrypto)(nil).ActiveKeyID))
+}
+
+// KeyID mocks base method
+func (m *MockCrypto) KeyID(arg0 io.Reader) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KeyID", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// KeyID indicates an expected call of KeyID
+func (mr *MockCryptoMockRecorder) KeyID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeyID", reflect.TypeOf((*MockCrypto)(nil).KeyID), arg0)
+}
+
+// MockOwnerCrypto is a mock of OwnerCrypto interface
+type MockOwnerCrypto struct {
+	ctrl     *gomock.Controller
+	recorder *MockOwnerCryptoMockRecorder
+}
+
+// MockOwnerCryptoMockRecorder is the mock recorder for MockOwnerCrypto
+type MockOwnerCryptoMockRecorder struct {
+	mock *MockOwnerCrypto
+}
+
+// NewMockOwnerCrypto creates a new mock instance
+func NewMockOwnerCrypto(ctrl *gomock.Controller) *MockOwnerCrypto {
+	mock := &MockOwnerCrypto{ctrl: ctrl}
+	mock.recorder = &MockOwnerCryptoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockOwnerCrypto) EXPECT() *MockOwnerCryptoMockRecorder {
+	return m.recorder
+}
+
+// ForOwner mocks base method
+func (m *MockOwnerCrypto) ForOwner(ctx context.Context, owner string) (crypto.Crypto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForOwner", ctx, owner)
+	ret0, _ := ret[0].(crypto.Crypto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForOwner indicates an expected call of ForOwner
+func (mr *MockOwnerCryptoMockRecorder) ForOwner(ctx, owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForOwner", reflect.TypeOf((*MockOwnerCrypto)(nil).ForOwner), ctx, owner)
+}
+
+// ForOwnerRead mocks base method
+func (m *MockOwnerCrypto) ForOwnerRead(ctx context.Context, owner string) (crypto.Crypto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForOwnerRead", ctx, owner)
+	ret0, _ := ret[0].(crypto.Crypto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForOwnerRead indicates an expected call of ForOwnerRead
+func (mr *MockOwnerCryptoMockRecorder) ForOwnerRead(ctx, owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForOwnerRead", reflect.TypeOf((*MockOwnerCrypto)(nil).ForOwnerRead), ctx, owner)
+}
+
+// MockKeyProvider is a mock of KeyProvider interface
+type MockKeyProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyProviderMockRecorder
+}
+
+// MockKeyProviderMockRecorder is the mock recorder for MockKeyProvider
+type MockKeyProviderMockRecorder struct {
+	mock *MockKeyProvider
+}
+
+// NewMockKeyProvider creates a new mock instance
+func NewMockKeyProvider(ctrl *gomock.Controller) *MockKeyProvider {
+	mock := &MockKeyProvider{ctrl: ctrl}
+	mock.recorder = &MockKeyProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockKeyProvider) EXPECT() *MockKeyProviderMockRecorder {
+	return m.recorder
+}
+
+// WrapKey mocks base method
+func (m *MockKeyProvider) WrapKey(ctx context.Context, key []byte) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WrapKey", ctx, key)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WrapKey indicates an expected call of WrapKey
+func (mr *MockKeyProviderMockRecorder) WrapKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WrapKey", reflect.TypeOf((*MockKeyProvider)(nil).WrapKey), ctx, key)
+}
+
+// UnwrapKey mocks base method
+func (m *MockKeyProvider) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnwrapKey", ctx, wrapped)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnwrapKey indicates an expected call of UnwrapKey
+func (mr *MockKeyProviderMockRecorder) UnwrapKey(ctx, wrapped interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnwrapKey", reflect.TypeOf((*MockKeyProvider)(nil).UnwrapKey), ctx, wrapped)
+}
+
+// ActiveKeyID mocks base method
+func (m *MockKeyProvider) ActiveKeyID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActiveKeyID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ActiveKeyID indicates an expected call of ActiveKeyID
+func (mr *MockKeyProviderMockRecorder) ActiveKeyID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActiveKeyID", reflect.TypeOf((*MockKeyProvider)(nil).ActiveKeyID))
+}
+
+// KeyID mocks base method
+func (m *MockKeyProvider) KeyID(wrapped []byte) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KeyID", wrapped)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// KeyID indicates an expected call of KeyID
+func (mr *MockKeyProviderMockRecorder) KeyID(wrapped interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeyID", reflect.TypeOf((*MockKeyProvider)(nil).KeyID), wrapped)
+}
@@ -3,6 +3,9 @@ package sio
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"io"
 
 	"github.com/minio/sio"
@@ -10,31 +13,203 @@ import (
 	icrypto "github.com/Decentr-net/cerberus/internal/crypto"
 )
 
+// LegacyKeyID is id of the key which is used to decrypt data without header,
+// i.e. data encrypted before keys rotation was introduced.
+const LegacyKeyID = "legacy"
+
+// magic prefixes envelope header. It can't be confused with sio stream since sio's stream starts with version byte.
+var magic = []byte{'C', 'R', 'B', 0x01}
+
+const (
+	keySize        = 32
+	maxKeyIDLength = 255
+)
+
+// ErrUnknownKey is returned when data is encrypted with a key which is missing in keyring.
+var ErrUnknownKey = errors.New("unknown key")
+
+var (
+	errInvalidKeyID   = errors.New("invalid key id")
+	errInvalidKeySize = errors.New("invalid key size")
+	errNoActiveKey    = errors.New("no active key")
+	errNotSio         = errors.New("crypto is not sio implementation")
+)
+
 type crypto struct {
-	c sio.Config
+	active string
+	keys   map[string]sio.Config
 }
 
 // NewCrypto returns minio/sio implementation of crypto.Crypto interface.
-func NewCrypto(key [32]byte) icrypto.Crypto {
-	return &crypto{
-		c: sio.Config{
-			MinVersion: sio.Version20,
-			Key:        key[:],
-		},
+// keys is a keyring of wrapped keys by id, the keys are unwrapped with kp. New data is encrypted
+// with active key and key's id is written into envelope header, so data can be decrypted while the key is in keyring.
+// Data without header is decrypted with LegacyKeyID key. Empty active means that crypto can only decrypt data.
+func NewCrypto(ctx context.Context, kp icrypto.KeyProvider, keys map[string][]byte, active string) (icrypto.Crypto, error) {
+	if _, ok := keys[active]; !ok && active != "" {
+		return nil, fmt.Errorf("active key %s: %w", active, ErrUnknownKey)
+	}
+
+	c := crypto{
+		active: active,
+		keys:   make(map[string]sio.Config, len(keys)),
+	}
+
+	for id, wrapped := range keys {
+		b, err := kp.UnwrapKey(ctx, wrapped)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unwrap key %s: %w", id, err)
+		}
+
+		if len(b) != keySize {
+			return nil, fmt.Errorf("key %s: %w", id, errInvalidKeySize)
+		}
+
+		var key [keySize]byte
+		copy(key[:], b)
+
+		if err := c.add(id, key); err != nil {
+			return nil, err
+		}
+	}
+
+	return &c, nil
+}
+
+// WithKey returns copy of c with the key added to keyring. The key becomes active.
+func WithKey(c icrypto.Crypto, id string, key [32]byte) (icrypto.Crypto, error) {
+	src, ok := c.(*crypto)
+	if !ok {
+		return nil, errNotSio
+	}
+
+	dst := crypto{
+		active: id,
+		keys:   make(map[string]sio.Config, len(src.keys)+1),
+	}
+
+	for k, v := range src.keys {
+		dst.keys[k] = v
 	}
+
+	if err := dst.add(id, key); err != nil {
+		return nil, err
+	}
+
+	return &dst, nil
+}
+
+func (c *crypto) add(id string, key [keySize]byte) error {
+	if id == "" || len(id) > maxKeyIDLength {
+		return fmt.Errorf("%w: %q", errInvalidKeyID, id)
+	}
+
+	c.keys[id] = sio.Config{
+		MinVersion: sio.Version20,
+		Key:        key[:],
+	}
+
+	return nil
+}
+
+// ActiveKeyID returns id of the key which is used to encrypt data.
+func (c *crypto) ActiveKeyID() string {
+	return c.active
+}
+
+// KeyID returns id of the key which src data was encrypted with.
+func (c *crypto) KeyID(src io.Reader) (string, error) {
+	id, _, err := readHeader(src)
+	return id, err
 }
 
 // Encrypt returns reader with encrypted src data.
 func (c *crypto) Encrypt(src []byte) ([]byte, error) {
-	buf := bytes.NewBuffer([]byte{})
-	_, err := sio.Encrypt(buf, bytes.NewReader(src), c.c)
+	if c.active == "" {
+		return nil, errNoActiveKey
+	}
+
+	buf := bytes.NewBuffer(header(c.active))
+	_, err := sio.Encrypt(buf, bytes.NewReader(src), c.keys[c.active])
 	if err != nil {
 		return nil, err
 	}
 	return buf.Bytes(), nil
 }
 
+// EncryptReader returns reader with encrypted src data and size of encrypted data.
+// Size is known only if src reports its length (e.g. *bytes.Reader), otherwise it's -1.
+func (c *crypto) EncryptReader(src io.Reader) (io.Reader, int64) {
+	if c.active == "" {
+		return errReader{err: errNoActiveKey}, -1
+	}
+
+	h := header(c.active)
+
+	size := int64(-1)
+	if l, ok := src.(interface{ Len() int }); ok {
+		if s, err := sio.EncryptedSize(uint64(l.Len())); err == nil {
+			size = int64(s) + int64(len(h))
+		}
+	}
+
+	r, err := sio.EncryptReader(src, c.keys[c.active])
+	if err != nil {
+		return errReader{err: err}, -1
+	}
+
+	return io.MultiReader(bytes.NewReader(h), r), size
+}
+
 // Decrypt returns reader with decrypted src data.
 func (c *crypto) Decrypt(src io.Reader) (io.Reader, error) {
-	return sio.DecryptReader(src, c.c)
+	id, r, err := readHeader(src)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, ok := c.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("key %s: %w", id, ErrUnknownKey)
+	}
+
+	return sio.DecryptReader(r, cfg)
+}
+
+// header returns envelope header: magic, length of key id and key id.
+func header(id string) []byte {
+	h := make([]byte, 0, len(magic)+1+len(id))
+	h = append(h, magic...)
+	h = append(h, byte(len(id)))
+	return append(h, id...)
+}
+
+// readHeader reads envelope header from src and returns key id and reader with the rest of data.
+// Data without header is considered as encrypted with legacy key, the returned reader contains whole data then.
+func readHeader(src io.Reader) (string, io.Reader, error) {
+	prefix := make([]byte, len(magic)+1)
+	n, err := io.ReadFull(src, prefix)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	prefix = prefix[:n]
+
+	if !bytes.HasPrefix(prefix, magic) || n < len(magic)+1 {
+		return LegacyKeyID, io.MultiReader(bytes.NewReader(prefix), src), nil
+	}
+
+	id := make([]byte, prefix[len(magic)])
+	if _, err := io.ReadFull(src, id); err != nil {
+		return "", nil, fmt.Errorf("failed to read key id: %w", err)
+	}
+
+	return string(id), src, nil
+}
+
+// errReader returns err on every read, it's used to pass an error through io.Reader.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
 }
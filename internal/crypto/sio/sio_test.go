@@ -2,13 +2,17 @@ package sio
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"io"
 	"io/ioutil"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	icrypto "github.com/Decentr-net/cerberus/internal/crypto"
 )
 
 var key = [32]byte{
@@ -16,8 +20,38 @@ var key = [32]byte{
 	0xf0, 0xe0, 0xd0, 0xc0, 0xb0, 0xa0, 0x90, 0x80, 0x70, 0x60, 0x50, 0x40, 0x30, 0x20, 0x10, 0x00,
 }
 
+var key2 = [32]byte{
+	0x0f, 0x0e, 0x0d, 0x0c, 0x0b, 0x0a, 0x09, 0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01, 0x00,
+	0x00, 0x10, 0x20, 0x30, 0x40, 0x50, 0x60, 0x70, 0x80, 0x90, 0xa0, 0xb0, 0xc0, 0xd0, 0xe0, 0xf0,
+}
+
+// nopKeyProvider returns keys as is.
+type nopKeyProvider struct{}
+
+func (nopKeyProvider) WrapKey(_ context.Context, key []byte) ([]byte, error) {
+	return key, nil
+}
+
+func (nopKeyProvider) UnwrapKey(_ context.Context, wrapped []byte) ([]byte, error) {
+	return wrapped, nil
+}
+
+func (nopKeyProvider) ActiveKeyID() string {
+	return ""
+}
+
+func (nopKeyProvider) KeyID([]byte) (string, error) {
+	return "", nil
+}
+
+func newCrypto(t *testing.T, keys map[string][]byte, active string) icrypto.Crypto {
+	c, err := NewCrypto(context.Background(), nopKeyProvider{}, keys, active)
+	require.NoError(t, err)
+	return c
+}
+
 func TestCrypto_Encrypt(t *testing.T) {
-	c := NewCrypto(key)
+	c := newCrypto(t, map[string][]byte{LegacyKeyID: key[:]}, LegacyKeyID)
 
 	src := []byte("example")
 
@@ -27,7 +61,7 @@ func TestCrypto_Encrypt(t *testing.T) {
 }
 
 func TestCrypto_Decrypt(t *testing.T) {
-	c := NewCrypto(key)
+	c := newCrypto(t, map[string][]byte{LegacyKeyID: key[:]}, LegacyKeyID)
 
 	src, err := hex.DecodeString("20000600ba67f3d40a97d8cfc64b7a579aa477c453ad0db4e1715afd5a067e666a4d7e3d1ff542")
 	require.NoError(t, err)
@@ -49,7 +83,7 @@ func TestCrypto_Encrypt_Decrypt(t *testing.T) {
 	require.NoError(t, err)
 	require.NotZero(t, n)
 
-	c := NewCrypto(key)
+	c := newCrypto(t, map[string][]byte{LegacyKeyID: key[:]}, LegacyKeyID)
 
 	enc, err := c.Encrypt(exp)
 	require.NoError(t, err)
@@ -63,3 +97,119 @@ func TestCrypto_Encrypt_Decrypt(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, exp, act)
 }
+
+func TestCrypto_EncryptReader(t *testing.T) {
+	exp := make([]byte, 1024*1024)
+	n, err := rand.Read(exp)
+	require.NoError(t, err)
+	require.NotZero(t, n)
+
+	c := newCrypto(t, map[string][]byte{LegacyKeyID: key[:]}, LegacyKeyID)
+
+	t.Run("known size", func(t *testing.T) {
+		r, size := c.EncryptReader(bytes.NewReader(exp))
+		require.NotNil(t, r)
+
+		enc, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		require.EqualValues(t, len(enc), size)
+
+		dec, err := c.Decrypt(bytes.NewReader(enc))
+		require.NoError(t, err)
+
+		act, err := ioutil.ReadAll(dec)
+		require.NoError(t, err)
+		assert.Equal(t, exp, act)
+	})
+
+	t.Run("unknown size", func(t *testing.T) {
+		r, size := c.EncryptReader(io.MultiReader(bytes.NewReader(exp)))
+		require.NotNil(t, r)
+		require.EqualValues(t, -1, size)
+
+		enc, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+
+		dec, err := c.Decrypt(bytes.NewReader(enc))
+		require.NoError(t, err)
+
+		act, err := ioutil.ReadAll(dec)
+		require.NoError(t, err)
+		assert.Equal(t, exp, act)
+	})
+}
+
+func TestNewCrypto(t *testing.T) {
+	_, err := NewCrypto(context.Background(), nopKeyProvider{}, map[string][]byte{LegacyKeyID: key[:]}, "2")
+	require.ErrorIs(t, err, ErrUnknownKey)
+
+	_, err = NewCrypto(context.Background(), nopKeyProvider{}, map[string][]byte{"1": key[:], "": key2[:]}, "1")
+	require.ErrorIs(t, err, errInvalidKeyID)
+
+	_, err = NewCrypto(context.Background(), nopKeyProvider{}, map[string][]byte{"1": key[:16]}, "1")
+	require.ErrorIs(t, err, errInvalidKeySize)
+
+	c, err := NewCrypto(context.Background(), nopKeyProvider{}, map[string][]byte{}, "")
+	require.NoError(t, err)
+	_, err = c.Encrypt([]byte("example"))
+	require.ErrorIs(t, err, errNoActiveKey)
+}
+
+func TestWithKey(t *testing.T) {
+	legacy := newCrypto(t, map[string][]byte{LegacyKeyID: key[:]}, "")
+
+	c, err := WithKey(legacy, "2", key2)
+	require.NoError(t, err)
+	require.Equal(t, "2", c.ActiveKeyID())
+	require.Equal(t, "", legacy.ActiveKeyID())
+
+	enc, err := c.Encrypt([]byte("example"))
+	require.NoError(t, err)
+
+	_, err = legacy.Decrypt(bytes.NewReader(enc))
+	require.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestCrypto_Rotation(t *testing.T) {
+	exp := []byte("example")
+
+	old := newCrypto(t, map[string][]byte{LegacyKeyID: key[:]}, LegacyKeyID)
+	legacy, err := hex.DecodeString("20000600ba67f3d40a97d8cfc64b7a579aa477c453ad0db4e1715afd5a067e666a4d7e3d1ff542")
+	require.NoError(t, err)
+	enc1, err := old.Encrypt(exp)
+	require.NoError(t, err)
+
+	c := newCrypto(t, map[string][]byte{LegacyKeyID: key[:], "2": key2[:]}, "2")
+	require.Equal(t, "2", c.ActiveKeyID())
+
+	enc2, err := c.Encrypt(exp)
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		data  []byte
+		keyID string
+	}{
+		"without header": {data: legacy, keyID: LegacyKeyID},
+		"old key":        {data: enc1, keyID: LegacyKeyID},
+		"active key":     {data: enc2, keyID: "2"},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			id, err := c.KeyID(bytes.NewReader(tc.data))
+			require.NoError(t, err)
+			assert.Equal(t, tc.keyID, id)
+
+			dec, err := c.Decrypt(bytes.NewReader(tc.data))
+			require.NoError(t, err)
+
+			act, err := ioutil.ReadAll(dec)
+			require.NoError(t, err)
+			assert.Equal(t, exp, act)
+		})
+	}
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := old.Decrypt(bytes.NewReader(enc2))
+		require.ErrorIs(t, err, ErrUnknownKey)
+	})
+}
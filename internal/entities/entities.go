@@ -14,6 +14,48 @@ type PDVMeta struct {
 	// ObjectTypes represents how much certain meta data meta contains.
 	ObjectTypes map[schema.Type]uint16 `json:"object_types"`
 	Reward      sdk.Dec                `json:"reward"`
+	// RewardMapVersion is a version of the reward map used to calculate the reward.
+	RewardMapVersion uint64 `json:"reward_map_version"`
+	// Rejected contains data which isn't rewarded.
+	Rejected []RejectedPDV `json:"rejected,omitempty"`
+}
+
+// RejectedPDV is a data which isn't rewarded.
+type RejectedPDV struct {
+	// Index is an index of the data in the batch.
+	Index int `json:"index"`
+	// Rule is a name of the rule which rejected the data.
+	Rule string `json:"rule"`
+}
+
+// PDVRewardsHistoryItem is a distributed PDV reward.
+type PDVRewardsHistoryItem struct {
+	ID uint64
+	// Amount is a reward in uDEC.
+	Amount      int64
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	TxHash      string
+	CreatedAt   time.Time
+}
+
+// BannedProfile is a ban of the profile.
+type BannedProfile struct {
+	Address string
+	// Source is hades for automatic bans or manual for bans made by admins.
+	Source   string
+	Reason   string
+	BannedAt time.Time
+}
+
+// BlacklistEntry is a pattern of worthless pdv attribute.
+type BlacklistEntry struct {
+	ID uint64
+	// Kind is cookie_source or search_engine.
+	Kind      string
+	Pattern   string
+	CreatedBy string
+	CreatedAt time.Time
 }
 
 // Profile ...
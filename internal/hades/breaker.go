@@ -0,0 +1,84 @@
+package hades
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when Hades isn't called after consecutive failures.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+var _ Hades = &breaker{}
+
+// breaker stops calling Hades after threshold consecutive failures. When cooldown passes
+// one probe request is let through, success closes the circuit and failure opens it again.
+type breaker struct {
+	h         Hades
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// WithCircuitBreaker wraps h with circuit breaker which opens after threshold consecutive failures for cooldown.
+func WithCircuitBreaker(h Hades, threshold int, cooldown time.Duration) Hades {
+	return &breaker{
+		h:         h,
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// AntiFraud check the given PDV for fraud. It returns ErrCircuitOpen if the circuit is open.
+func (b *breaker) AntiFraud(ctx context.Context, r *AntiFraudRequest) (*AntiFraudResponse, error) {
+	if !b.allow() {
+		return nil, ErrCircuitOpen
+	}
+
+	resp, err := b.h.AntiFraud(ctx, r)
+
+	// canceled request says nothing about Hades availability
+	b.done(err == nil, errors.Is(err, context.Canceled))
+
+	return resp, err
+}
+
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+
+	if b.probing || time.Since(b.openedAt) < b.cooldown {
+		return false
+	}
+
+	b.probing = true
+
+	return true
+}
+
+func (b *breaker) done(success, canceled bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+
+	switch {
+	case success:
+		b.failures = 0
+	case canceled:
+	default:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.openedAt = time.Now()
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package hades
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type hadesFunc func() (*AntiFraudResponse, error)
+
+func (f hadesFunc) AntiFraud(context.Context, *AntiFraudRequest) (*AntiFraudResponse, error) {
+	return f()
+}
+
+func TestBreaker(t *testing.T) {
+	var (
+		calls int
+		err   = errors.New("unavailable")
+	)
+
+	h := WithCircuitBreaker(hadesFunc(func() (*AntiFraudResponse, error) {
+		calls++
+		if err != nil {
+			return nil, err
+		}
+		return &AntiFraudResponse{}, nil
+	}), 2, 50*time.Millisecond)
+
+	call := func() error {
+		_, err := h.AntiFraud(context.Background(), &AntiFraudRequest{})
+		return err
+	}
+
+	// canceled requests aren't failures
+	err = context.Canceled
+	require.ErrorIs(t, call(), context.Canceled)
+	require.ErrorIs(t, call(), context.Canceled)
+
+	err = errors.New("unavailable")
+	require.Error(t, call())
+	require.Error(t, call())
+	require.ErrorIs(t, call(), ErrCircuitOpen)
+	require.Equal(t, 4, calls)
+
+	// failed probe opens the circuit again
+	time.Sleep(60 * time.Millisecond)
+	require.NotErrorIs(t, call(), ErrCircuitOpen)
+	require.ErrorIs(t, call(), ErrCircuitOpen)
+	require.Equal(t, 5, calls)
+
+	// successful probe closes the circuit
+	time.Sleep(60 * time.Millisecond)
+	err = nil
+	require.NoError(t, call())
+	require.NoError(t, call())
+	require.Equal(t, 7, calls)
+}
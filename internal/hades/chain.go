@@ -0,0 +1,37 @@
+package hades
+
+import (
+	"context"
+)
+
+var _ Hades = chain{}
+
+type chain []Hades
+
+// Chain checks PDV with every Hades in order until fraud is detected,
+// e.g. built-in rules first and remote Hades as a second opinion. An error of any Hades is returned.
+func Chain(hh ...Hades) Hades {
+	if len(hh) == 1 {
+		return hh[0]
+	}
+
+	return chain(hh)
+}
+
+// AntiFraud check the given PDV for fraud.
+func (c chain) AntiFraud(ctx context.Context, r *AntiFraudRequest) (*AntiFraudResponse, error) {
+	resp := &AntiFraudResponse{}
+
+	for _, h := range c {
+		var err error
+		if resp, err = h.AntiFraud(ctx, r); err != nil {
+			return nil, err
+		}
+
+		if resp.IsFraud {
+			return resp, nil
+		}
+	}
+
+	return resp, nil
+}
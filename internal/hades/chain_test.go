@@ -0,0 +1,66 @@
+package hades
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain(t *testing.T) {
+	var calls []string
+
+	h := func(name string, resp *AntiFraudResponse, err error) Hades {
+		return hadesFunc(func() (*AntiFraudResponse, error) {
+			calls = append(calls, name)
+			return resp, err
+		})
+	}
+
+	tt := []struct {
+		name  string
+		chain Hades
+		resp  *AntiFraudResponse
+		err   bool
+		calls []string
+	}{
+		{
+			name:  "no fraud",
+			chain: Chain(h("local", &AntiFraudResponse{}, nil), h("remote", &AntiFraudResponse{}, nil)),
+			resp:  &AntiFraudResponse{},
+			calls: []string{"local", "remote"},
+		},
+		{
+			name:  "local fraud",
+			chain: Chain(h("local", &AntiFraudResponse{IsFraud: true, Rule: "rule"}, nil), h("remote", &AntiFraudResponse{}, nil)),
+			resp:  &AntiFraudResponse{IsFraud: true, Rule: "rule"},
+			calls: []string{"local"},
+		},
+		{
+			name:  "remote fraud",
+			chain: Chain(h("local", &AntiFraudResponse{}, nil), h("remote", &AntiFraudResponse{IsFraud: true}, nil)),
+			resp:  &AntiFraudResponse{IsFraud: true},
+			calls: []string{"local", "remote"},
+		},
+		{
+			name:  "remote error",
+			chain: Chain(h("local", &AntiFraudResponse{}, nil), h("remote", nil, errors.New("test"))),
+			err:   true,
+			calls: []string{"local", "remote"},
+		},
+	}
+
+	for _, tc := range tt {
+		calls = nil
+
+		resp, err := tc.chain.AntiFraud(context.Background(), &AntiFraudRequest{})
+		if tc.err {
+			require.Error(t, err, tc.name)
+		} else {
+			require.NoError(t, err, tc.name)
+			require.Equal(t, tc.resp, resp, tc.name)
+		}
+		require.Equal(t, tc.calls, calls, tc.name)
+	}
+}
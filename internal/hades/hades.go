@@ -33,24 +33,31 @@ type AntiFraudRequest struct {
 // AntiFraudResponse ...
 type AntiFraudResponse struct {
 	IsFraud bool `json:"isFraud"`
+	// Rule is a name of the rule which detected fraud, it's filled by built-in rules engine.
+	Rule string `json:"rule,omitempty"`
 }
 
 // client encapsulates Hades HTTP client.
 type client struct {
 	baseURL string
+	timeout time.Duration
 	client  *http.Client
 }
 
-// New create a new Hades client.
-func New(baseURL string) Hades {
+// New create a new Hades client. Request timeout is derived from ctx deadline and doesn't exceed timeout.
+func New(baseURL string, timeout time.Duration) Hades {
 	return &client{
 		baseURL: baseURL,
-		client:  &http.Client{Timeout: 10 * time.Second},
+		timeout: timeout,
+		client:  &http.Client{},
 	}
 }
 
 // AntiFraud check the given PDV for fraud.
 func (c *client) AntiFraud(ctx context.Context, r *AntiFraudRequest) (*AntiFraudResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.budget(ctx))
+	defer cancel()
+
 	path, err := url.Parse(c.baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse base url: %w", err)
@@ -87,3 +94,17 @@ func (c *client) AntiFraud(ctx context.Context, r *AntiFraudRequest) (*AntiFraud
 
 	return &resp, nil
 }
+
+// budget returns timeout of the request. Half of the time left before ctx deadline is given to Hades,
+// so the caller has time to handle the result.
+func (c *client) budget(ctx context.Context) time.Duration {
+	timeout := c.timeout
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if left := time.Until(deadline) / 2; left < timeout {
+			timeout = left
+		}
+	}
+
+	return timeout
+}
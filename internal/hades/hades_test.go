@@ -0,0 +1,23 @@
+package hades
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_budget(t *testing.T) {
+	c := New("http://localhost", 10*time.Second).(*client)
+
+	require.Equal(t, 10*time.Second, c.budget(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	require.Equal(t, 10*time.Second, c.budget(ctx))
+
+	ctx, cancel = context.WithTimeout(context.Background(), 4*time.Second)
+	defer cancel()
+	require.InDelta(t, 2*time.Second, c.budget(ctx), float64(100*time.Millisecond))
+}
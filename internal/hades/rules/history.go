@@ -0,0 +1,68 @@
+package rules
+
+import (
+	"sync"
+	"time"
+)
+
+// how often expired items are removed from history
+const sweepInterval = time.Minute
+
+// history remembers batches' keys during window.
+type history struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	items   map[string]historyItem
+	sweptAt time.Time
+}
+
+type historyItem struct {
+	address string
+	id      uint64
+	at      time.Time
+}
+
+func newHistory(window time.Duration) *history {
+	return &history{
+		window: window,
+		items:  make(map[string]historyItem),
+	}
+}
+
+// get returns item saved with the key during window.
+func (h *history) get(key string, now time.Time) (historyItem, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	item, ok := h.items[key]
+	if !ok || h.expired(item, now) {
+		return historyItem{}, false
+	}
+
+	return item, true
+}
+
+// put saves item with the key.
+func (h *history) put(key string, item historyItem, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	item.at = now
+	h.items[key] = item
+
+	if now.Sub(h.sweptAt) < sweepInterval {
+		return
+	}
+
+	for k, v := range h.items {
+		if h.expired(v, now) {
+			delete(h.items, k)
+		}
+	}
+	h.sweptAt = now
+}
+
+func (h *history) expired(item historyItem, now time.Time) bool {
+	return now.Sub(item.at) > h.window
+}
@@ -0,0 +1,203 @@
+package rules
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/Decentr-net/cerberus/internal/hades"
+	"github.com/Decentr-net/cerberus/pkg/schema"
+)
+
+const (
+	duplicateCookiesName      = "duplicate_cookies"
+	repeatedSearchQueriesName = "repeated_search_queries"
+	timestampsName            = "timestamps"
+	identicalBatchesName      = "identical_batches"
+)
+
+// duplicateCookies detects batches where share of duplicate cookies exceeds maxShare.
+type duplicateCookies struct {
+	maxShare float64
+}
+
+func (duplicateCookies) Name() string {
+	return duplicateCookiesName
+}
+
+func (r duplicateCookies) Check(req *hades.AntiFraudRequest, _ time.Time) bool {
+	var (
+		total  int
+		unique = make(map[string]struct{})
+	)
+
+	for _, d := range req.Data.Data() {
+		c, ok := cookie(d)
+		if !ok {
+			continue
+		}
+
+		total++
+		unique[strings.Join([]string{c.Source.Host, c.Domain, c.Path, c.Name, c.Value}, "\x00")] = struct{}{}
+	}
+
+	return exceeds(total-len(unique), total, r.maxShare)
+}
+
+// repeatedSearchQueries detects batches where share of search queries already sent by the address
+// in other batches exceeds maxShare.
+type repeatedSearchQueries struct {
+	maxShare float64
+	history  *history
+}
+
+func (*repeatedSearchQueries) Name() string {
+	return repeatedSearchQueriesName
+}
+
+func (r *repeatedSearchQueries) Check(req *hades.AntiFraudRequest, now time.Time) bool {
+	var total, repeated int
+
+	for _, d := range req.Data.Data() {
+		s, ok := searchHistory(d)
+		if !ok {
+			continue
+		}
+
+		key := hash(req.Address, s.Engine, s.Query)
+
+		// the same batch could be checked again, e.g. on recheck
+		if item, ok := r.history.get(key, now); ok && item.id != req.ID {
+			repeated++
+		}
+
+		total++
+		r.history.put(key, historyItem{address: req.Address, id: req.ID}, now)
+	}
+
+	return exceeds(repeated, total, r.maxShare)
+}
+
+// timestamps detects batches with data created later than maxFuture from now or earlier than maxAge ago.
+type timestamps struct {
+	maxFuture time.Duration
+	maxAge    time.Duration
+}
+
+func (timestamps) Name() string {
+	return timestampsName
+}
+
+func (r timestamps) Check(req *hades.AntiFraudRequest, now time.Time) bool {
+	for _, d := range req.Data.Data() {
+		t, ok := timestamp(d)
+		if !ok {
+			continue
+		}
+
+		if t.After(now.Add(r.maxFuture)) || t.Before(now.Add(-r.maxAge)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// identicalBatches detects batches identical to batches sent by other addresses.
+type identicalBatches struct {
+	history *history
+}
+
+func (*identicalBatches) Name() string {
+	return identicalBatchesName
+}
+
+func (r *identicalBatches) Check(req *hades.AntiFraudRequest, now time.Time) bool {
+	b, err := json.Marshal(req.Data.Data())
+	if err != nil {
+		return false
+	}
+
+	key := hash(string(b))
+
+	if item, ok := r.history.get(key, now); ok {
+		return item.address != req.Address
+	}
+
+	// the first sender owns the batch
+	r.history.put(key, historyItem{address: req.Address, id: req.ID}, now)
+
+	return false
+}
+
+// cookie returns cookie data, data is a pointer when it's unmarshalled from json.
+func cookie(d schema.Data) (*schema.V1Cookie, bool) {
+	switch v := d.(type) {
+	case *schema.V1Cookie:
+		return v, true
+	case schema.V1Cookie:
+		return &v, true
+	default:
+		return nil, false
+	}
+}
+
+// searchHistory returns search history data, data is a pointer when it's unmarshalled from json.
+func searchHistory(d schema.Data) (*schema.V1SearchHistory, bool) {
+	switch v := d.(type) {
+	case *schema.V1SearchHistory:
+		return v, true
+	case schema.V1SearchHistory:
+		return &v, true
+	default:
+		return nil, false
+	}
+}
+
+// timestamp returns time of data creation if data has it.
+func timestamp(d schema.Data) (time.Time, bool) {
+	if c, ok := cookie(d); ok {
+		return c.Time, true
+	}
+
+	if s, ok := searchHistory(d); ok {
+		return s.Time, true
+	}
+
+	// installed apps don't have time of creation
+	switch v := d.(type) {
+	case *schema.V1Location:
+		return v.Time, true
+	case schema.V1Location:
+		return v.Time, true
+	case *schema.V2BrowsingHistory:
+		return v.Time, true
+	case schema.V2BrowsingHistory:
+		return v.Time, true
+	case *schema.V2Bookmark:
+		return v.Time, true
+	case schema.V2Bookmark:
+		return v.Time, true
+	case *schema.V2DeviceInfo:
+		return v.Time, true
+	case schema.V2DeviceInfo:
+		return v.Time, true
+	case *schema.V2Interest:
+		return v.Time, true
+	case schema.V2Interest:
+		return v.Time, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// exceeds returns true if share of n in total exceeds maxShare.
+func exceeds(n, total int, maxShare float64) bool {
+	return total > 0 && float64(n)/float64(total) > maxShare
+}
+
+func hash(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return string(sum[:])
+}
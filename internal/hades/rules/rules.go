@@ -0,0 +1,129 @@
+// Package rules is a built-in antifraud engine which checks PDV with rules configured in yaml.
+package rules
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/Decentr-net/cerberus/internal/hades"
+)
+
+var _ hades.Hades = &engine{}
+
+// Rule checks PDV batch for fraud.
+type Rule interface {
+	// Name returns name of the rule which is reported in AntiFraudResponse.
+	Name() string
+	// Check returns true if fraud is detected, now is the time of the check.
+	Check(req *hades.AntiFraudRequest, now time.Time) bool
+}
+
+// Config is a rules configuration, rules which aren't configured are disabled.
+type Config struct {
+	DuplicateCookies      *DuplicateCookiesConfig      `yaml:"duplicate_cookies"`
+	RepeatedSearchQueries *RepeatedSearchQueriesConfig `yaml:"repeated_search_queries"`
+	Timestamps            *TimestampsConfig            `yaml:"timestamps"`
+	IdenticalBatches      *IdenticalBatchesConfig      `yaml:"identical_batches"`
+}
+
+// DuplicateCookiesConfig is a configuration of the rule which detects batches where share of duplicate cookies exceeds MaxShare.
+type DuplicateCookiesConfig struct {
+	MaxShare float64 `yaml:"max_share"`
+}
+
+// RepeatedSearchQueriesConfig is a configuration of the rule which detects batches where share of search queries
+// already sent by the address in other batches during Window exceeds MaxShare.
+type RepeatedSearchQueriesConfig struct {
+	MaxShare float64       `yaml:"max_share"`
+	Window   time.Duration `yaml:"window"`
+}
+
+// TimestampsConfig is a configuration of the rule which detects batches with data created later than MaxFuture
+// from now or earlier than MaxAge ago.
+type TimestampsConfig struct {
+	MaxFuture time.Duration `yaml:"max_future"`
+	MaxAge    time.Duration `yaml:"max_age"`
+}
+
+// IdenticalBatchesConfig is a configuration of the rule which detects batches identical to batches sent by other addresses during Window.
+type IdenticalBatchesConfig struct {
+	Window time.Duration `yaml:"window"`
+}
+
+// engine checks PDV with every rule.
+type engine struct {
+	rules []Rule
+}
+
+// Load reads rules configuration from yaml file and creates the engine.
+func Load(path string) (hades.Hades, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules config: %w", err)
+	}
+
+	var c Config
+	if err := yaml.UnmarshalStrict(b, &c); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rules config: %w", err)
+	}
+
+	return New(c)
+}
+
+// New creates the engine with rules enabled in the config.
+// Rules which compare batches with previous ones keep history in memory, so every instance has its own history.
+func New(c Config) (hades.Hades, error) {
+	var rules []Rule
+
+	if v := c.DuplicateCookies; v != nil {
+		if v.MaxShare < 0 || v.MaxShare >= 1 {
+			return nil, fmt.Errorf("invalid %s max share %v", duplicateCookiesName, v.MaxShare)
+		}
+		rules = append(rules, duplicateCookies{maxShare: v.MaxShare})
+	}
+
+	if v := c.RepeatedSearchQueries; v != nil {
+		if v.MaxShare < 0 || v.MaxShare >= 1 || v.Window <= 0 {
+			return nil, fmt.Errorf("invalid %s config %+v", repeatedSearchQueriesName, *v)
+		}
+		rules = append(rules, &repeatedSearchQueries{maxShare: v.MaxShare, history: newHistory(v.Window)})
+	}
+
+	if v := c.Timestamps; v != nil {
+		if v.MaxFuture < 0 || v.MaxAge <= 0 {
+			return nil, fmt.Errorf("invalid %s config %+v", timestampsName, *v)
+		}
+		rules = append(rules, timestamps{maxFuture: v.MaxFuture, maxAge: v.MaxAge})
+	}
+
+	if v := c.IdenticalBatches; v != nil {
+		if v.Window <= 0 {
+			return nil, fmt.Errorf("invalid %s window %s", identicalBatchesName, v.Window)
+		}
+		rules = append(rules, &identicalBatches{history: newHistory(v.Window)})
+	}
+
+	return &engine{rules: rules}, nil
+}
+
+// AntiFraud check the given PDV for fraud. Every rule sees the batch, so rules with history remember it
+// even if fraud is detected by another rule. The first rule which detected fraud is reported.
+func (e *engine) AntiFraud(_ context.Context, req *hades.AntiFraudRequest) (*hades.AntiFraudResponse, error) {
+	var (
+		now  = time.Now()
+		resp = &hades.AntiFraudResponse{}
+	)
+
+	for _, r := range e.rules {
+		if r.Check(req, now) && !resp.IsFraud {
+			resp.IsFraud = true
+			resp.Rule = r.Name()
+		}
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,201 @@
+package rules
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Decentr-net/cerberus/internal/hades"
+	"github.com/Decentr-net/cerberus/pkg/schema"
+	"github.com/Decentr-net/cerberus/pkg/schema/types"
+	v1 "github.com/Decentr-net/cerberus/pkg/schema/v1"
+	v2 "github.com/Decentr-net/cerberus/pkg/schema/v2"
+)
+
+var now = time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+
+func cookieData(name string) schema.Data {
+	return &v1.Cookie{
+		Timestamp: types.Timestamp{Time: now},
+		Source:    types.Source{Host: "https://decentr.xyz"},
+		Name:      name,
+		Value:     "value",
+		Domain:    "decentr.xyz",
+	}
+}
+
+func searchData(query string) schema.Data {
+	return &v1.SearchHistory{
+		Timestamp: types.Timestamp{Time: now},
+		Engine:    "google",
+		Domain:    "google.com",
+		Query:     query,
+	}
+}
+
+func request(address string, id uint64, data ...schema.Data) *hades.AntiFraudRequest {
+	return &hades.AntiFraudRequest{
+		ID:      id,
+		Address: address,
+		Data:    schema.NewPDVWrapper("desktop", v1.PDV(data)),
+	}
+}
+
+func TestLoad(t *testing.T) {
+	h, err := Load("../../../configs/hades_rules.yml")
+	require.NoError(t, err)
+	require.Len(t, h.(*engine).rules, 4)
+}
+
+func TestNew(t *testing.T) {
+	tt := []struct {
+		name   string
+		config Config
+		rules  []string
+		valid  bool
+	}{
+		{
+			name:   "empty",
+			config: Config{},
+			valid:  true,
+		},
+		{
+			name: "all",
+			config: Config{
+				DuplicateCookies:      &DuplicateCookiesConfig{MaxShare: 0.5},
+				RepeatedSearchQueries: &RepeatedSearchQueriesConfig{MaxShare: 0.5, Window: time.Hour},
+				Timestamps:            &TimestampsConfig{MaxAge: time.Hour},
+				IdenticalBatches:      &IdenticalBatchesConfig{Window: time.Hour},
+			},
+			rules: []string{duplicateCookiesName, repeatedSearchQueriesName, timestampsName, identicalBatchesName},
+			valid: true,
+		},
+		{
+			name:   "invalid max share",
+			config: Config{DuplicateCookies: &DuplicateCookiesConfig{MaxShare: 1}},
+		},
+		{
+			name:   "invalid window",
+			config: Config{RepeatedSearchQueries: &RepeatedSearchQueriesConfig{MaxShare: 0.5}},
+		},
+		{
+			name:   "invalid max age",
+			config: Config{Timestamps: &TimestampsConfig{MaxFuture: time.Hour}},
+		},
+		{
+			name:   "invalid identical batches window",
+			config: Config{IdenticalBatches: &IdenticalBatchesConfig{Window: -time.Hour}},
+		},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.name, func(t *testing.T) {
+			h, err := New(tc.config)
+			if !tc.valid {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			var names []string
+			for _, v := range h.(*engine).rules {
+				names = append(names, v.Name())
+			}
+			require.Equal(t, tc.rules, names)
+		})
+	}
+}
+
+func TestDuplicateCookies_Check(t *testing.T) {
+	r := duplicateCookies{maxShare: 0.5}
+
+	require.False(t, r.Check(request("addr", 1), now))
+	require.False(t, r.Check(request("addr", 1, cookieData("1"), cookieData("1"), cookieData("2")), now))
+	require.True(t, r.Check(request("addr", 1, cookieData("1"), cookieData("1"), cookieData("1"), searchData("q")), now))
+}
+
+func TestRepeatedSearchQueries_Check(t *testing.T) {
+	r := &repeatedSearchQueries{maxShare: 0.5, history: newHistory(time.Hour)}
+
+	require.False(t, r.Check(request("addr", 1, searchData("1"), searchData("2")), now))
+	// recheck of the same pdv
+	require.False(t, r.Check(request("addr", 1, searchData("1"), searchData("2")), now))
+	// another address
+	require.False(t, r.Check(request("addr2", 2, searchData("1"), searchData("2")), now))
+	// half of queries is repeated
+	require.False(t, r.Check(request("addr", 3, searchData("1"), searchData("3")), now))
+	require.True(t, r.Check(request("addr", 4, searchData("1"), searchData("2"), searchData("3")), now))
+	// window passed
+	require.False(t, r.Check(request("addr", 5, searchData("1"), searchData("2")), now.Add(2*time.Hour)))
+}
+
+func TestTimestamps_Check(t *testing.T) {
+	r := timestamps{maxFuture: time.Minute, maxAge: time.Hour}
+
+	require.False(t, r.Check(request("addr", 1, cookieData("1"), searchData("q")), now))
+	require.False(t, r.Check(request("addr", 1, cookieData("1")), now.Add(-time.Minute)))
+	require.True(t, r.Check(request("addr", 1, cookieData("1")), now.Add(-2*time.Minute)))
+	require.True(t, r.Check(request("addr", 1, searchData("q")), now.Add(2*time.Hour)))
+	require.False(t, r.Check(request("addr", 1, &v1.AdvertiserID{Advertiser: "google", Name: "name", Value: "id"}), now.Add(2*time.Hour)))
+}
+
+func TestTimestamp(t *testing.T) {
+	ts := types.Timestamp{Time: now}
+
+	for _, d := range []schema.Data{
+		cookieData("1"),
+		searchData("q"),
+		&v1.Location{Timestamp: ts},
+		&v2.BrowsingHistory{Timestamp: ts},
+		v2.BrowsingHistory{Timestamp: ts},
+		&v2.Bookmark{Timestamp: ts},
+		&v2.DeviceInfo{Timestamp: ts},
+		&v2.Interest{Timestamp: ts},
+	} {
+		v, ok := timestamp(d)
+		require.True(t, ok, d.Type())
+		require.Equal(t, now, v, d.Type())
+	}
+
+	// installed apps don't have timestamp
+	_, ok := timestamp(&v2.InstalledApp{})
+	require.False(t, ok)
+}
+
+func TestIdenticalBatches_Check(t *testing.T) {
+	r := &identicalBatches{history: newHistory(time.Hour)}
+
+	require.False(t, r.Check(request("addr", 1, cookieData("1")), now))
+	require.False(t, r.Check(request("addr", 2, cookieData("1")), now))
+	require.False(t, r.Check(request("addr2", 3, cookieData("2")), now))
+	require.True(t, r.Check(request("addr2", 4, cookieData("1")), now))
+	// window passed
+	require.False(t, r.Check(request("addr2", 5, cookieData("1")), now.Add(2*time.Hour)))
+}
+
+func TestEngine_AntiFraud(t *testing.T) {
+	h, err := New(Config{
+		DuplicateCookies: &DuplicateCookiesConfig{MaxShare: 0},
+		IdenticalBatches: &IdenticalBatchesConfig{Window: time.Hour},
+	})
+	require.NoError(t, err)
+
+	c := cookieData("1")
+	c.(*v1.Cookie).Time = time.Now()
+
+	resp, err := h.AntiFraud(context.Background(), request("addr", 1, c))
+	require.NoError(t, err)
+	require.Equal(t, &hades.AntiFraudResponse{}, resp)
+
+	resp, err = h.AntiFraud(context.Background(), request("addr2", 2, c, c))
+	require.NoError(t, err)
+	require.Equal(t, &hades.AntiFraudResponse{IsFraud: true, Rule: duplicateCookiesName}, resp)
+
+	resp, err = h.AntiFraud(context.Background(), request("addr2", 3, c))
+	require.NoError(t, err)
+	require.Equal(t, &hades.AntiFraudResponse{IsFraud: true, Rule: identicalBatchesName}, resp)
+}
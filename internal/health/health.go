@@ -30,6 +30,8 @@ func GetVersion() string {
 type VersionResponse struct {
 	Version string `json:"version"`
 	Commit  string `json:"commit"`
+	// State contains states reported by pingers which implement Reporter interface.
+	State map[string]interface{} `json:"state,omitempty"`
 }
 
 // Pinger pings external service.
@@ -37,6 +39,13 @@ type Pinger interface {
 	Ping(ctx context.Context) error
 }
 
+// Reporter is a Pinger which reports its state in the health response.
+type Reporter interface {
+	Pinger
+	// Report returns name and state of the component.
+	Report() (string, interface{})
+}
+
 // PingFunc is wrapper for raw func.
 type PingFunc func(ctx context.Context) error
 
@@ -62,20 +71,32 @@ func SetupRouter(r chi.Router, p ...Pinger) {
 			})
 		}
 
+		resp := VersionResponse{Version: version, Commit: commit}
+		for _, v := range p {
+			if rep, ok := v.(Reporter); ok {
+				if resp.State == nil {
+					resp.State = map[string]interface{}{}
+				}
+
+				name, state := rep.Report()
+				resp.State[name] = state
+			}
+		}
+
 		if err := gr.Wait(); err != nil {
 			data, _ := json.Marshal(struct {
 				api.Error
 				VersionResponse
 			}{
 				Error:           api.Error{Error: err.Error()},
-				VersionResponse: VersionResponse{Version: version, Commit: commit},
+				VersionResponse: resp,
 			})
 			w.WriteHeader(http.StatusInternalServerError)
 			w.Write(data) // nolint
 
 			return
 		}
-		data, _ := json.Marshal(VersionResponse{Version: version, Commit: commit})
+		data, _ := json.Marshal(resp)
 
 		w.WriteHeader(http.StatusOK)
 		w.Write(data) // nolint
@@ -0,0 +1,178 @@
+// Package keyrotation contains jobs which re-encrypt stored PDV and rewrap data keys encrypted with outdated keys.
+package keyrotation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Decentr-net/cerberus/internal/crypto"
+	"github.com/Decentr-net/cerberus/internal/leader"
+	"github.com/Decentr-net/cerberus/internal/storage"
+)
+
+const batchSize = 100
+
+var log = logrus.WithField("package", "keyrotation")
+
+// Rotator rewrites PDV encrypted with outdated keys using owner's data key.
+type Rotator struct {
+	c       crypto.OwnerCrypto
+	fs      storage.FileStorage
+	is      storage.IndexStorage
+	elector leader.Elector
+}
+
+// NewRotator creates a new instance of Rotator. PDV are re-encrypted only while the instance is the leader.
+func NewRotator(c crypto.OwnerCrypto, fs storage.FileStorage, is storage.IndexStorage, elector leader.Elector) *Rotator {
+	return &Rotator{
+		c:       c,
+		fs:      fs,
+		is:      is,
+		elector: elector,
+	}
+}
+
+// Run walks through all PDV and re-encrypts ones which are encrypted with outdated key.
+// The walk is stopped when the instance loses leadership. It returns count of re-encrypted PDV.
+func (r *Rotator) Run(ctx context.Context) (int, error) {
+	var (
+		count int
+		after *storage.PDVIndex
+	)
+
+	for {
+		if !r.elector.IsLeader() {
+			log.Info("leadership is lost, stop pdv re-encryption")
+			return count, nil
+		}
+
+		list, err := r.is.ListAllPDV(ctx, after, batchSize)
+		if err != nil {
+			return count, fmt.Errorf("failed to list pdv: %w", err)
+		}
+
+		for _, v := range list {
+			if err := ctx.Err(); err != nil {
+				return count, err
+			}
+
+			ok, err := r.rotate(ctx, v.Owner, v.ID)
+			if err != nil {
+				log.WithError(err).WithField("owner", v.Owner).WithField("id", v.ID).Error("failed to re-encrypt pdv")
+				continue
+			}
+			if ok {
+				count++
+			}
+		}
+
+		if len(list) < batchSize {
+			return count, nil
+		}
+		after = list[len(list)-1]
+	}
+}
+
+// RunAsync runs rotation in the background every interval until ctx is done.
+func (r *Rotator) RunAsync(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			// followers skip the run, otherwise every replica rewrites the same objects
+			if r.elector.IsLeader() {
+				log.Info("start pdv re-encryption")
+				count, err := r.Run(ctx)
+				if err != nil {
+					log.WithError(err).Error("failed to re-encrypt pdv")
+				}
+				log.Infof("%d pdv re-encrypted", count)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// rotate re-encrypts pdv with owner's data key. It returns false if pdv is already encrypted with the key.
+func (r *Rotator) rotate(ctx context.Context, owner string, id uint64) (bool, error) {
+	path := getPDVFilePath(owner, id)
+
+	c, err := r.c.ForOwner(ctx, owner)
+	if err != nil {
+		return false, fmt.Errorf("failed to get owner's crypto: %w", err)
+	}
+
+	data, err := r.read(ctx, path)
+	if err != nil {
+		return false, err
+	}
+
+	keyID, err := c.KeyID(bytes.NewReader(data))
+	if err != nil {
+		return false, fmt.Errorf("failed to get key id: %w", err)
+	}
+
+	if keyID == c.ActiveKeyID() {
+		return false, nil
+	}
+
+	dr, err := c.Decrypt(bytes.NewReader(data))
+	if err != nil {
+		return false, fmt.Errorf("failed to create decrypting reader: %w", err)
+	}
+
+	plain, err := ioutil.ReadAll(dr)
+	if err != nil {
+		return false, fmt.Errorf("failed to decrypt: %w", err)
+	}
+
+	// pdv could be removed by account reset while we were reading it, we should not restore it
+	if _, err := r.is.GetPDVMeta(ctx, owner, id); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get pdv meta: %w", err)
+	}
+
+	er, size := c.EncryptReader(bytes.NewReader(plain))
+	if _, err := r.fs.Write(ctx, er, size, path, "binary/octet-stream", false); err != nil {
+		return false, fmt.Errorf("failed to write data: %w", err)
+	}
+
+	log.WithField("owner", owner).WithField("id", id).Debugf("re-encrypted from %s key", keyID)
+
+	return true, nil
+}
+
+func (r *Rotator) read(ctx context.Context, path string) ([]byte, error) {
+	f, err := r.fs.Read(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data from storage: %w", err)
+	}
+	defer f.Close() // nolint
+
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data: %w", err)
+	}
+
+	return data, nil
+}
+
+func getPDVFilePath(owner string, id uint64) string {
+	// path should match the one used by processor
+	return fmt.Sprintf("%s/pdv/%016x", owner, math.MaxUint64-id)
+}
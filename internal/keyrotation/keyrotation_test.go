@@ -0,0 +1,104 @@
+package keyrotation
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	cryptomock "github.com/Decentr-net/cerberus/internal/crypto/mock"
+	"github.com/Decentr-net/cerberus/internal/crypto/sio"
+	"github.com/Decentr-net/cerberus/internal/entities"
+	leadermock "github.com/Decentr-net/cerberus/internal/leader/mock"
+	"github.com/Decentr-net/cerberus/internal/storage"
+	"github.com/Decentr-net/cerberus/internal/storage/mock"
+)
+
+var (
+	oldKey = [32]byte{1}
+	newKey = [32]byte{2}
+)
+
+func TestRotator_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	data := []byte("pdv")
+
+	kp := cryptomock.NewMockKeyProvider(ctrl)
+	kp.EXPECT().UnwrapKey(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, key []byte) ([]byte, error) {
+		return key, nil
+	}).AnyTimes()
+
+	legacy, err := sio.NewCrypto(ctx, kp, map[string][]byte{sio.LegacyKeyID: oldKey[:]}, sio.LegacyKeyID)
+	require.NoError(t, err)
+	c, err := sio.WithKey(legacy, "2", newKey)
+	require.NoError(t, err)
+
+	outdated, err := legacy.Encrypt(data)
+	require.NoError(t, err)
+	actual, err := c.Encrypt(data)
+	require.NoError(t, err)
+
+	fs := mock.NewMockFileStorage(ctrl)
+	is := mock.NewMockIndexStorage(ctrl)
+	oc := cryptomock.NewMockOwnerCrypto(ctrl)
+	oc.EXPECT().ForOwner(ctx, gomock.Any()).Return(c, nil).Times(3)
+
+	is.EXPECT().ListAllPDV(ctx, nil, uint16(batchSize)).Return([]*storage.PDVIndex{
+		{Owner: "owner", ID: 1},
+		{Owner: "owner", ID: 2},
+		{Owner: "removed", ID: 1},
+	}, nil)
+
+	fs.EXPECT().Read(ctx, "owner/pdv/fffffffffffffffe").Return(ioutil.NopCloser(bytes.NewReader(outdated)), nil)
+	is.EXPECT().GetPDVMeta(ctx, "owner", uint64(1)).Return(&entities.PDVMeta{}, nil)
+	fs.EXPECT().Write(ctx, gomock.Any(), gomock.Any(), "owner/pdv/fffffffffffffffe", "binary/octet-stream", false).
+		DoAndReturn(func(_ context.Context, r io.Reader, size int64, _, _ string, _ bool) (string, error) {
+			b, err := ioutil.ReadAll(r)
+			require.NoError(t, err)
+			require.EqualValues(t, len(b), size)
+
+			id, err := c.KeyID(bytes.NewReader(b))
+			require.NoError(t, err)
+			require.Equal(t, "2", id)
+
+			dr, err := c.Decrypt(bytes.NewReader(b))
+			require.NoError(t, err)
+			act, err := ioutil.ReadAll(dr)
+			require.NoError(t, err)
+			require.Equal(t, data, act)
+
+			return "", nil
+		})
+
+	fs.EXPECT().Read(ctx, "owner/pdv/fffffffffffffffd").Return(ioutil.NopCloser(bytes.NewReader(actual)), nil)
+
+	fs.EXPECT().Read(ctx, "removed/pdv/fffffffffffffffe").Return(ioutil.NopCloser(bytes.NewReader(outdated)), nil)
+	is.EXPECT().GetPDVMeta(ctx, "removed", uint64(1)).Return(nil, storage.ErrNotFound)
+
+	elector := leadermock.NewMockElector(ctrl)
+	elector.EXPECT().IsLeader().Return(true)
+
+	count, err := NewRotator(oc, fs, is, elector).Run(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
+func TestRotator_Run_Follower(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	elector := leadermock.NewMockElector(ctrl)
+	elector.EXPECT().IsLeader().Return(false)
+
+	// follower doesn't touch the storage
+	count, err := NewRotator(nil, nil, nil, elector).Run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+}
@@ -0,0 +1,129 @@
+package keyrotation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Decentr-net/cerberus/internal/crypto"
+	"github.com/Decentr-net/cerberus/internal/leader"
+	"github.com/Decentr-net/cerberus/internal/storage"
+)
+
+// Rewrapper rewraps owners' data keys wrapped with outdated master key using the active one,
+// so the outdated master key could be removed from key provider.
+type Rewrapper struct {
+	kp      crypto.KeyProvider
+	is      storage.IndexStorage
+	elector leader.Elector
+}
+
+// NewRewrapper creates a new instance of Rewrapper. Data keys are rewrapped only while the instance is the leader.
+func NewRewrapper(kp crypto.KeyProvider, is storage.IndexStorage, elector leader.Elector) *Rewrapper {
+	return &Rewrapper{
+		kp:      kp,
+		is:      is,
+		elector: elector,
+	}
+}
+
+// Run walks through all data keys and rewraps ones which are wrapped with outdated master key.
+// The walk is stopped when the instance loses leadership. It returns count of rewrapped keys.
+func (r *Rewrapper) Run(ctx context.Context) (int, error) {
+	var (
+		count int
+		after string
+	)
+
+	for {
+		if !r.elector.IsLeader() {
+			log.Info("leadership is lost, stop data keys rewrapping")
+			return count, nil
+		}
+
+		list, err := r.is.ListDataKeys(ctx, after, batchSize)
+		if err != nil {
+			return count, fmt.Errorf("failed to list data keys: %w", err)
+		}
+
+		for _, v := range list {
+			if err := ctx.Err(); err != nil {
+				return count, err
+			}
+
+			ok, err := r.rewrap(ctx, v)
+			if err != nil {
+				log.WithError(err).WithField("owner", v.Owner).Error("failed to rewrap data key")
+				continue
+			}
+			if ok {
+				count++
+			}
+		}
+
+		if len(list) < batchSize {
+			return count, nil
+		}
+		after = list[len(list)-1].Owner
+	}
+}
+
+// RunAsync runs rewrapping in the background every interval until ctx is done.
+func (r *Rewrapper) RunAsync(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if r.elector.IsLeader() {
+				log.Info("start data keys rewrapping")
+				count, err := r.Run(ctx)
+				if err != nil {
+					log.WithError(err).Error("failed to rewrap data keys")
+				}
+				log.Infof("%d data keys rewrapped", count)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// rewrap wraps data key with the active master key. It returns false if the key is already wrapped with it.
+func (r *Rewrapper) rewrap(ctx context.Context, dk *storage.DataKey) (bool, error) {
+	keyID, err := r.kp.KeyID(dk.Key)
+	if err != nil {
+		return false, fmt.Errorf("failed to get key id: %w", err)
+	}
+
+	if keyID == r.kp.ActiveKeyID() {
+		return false, nil
+	}
+
+	key, err := r.kp.UnwrapKey(ctx, dk.Key)
+	if err != nil {
+		return false, fmt.Errorf("failed to unwrap: %w", err)
+	}
+
+	wrapped, err := r.kp.WrapKey(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to wrap: %w", err)
+	}
+
+	// the key could be deleted by account reset while we were rewrapping it, we should not restore it
+	if err := r.is.UpdateDataKey(ctx, dk.Owner, dk.Key, wrapped); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to update data key: %w", err)
+	}
+
+	log.WithField("owner", dk.Owner).Debugf("data key rewrapped from %q key", keyID)
+
+	return true, nil
+}
@@ -0,0 +1,61 @@
+package keyrotation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	cryptomock "github.com/Decentr-net/cerberus/internal/crypto/mock"
+	leadermock "github.com/Decentr-net/cerberus/internal/leader/mock"
+	"github.com/Decentr-net/cerberus/internal/storage"
+	"github.com/Decentr-net/cerberus/internal/storage/mock"
+)
+
+func TestRewrapper_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	kp := cryptomock.NewMockKeyProvider(ctrl)
+	kp.EXPECT().ActiveKeyID().Return("2").AnyTimes()
+	kp.EXPECT().KeyID([]byte("1:owner")).Return("1", nil)
+	kp.EXPECT().KeyID([]byte("2:owner")).Return("2", nil)
+	kp.EXPECT().KeyID([]byte("1:removed")).Return("1", nil)
+	kp.EXPECT().UnwrapKey(ctx, []byte("1:owner")).Return([]byte("owner"), nil)
+	kp.EXPECT().UnwrapKey(ctx, []byte("1:removed")).Return([]byte("removed"), nil)
+	kp.EXPECT().WrapKey(ctx, []byte("owner")).Return([]byte("2:owner"), nil)
+	kp.EXPECT().WrapKey(ctx, []byte("removed")).Return([]byte("2:removed"), nil)
+
+	is := mock.NewMockIndexStorage(ctrl)
+	is.EXPECT().ListDataKeys(ctx, "", uint16(batchSize)).Return([]*storage.DataKey{
+		{Owner: "owner", Key: []byte("1:owner")},
+		{Owner: "owner2", Key: []byte("2:owner")},
+		{Owner: "removed", Key: []byte("1:removed")},
+	}, nil)
+	is.EXPECT().UpdateDataKey(ctx, "owner", []byte("1:owner"), []byte("2:owner")).Return(nil)
+	// key is deleted by account reset
+	is.EXPECT().UpdateDataKey(ctx, "removed", []byte("1:removed"), []byte("2:removed")).Return(storage.ErrNotFound)
+
+	elector := leadermock.NewMockElector(ctrl)
+	elector.EXPECT().IsLeader().Return(true)
+
+	count, err := NewRewrapper(kp, is, elector).Run(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
+func TestRewrapper_Run_Follower(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	elector := leadermock.NewMockElector(ctrl)
+	elector.EXPECT().IsLeader().Return(false)
+
+	// follower doesn't touch the storage
+	count, err := NewRewrapper(nil, nil, elector).Run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+}
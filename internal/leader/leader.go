@@ -0,0 +1,28 @@
+// Package leader contains the interface of leader election between service instances.
+package leader
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate mockgen -destination=./mock/leader.go -package=mock -source=leader.go
+
+// Elector elects one instance which is allowed to run exclusive tasks.
+type Elector interface {
+	// RunAsync tries to become the leader every interval until ctx is done, the first attempt is made synchronously.
+	// Leadership is released when ctx is done.
+	RunAsync(ctx context.Context, interval time.Duration)
+	// IsLeader returns true if the instance is the leader.
+	IsLeader() bool
+	// Ping implements health.Pinger interface.
+	Ping(ctx context.Context) error
+	// Report implements health.Reporter interface.
+	Report() (string, interface{})
+}
+
+// State is a leadership state reported on /health.
+type State struct {
+	Lock     string `json:"lock"`
+	IsLeader bool   `json:"is_leader"`
+}
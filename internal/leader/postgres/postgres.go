@@ -0,0 +1,158 @@
+// Package postgres is an implementation of leader election based on postgres advisory locks.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Decentr-net/cerberus/internal/leader"
+)
+
+var log = logrus.WithField("package", "leader")
+
+var _ leader.Elector = &elector{}
+
+// elector holds session level advisory lock on a dedicated connection, the lock is released by postgres
+// when the connection is lost.
+type elector struct {
+	db   *sql.DB
+	name string
+	key  int64
+
+	mu       sync.RWMutex
+	conn     *sql.Conn
+	isLeader bool
+}
+
+// New creates a new instance of elector which uses advisory lock with key derived from the name.
+func New(db *sql.DB, name string) *elector { // nolint:golint
+	h := fnv.New64a()
+	h.Write([]byte(name)) // nolint
+
+	return &elector{
+		db:   db,
+		name: name,
+		key:  int64(h.Sum64()),
+	}
+}
+
+// RunAsync tries to become the leader every interval until ctx is done, the first attempt is made synchronously.
+func (e *elector) RunAsync(ctx context.Context, interval time.Duration) {
+	e.elect(ctx)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				e.release()
+				return
+			case <-ticker.C:
+				e.elect(ctx)
+			}
+		}
+	}()
+}
+
+// IsLeader returns true if the instance holds the lock.
+func (e *elector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.isLeader
+}
+
+// Ping implements health.Pinger interface. Follower is healthy too, so it always returns nil.
+func (e *elector) Ping(context.Context) error {
+	return nil
+}
+
+// Report implements health.Reporter interface.
+func (e *elector) Report() (string, interface{}) {
+	return "leader", leader.State{
+		Lock:     e.name,
+		IsLeader: e.IsLeader(),
+	}
+}
+
+func (e *elector) elect(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.isLeader {
+		// the lock is lost with the connection
+		if err := e.conn.PingContext(ctx); err != nil {
+			log.WithError(err).Error("leadership is lost")
+			e.closeConn(true)
+		}
+		return
+	}
+
+	conn, err := e.db.Conn(ctx)
+	if err != nil {
+		log.WithError(err).Error("failed to get connection")
+		return
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, e.key).Scan(&acquired); err != nil {
+		log.WithError(err).Error("failed to acquire lock")
+		conn.Close() // nolint
+		return
+	}
+
+	if !acquired {
+		conn.Close() // nolint
+		return
+	}
+
+	log.WithField("lock", e.name).Info("leadership is acquired")
+
+	e.conn = conn
+	e.isLeader = true
+}
+
+func (e *elector) release() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.isLeader {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := e.conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, e.key)
+	if err != nil {
+		log.WithError(err).Error("failed to release lock")
+	} else {
+		log.WithField("lock", e.name).Info("leadership is released")
+	}
+
+	e.closeConn(err != nil)
+}
+
+// closeConn closes the leader's connection. Broken connection is discarded, so the lock can't be kept in the pool.
+func (e *elector) closeConn(discard bool) {
+	if discard {
+		e.conn.Raw(func(interface{}) error { // nolint
+			return driver.ErrBadConn
+		})
+	}
+
+	if err := e.conn.Close(); err != nil {
+		log.WithError(err).Debug("failed to close connection")
+	}
+
+	e.conn = nil
+	e.isLeader = false
+}
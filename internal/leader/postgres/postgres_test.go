@@ -0,0 +1,108 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/Decentr-net/cerberus/internal/leader"
+)
+
+var (
+	db  *sql.DB
+	ctx = context.Background()
+)
+
+func TestMain(m *testing.M) {
+	shutdown := setup()
+
+	code := m.Run()
+	shutdown()
+	os.Exit(code)
+}
+
+func setup() func() {
+	req := testcontainers.ContainerRequest{
+		Image:        "postgres:12",
+		Env:          map[string]string{"POSTGRES_PASSWORD": "root"},
+		ExposedPorts: []string{"5432/tcp"},
+		WaitingFor:   wait.ForListeningPort("5432/tcp"),
+	}
+	c, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+	})
+	if err != nil {
+		logrus.WithError(err).Fatalf("failed to create container")
+	}
+
+	if err := c.Start(ctx); err != nil {
+		logrus.WithError(err).Fatal("failed to start container")
+	}
+
+	host, err := c.Host(ctx)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to get host")
+	}
+
+	port, err := c.MappedPort(ctx, "5432")
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to map port")
+	}
+
+	dsn := fmt.Sprintf("host=%s port=%d user=postgres password=root sslmode=disable", host, port.Int())
+
+	db, err = sql.Open("postgres", dsn)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to open connection")
+	}
+
+	if err := db.Ping(); err != nil {
+		logrus.WithError(err).Fatal("failed to ping postgres")
+	}
+
+	return func() {
+		if c != nil {
+			c.Terminate(ctx)
+		}
+	}
+}
+
+func TestElector(t *testing.T) {
+	ctx1, cancel1 := context.WithCancel(ctx)
+	ctx2, cancel2 := context.WithCancel(ctx)
+	defer cancel2()
+
+	e1, e2 := New(db, "test"), New(db, "test")
+
+	e1.RunAsync(ctx1, 100*time.Millisecond)
+	e2.RunAsync(ctx2, 100*time.Millisecond)
+
+	require.True(t, e1.IsLeader())
+	require.False(t, e2.IsLeader())
+
+	name, state := e1.Report()
+	require.Equal(t, "leader", name)
+	require.Equal(t, leader.State{Lock: "test", IsLeader: true}, state)
+
+	// another lock is independent
+	other := New(db, "other")
+	other.RunAsync(ctx2, time.Second)
+	require.True(t, other.IsLeader())
+
+	cancel1()
+
+	require.Eventually(t, e2.IsLeader, 5*time.Second, 100*time.Millisecond)
+	require.False(t, e1.IsLeader())
+}
@@ -3,6 +3,7 @@ package pdvrewards
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -10,16 +11,26 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/Decentr-net/cerberus/internal/blockchain"
+	"github.com/Decentr-net/cerberus/internal/leader"
 	"github.com/Decentr-net/cerberus/internal/storage"
 )
 
 const (
 	chunkSize   = 25
 	rewardsMemo = "PDV rewards"
+	// how long distributor waits for stakes tx to be included into the blockchain
+	txWaitTimeout = time.Minute
+	// how long sent stakes tx could be not found before rewards are sent again
+	txExpiry = time.Hour
 )
 
 var log = logrus.WithField("package", "pdvrewards")
 
+var errTxFailed = errors.New("tx is failed")
+
+// ErrRewardNotUnknown is returned when resolved reward isn't sent or its tx hash is already known.
+var ErrRewardNotUnknown = errors.New("reward broadcast result isn't unknown")
+
 type reward struct {
 	address string
 	reward  int64
@@ -28,18 +39,29 @@ type reward struct {
 // Distributor responsible for distributing PDV rewards in uDEC.
 type Distributor struct {
 	rewardsPoolSize int64
+	policy          RewardPolicy
+	manualApprove   bool
 
-	b  blockchain.Blockchain
-	is storage.IndexStorage
+	elector leader.Elector
+	b       blockchain.Blockchain
+	is      storage.IndexStorage
 }
 
 // NewDistributor creates a new instance of Distributor.
+// When manualApprove is true the rewards queue is filled only by approved plans.
+// Rewards are prepared and distributed only while the instance is elected as the leader.
 func NewDistributor(
 	rewardsPoolSize int64,
+	policy RewardPolicy,
+	manualApprove bool,
+	elector leader.Elector,
 	b blockchain.Blockchain,
 	is storage.IndexStorage) *Distributor {
 	return &Distributor{
 		rewardsPoolSize: rewardsPoolSize,
+		policy:          policy,
+		manualApprove:   manualApprove,
+		elector:         elector,
 		b:               b,
 		is:              is,
 	}
@@ -49,53 +71,20 @@ func NewDistributor(
 func (d *Distributor) prepareRewardsQueue() {
 	ctx := context.Background()
 
-	deltas, err := d.is.GetPDVDeltaList(ctx)
+	p, err := MakePlan(ctx, d.is, d.policy, d.rewardsPoolSize)
 	if err != nil {
-		log.WithError(err).Error("failed to get PDV delta list")
+		log.WithError(err).Error("failed to make rewards plan")
 		return
 	}
 
-	var total float64
-	for _, delta := range deltas {
-		total += delta.Delta
-	}
-
-	if total == 0 {
-		log.Info("total PDV delta is zero")
-
-		if err := d.is.SetPDVRewardsDistributedDate(ctx, time.Now().UTC()); err != nil {
-			log.WithError(err).Error("failed to set PDV rewards distributed date")
-		}
-
-		return
-	}
-
-	var rewards []reward
-	for _, delta := range deltas {
-		r := int64(delta.Delta * float64(d.rewardsPoolSize) / total)
-
-		if r != 0 {
-			rewards = append(rewards, reward{
-				address: delta.Address,
-				reward:  r,
-			})
-		}
-	}
-
-	if err := d.is.InTx(ctx, func(tx storage.IndexStorage) error {
-		for _, r := range rewards {
-			if err1 := tx.CreateRewardsQueueItem(ctx, r.address, r.reward); err1 != nil {
-				return fmt.Errorf("failed to create rewards quque item: %w", err1)
-			}
-		}
-
-		return tx.SetPDVRewardsDistributedDate(ctx, time.Now().UTC())
-	}); err != nil {
+	if err := ApprovePlan(ctx, d.is, p); err != nil {
 		log.WithError(err).Error("failed to prepare rewards")
 	}
 }
 
 // distributeRewardsIfExist distributes rewards if any item exists in queue.
+// Rewards which were sent by the previous runs are settled by the saved tx hash and aren't sent again
+// until the tx is failed or expired.
 func (d *Distributor) distributeRewardsIfExist() {
 	ctx := context.Background()
 
@@ -110,30 +99,173 @@ func (d *Distributor) distributeRewardsIfExist() {
 		return
 	}
 
-	log.Infof("%d rewards to distribute", len(items))
+	var pending, unsent []*storage.RewardsQueueItem
+	for _, item := range items {
+		if item.SentAt != nil {
+			pending = append(pending, item)
+		} else {
+			unsent = append(unsent, item)
+		}
+	}
+
+	if err := d.settlePending(ctx, pending); err != nil {
+		log.WithError(err).Error("failed to settle sent rewards")
+		return
+	}
+
+	if len(unsent) == 0 {
+		return
+	}
+
+	log.Infof("%d rewards to distribute", len(unsent))
 
-	chunks := chunkSlice(items, chunkSize)
+	chunks := chunkSlice(unsent, chunkSize)
 	for _, chunk := range chunks {
-		if err := d.sendStakes(chunk); err != nil {
-			log.WithError(err).Error("failed to send stakes")
+		// leadership could be lost while previous chunk was being distributed
+		if !d.elector.IsLeader() {
+			log.Warn("leadership is lost, stop rewards distribution")
 			return
 		}
-		if err := d.deleteItemsFromQueue(ctx, chunk); err != nil {
-			log.WithError(err).Error("failed to delete item form queue`")
+
+		addrs := addresses(chunk)
+
+		// items are marked before broadcast, so they aren't sent again if broadcast result is unknown
+		if err := d.is.SetRewardsQueueItemsSent(ctx, addrs, time.Now().UTC()); err != nil {
+			log.WithError(err).Error("failed to mark rewards as sent")
+			return
+		}
+
+		tx, err := d.sendStakes(chunk)
+		if err != nil {
+			if blockchain.IsNotBroadcast(err) {
+				log.WithError(err).WithField("addresses", addrs).Error("failed to send stakes, tx isn't broadcast")
+
+				if err := d.is.ResetRewardsQueueItems(ctx, addrs); err != nil {
+					log.WithError(err).WithField("addresses", addrs).Error("failed to reset rewards")
+				}
+				return
+			}
+
+			log.WithError(err).WithField("addresses", addrs).
+				Error("failed to send stakes, broadcast result is unknown, it should be resolved with rewards command")
+			return
+		}
+
+		if err := d.is.SetRewardsQueueItemsTx(ctx, addrs, tx); err != nil {
+			log.WithError(err).WithFields(logrus.Fields{"tx": tx, "addresses": addrs}).
+				Error("failed to save stakes tx, it should be checked manually")
+			return
+		}
+
+		// items are left in the queue until tx is included, the next run settles them by the saved hash
+		status, err := d.waitForTx(ctx, tx)
+		if err != nil {
+			log.WithError(err).WithField("tx", tx).Error("failed to confirm stakes tx")
 			return
 		}
 
-		time.Sleep(6 * time.Second)
+		if err := d.settle(ctx, chunk, tx, status); err != nil {
+			log.WithError(err).WithField("tx", tx).Error("failed to settle stakes tx")
+			return
+		}
+
+		if status != blockchain.TxSucceeded {
+			log.WithField("tx", tx).Error(errTxFailed)
+			return
+		}
+	}
+
+	log.Infof("%d rewards distributed", len(unsent))
+}
+
+// settlePending settles rewards sent by the previous runs.
+func (d *Distributor) settlePending(ctx context.Context, items []*storage.RewardsQueueItem) error {
+	txs := make(map[string][]*storage.RewardsQueueItem)
+	for _, item := range items {
+		if item.TxHash == "" {
+			log.WithField("address", item.Address).
+				Error("reward broadcast result is unknown, it should be resolved with rewards command")
+			continue
+		}
+
+		txs[item.TxHash] = append(txs[item.TxHash], item)
+	}
+
+	for tx, chunk := range txs {
+		if !d.elector.IsLeader() {
+			log.Warn("leadership is lost, stop rewards settlement")
+			return nil
+		}
+
+		status, err := d.b.GetTxStatus(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("failed to get tx %s status: %w", tx, err)
+		}
+
+		if status == blockchain.TxNotFound && time.Since(*chunk[0].SentAt) < txExpiry {
+			log.WithField("tx", tx).Info("stakes tx isn't included yet")
+			continue
+		}
+
+		if err := d.settle(ctx, chunk, tx, status); err != nil {
+			return fmt.Errorf("failed to settle tx %s: %w", tx, err)
+		}
+	}
+
+	return nil
+}
+
+// settle moves items to history if tx is succeeded, otherwise resets them, so they are sent again by the next run.
+func (d *Distributor) settle(ctx context.Context, items []*storage.RewardsQueueItem, tx string, status blockchain.TxStatus) error {
+	switch status {
+	case blockchain.TxSucceeded:
+		if err := d.moveItemsToHistory(ctx, items, tx); err != nil {
+			return fmt.Errorf("failed to move items to history: %w", err)
+		}
 
-		for _, item := range chunk {
+		for _, item := range items {
 			log.Infof("%s got %d uDEC", item.Address, item.Reward)
 		}
+	case blockchain.TxFailed, blockchain.TxNotFound:
+		log.WithFields(logrus.Fields{"tx": tx, "status": status}).Warn("stakes tx isn't succeeded, rewards will be sent again")
+
+		if err := d.is.ResetRewardsQueueItems(ctx, addresses(items)); err != nil {
+			return fmt.Errorf("failed to reset items: %w", err)
+		}
 	}
 
-	log.Infof("%d rewards distributed", len(items))
+	return nil
+}
+
+// ResolveRewards resolves rewards which broadcast result is unknown. If tx is empty rewards are considered not sent
+// and are sent again by the next run, otherwise they are settled by tx hash.
+func ResolveRewards(ctx context.Context, is storage.IndexStorage, addrs []string, tx string) error {
+	return is.InTx(ctx, func(s storage.IndexStorage) error {
+		items, err := s.GetRewardsQueueItemList(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get rewards queue item list: %w", err)
+		}
+
+		unknown := make(map[string]bool, len(items))
+		for _, item := range items {
+			unknown[item.Address] = item.SentAt != nil && item.TxHash == ""
+		}
+
+		for _, addr := range addrs {
+			if !unknown[addr] {
+				return fmt.Errorf("%w: %s", ErrRewardNotUnknown, addr)
+			}
+		}
+
+		if tx == "" {
+			return s.ResetRewardsQueueItems(ctx, addrs)
+		}
+
+		return s.SetRewardsQueueItemsTx(ctx, addrs, tx)
+	})
 }
 
-func (d *Distributor) sendStakes(items []*storage.RewardsQueueItem) error {
+func (d *Distributor) sendStakes(items []*storage.RewardsQueueItem) (string, error) {
 	stakes := make([]blockchain.Stake, len(items))
 	for idx, item := range items {
 		stakes[idx] = blockchain.Stake{
@@ -144,10 +276,33 @@ func (d *Distributor) sendStakes(items []*storage.RewardsQueueItem) error {
 	return d.b.SendStakes(stakes, rewardsMemo)
 }
 
-func (d *Distributor) deleteItemsFromQueue(ctx context.Context, items []*storage.RewardsQueueItem) error {
-	return d.is.InTx(ctx, func(tx storage.IndexStorage) error {
+func (d *Distributor) waitForTx(ctx context.Context, tx string) (blockchain.TxStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, txWaitTimeout)
+	defer cancel()
+
+	status, err := d.b.WaitForTx(ctx, tx)
+	if err != nil {
+		return status, fmt.Errorf("failed to wait for tx: %w", err)
+	}
+
+	return status, nil
+}
+
+// moveItemsToHistory deletes distributed items from the queue and saves them into history.
+func (d *Distributor) moveItemsToHistory(ctx context.Context, items []*storage.RewardsQueueItem, tx string) error {
+	return d.is.InTx(ctx, func(s storage.IndexStorage) error {
 		for _, item := range items {
-			if err := tx.DeleteRewardsQueueItem(ctx, item.Address); err != nil {
+			if err := s.CreatePDVRewardsHistoryItem(ctx, &storage.PDVRewardsHistoryItem{
+				Address:     item.Address,
+				Amount:      item.Reward,
+				PeriodStart: item.PeriodStart,
+				PeriodEnd:   item.PeriodEnd,
+				TxHash:      tx,
+			}); err != nil {
+				return err
+			}
+
+			if err := s.DeleteRewardsQueueItem(ctx, item.Address); err != nil {
 				return err
 			}
 		}
@@ -155,6 +310,14 @@ func (d *Distributor) deleteItemsFromQueue(ctx context.Context, items []*storage
 	})
 }
 
+func addresses(items []*storage.RewardsQueueItem) []string {
+	addrs := make([]string, len(items))
+	for i, item := range items {
+		addrs[i] = item.Address
+	}
+	return addrs
+}
+
 func chunkSlice(slice []*storage.RewardsQueueItem, chunkSize int) [][]*storage.RewardsQueueItem {
 	var chunks [][]*storage.RewardsQueueItem
 	for {
@@ -179,6 +342,10 @@ func (d *Distributor) RunAsync(ctx context.Context, interval time.Duration) {
 	go func() {
 		ticker := time.NewTicker(10 * time.Minute)
 		for range ticker.C {
+			if !d.elector.IsLeader() {
+				continue
+			}
+
 			date, err := d.is.GetPDVRewardsDistributedDate(ctx)
 			if err != nil {
 				log.WithError(err).Error("failed to get pdv rewards distributed date")
@@ -186,6 +353,11 @@ func (d *Distributor) RunAsync(ctx context.Context, interval time.Duration) {
 			}
 
 			if time.Now().After(date.Add(interval)) {
+				if d.manualApprove {
+					log.Warn("pdv rewards plan is waiting for approval")
+					continue
+				}
+
 				// PDVRewardsDistributedDate is updated in transaction
 				d.prepareRewardsQueue()
 			}
@@ -194,11 +366,20 @@ func (d *Distributor) RunAsync(ctx context.Context, interval time.Duration) {
 
 	// if any item in distribution queue, send a reward
 	go func() {
-		d.distributeRewardsIfExist()
+		distribute := func() {
+			if !d.elector.IsLeader() {
+				log.Debug("skip rewards distribution on follower")
+				return
+			}
+
+			d.distributeRewardsIfExist()
+		}
+
+		distribute()
 
 		ticker := time.NewTicker(1 * time.Hour)
 		for range ticker.C {
-			d.distributeRewardsIfExist()
+			distribute()
 		}
 	}()
 }
@@ -6,10 +6,13 @@ import (
 	"testing"
 	"time"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/Decentr-net/cerberus/internal/blockchain"
 	blockchainmock "github.com/Decentr-net/cerberus/internal/blockchain/mock"
+	leadermock "github.com/Decentr-net/cerberus/internal/leader/mock"
 	"github.com/Decentr-net/cerberus/internal/storage"
 	"github.com/Decentr-net/cerberus/internal/storage/mock"
 )
@@ -19,7 +22,13 @@ func TestDistributor_prepareRewardsQueue_EmptyGetPDVDeltaList(t *testing.T) {
 	defer ctrl.Finish()
 
 	is := mock.NewMockIndexStorage(ctrl)
-	is.EXPECT().GetPDVDeltaList(gomock.Any()).Return([]*storage.PDVDelta{}, nil)
+	is.EXPECT().GetPDVRewardsDistributedDate(gomock.Any()).Return(time.Time{}, nil).Times(2)
+	is.EXPECT().DeletePDVRewardsCarryOverList(gomock.Any())
+	is.EXPECT().GetPDVDeltaList(gomock.Any(), time.Time{}, gomock.Any()).Return([]*storage.PDVDelta{}, nil)
+	is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.IndexStorage) error) error {
+		return f(is)
+	})
+	is.EXPECT().GetRewardsQueueItemList(gomock.Any()).Return(nil, nil)
 
 	// asset distributed date is set
 	is.EXPECT().SetPDVRewardsDistributedDate(gomock.Any(), gomock.Any()).Do(func(_ context.Context, date time.Time) {
@@ -27,7 +36,7 @@ func TestDistributor_prepareRewardsQueue_EmptyGetPDVDeltaList(t *testing.T) {
 	})
 
 	b := blockchainmock.NewMockBlockchain(ctrl)
-	d := NewDistributor(1000, b, is)
+	d := NewDistributor(1000, proportionalPolicy{}, false, leadermock.NewMockElector(ctrl), b, is)
 
 	//act
 	d.prepareRewardsQueue()
@@ -39,31 +48,34 @@ func TestDistributor_prepareRewardsQueue(t *testing.T) {
 
 	delta1 := storage.PDVDelta{
 		Address: "addr1",
-		Delta:   30,
+		Delta:   sdk.NewDec(30),
 	}
 
 	delta2 := storage.PDVDelta{
 		Address: "addr2",
-		Delta:   20,
+		Delta:   sdk.NewDec(20),
 	}
 
 	b := blockchainmock.NewMockBlockchain(ctrl)
 	is := mock.NewMockIndexStorage(ctrl)
-	is.EXPECT().GetPDVDeltaList(gomock.Any()).Return([]*storage.PDVDelta{&delta1, &delta2}, nil)
+	is.EXPECT().GetPDVRewardsDistributedDate(gomock.Any()).Return(time.Time{}, nil).Times(2)
+	is.EXPECT().DeletePDVRewardsCarryOverList(gomock.Any())
+	is.EXPECT().GetPDVDeltaList(gomock.Any(), time.Time{}, gomock.Any()).Return([]*storage.PDVDelta{&delta1, &delta2}, nil)
 	is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.IndexStorage) error) error {
 		return f(is)
 	})
+	is.EXPECT().GetRewardsQueueItemList(gomock.Any()).Return(nil, nil)
 
 	// asset 2 reward queue items created
-	is.EXPECT().CreateRewardsQueueItem(gomock.Any(), delta1.Address, int64(600))
-	is.EXPECT().CreateRewardsQueueItem(gomock.Any(), delta2.Address, int64(400))
+	is.EXPECT().CreateRewardsQueueItem(gomock.Any(), delta1.Address, int64(600), time.Time{}, gomock.Any())
+	is.EXPECT().CreateRewardsQueueItem(gomock.Any(), delta2.Address, int64(400), time.Time{}, gomock.Any())
 
 	// asset distributed date is set
 	is.EXPECT().SetPDVRewardsDistributedDate(gomock.Any(), gomock.Any()).Do(func(_ context.Context, date time.Time) {
 		require.Equal(t, time.UTC, date.Location())
 	})
 
-	d := NewDistributor(1000, b, is)
+	d := NewDistributor(1000, proportionalPolicy{}, false, leadermock.NewMockElector(ctrl), b, is)
 
 	//act
 	d.prepareRewardsQueue()
@@ -90,18 +102,225 @@ func TestDistributor_distributeRewardsIfExist(t *testing.T) {
 	}).Times(itemsCount / chunkSize)
 
 	for _, item := range items {
+		is.EXPECT().CreatePDVRewardsHistoryItem(gomock.Any(), &storage.PDVRewardsHistoryItem{
+			Address: item.Address,
+			Amount:  item.Reward,
+			TxHash:  "tx",
+		})
 		is.EXPECT().DeleteRewardsQueueItem(gomock.Any(), item.Address)
 	}
 
+	for _, chunk := range chunkSlice(items, chunkSize) {
+		is.EXPECT().SetRewardsQueueItemsSent(gomock.Any(), addresses(chunk), gomock.Any()).Return(nil)
+		is.EXPECT().SetRewardsQueueItemsTx(gomock.Any(), addresses(chunk), "tx").Return(nil)
+	}
+
+	b := blockchainmock.NewMockBlockchain(ctrl)
+	b.EXPECT().SendStakes(gomock.Any(), rewardsMemo).Return("tx", nil).Times(itemsCount / chunkSize)
+	b.EXPECT().WaitForTx(gomock.Any(), "tx").Return(blockchain.TxSucceeded, nil).Times(itemsCount / chunkSize)
+
+	d := NewDistributor(1000, proportionalPolicy{}, false, alwaysLeader(ctrl), b, is)
+
+	//act
+	d.distributeRewardsIfExist()
+}
+
+func TestDistributor_distributeRewardsIfExist_TxFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := mock.NewMockIndexStorage(ctrl)
+	is.EXPECT().GetRewardsQueueItemList(gomock.Any()).Return([]*storage.RewardsQueueItem{
+		{Address: "address1", Reward: 1},
+	}, nil)
+
+	is.EXPECT().SetRewardsQueueItemsSent(gomock.Any(), []string{"address1"}, gomock.Any()).Return(nil)
+	is.EXPECT().SetRewardsQueueItemsTx(gomock.Any(), []string{"address1"}, "tx").Return(nil)
+	// items are not deleted from the queue but reset to be sent again
+	is.EXPECT().ResetRewardsQueueItems(gomock.Any(), []string{"address1"}).Return(nil)
+
+	b := blockchainmock.NewMockBlockchain(ctrl)
+	b.EXPECT().SendStakes(gomock.Any(), rewardsMemo).Return("tx", nil)
+	b.EXPECT().WaitForTx(gomock.Any(), "tx").Return(blockchain.TxFailed, nil)
+
+	d := NewDistributor(1000, proportionalPolicy{}, false, alwaysLeader(ctrl), b, is)
+
+	//act
+	d.distributeRewardsIfExist()
+}
+
+func TestDistributor_distributeRewardsIfExist_SendError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := mock.NewMockIndexStorage(ctrl)
+	is.EXPECT().GetRewardsQueueItemList(gomock.Any()).Return([]*storage.RewardsQueueItem{
+		{Address: "address1", Reward: 1},
+	}, nil)
+	// items are left marked as sent, so they aren't sent twice
+	is.EXPECT().SetRewardsQueueItemsSent(gomock.Any(), []string{"address1"}, gomock.Any()).Return(nil)
+
+	b := blockchainmock.NewMockBlockchain(ctrl)
+	b.EXPECT().SendStakes(gomock.Any(), rewardsMemo).Return("", fmt.Errorf("timeout"))
+
+	d := NewDistributor(1000, proportionalPolicy{}, false, alwaysLeader(ctrl), b, is)
+
+	//act
+	d.distributeRewardsIfExist()
+}
+
+func TestDistributor_distributeRewardsIfExist_NotBroadcast(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := mock.NewMockIndexStorage(ctrl)
+	is.EXPECT().GetRewardsQueueItemList(gomock.Any()).Return([]*storage.RewardsQueueItem{
+		{Address: "address1", Reward: 1},
+	}, nil)
+	is.EXPECT().SetRewardsQueueItemsSent(gomock.Any(), []string{"address1"}, gomock.Any()).Return(nil)
+	// tx isn't broadcast, so items are reset to be sent again
+	is.EXPECT().ResetRewardsQueueItems(gomock.Any(), []string{"address1"}).Return(nil)
+
 	b := blockchainmock.NewMockBlockchain(ctrl)
-	b.EXPECT().SendStakes(gomock.Any(), rewardsMemo).Return(nil).Times(itemsCount / chunkSize)
+	b.EXPECT().SendStakes(gomock.Any(), rewardsMemo).Return("", fmt.Errorf("%w: address1", blockchain.ErrInvalidAddress))
 
-	d := NewDistributor(1000, b, is)
+	d := NewDistributor(1000, proportionalPolicy{}, false, alwaysLeader(ctrl), b, is)
 
 	//act
 	d.distributeRewardsIfExist()
 }
 
+func TestDistributor_distributeRewardsIfExist_Pending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fresh, expired := time.Now().Add(-time.Minute), time.Now().Add(-2*txExpiry)
+
+	is := mock.NewMockIndexStorage(ctrl)
+	is.EXPECT().GetRewardsQueueItemList(gomock.Any()).Return([]*storage.RewardsQueueItem{
+		{Address: "unknown", Reward: 1, SentAt: &expired},
+		{Address: "succeeded", Reward: 1, TxHash: "tx1", SentAt: &fresh},
+		{Address: "failed", Reward: 1, TxHash: "tx2", SentAt: &fresh},
+		{Address: "not_included", Reward: 1, TxHash: "tx3", SentAt: &fresh},
+		{Address: "expired", Reward: 1, TxHash: "tx4", SentAt: &expired},
+	}, nil)
+
+	b := blockchainmock.NewMockBlockchain(ctrl)
+	b.EXPECT().GetTxStatus(gomock.Any(), "tx1").Return(blockchain.TxSucceeded, nil)
+	b.EXPECT().GetTxStatus(gomock.Any(), "tx2").Return(blockchain.TxFailed, nil)
+	b.EXPECT().GetTxStatus(gomock.Any(), "tx3").Return(blockchain.TxNotFound, nil)
+	b.EXPECT().GetTxStatus(gomock.Any(), "tx4").Return(blockchain.TxNotFound, nil)
+
+	is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.IndexStorage) error) error {
+		return f(is)
+	})
+	is.EXPECT().CreatePDVRewardsHistoryItem(gomock.Any(), &storage.PDVRewardsHistoryItem{
+		Address: "succeeded",
+		Amount:  1,
+		TxHash:  "tx1",
+	})
+	is.EXPECT().DeleteRewardsQueueItem(gomock.Any(), "succeeded")
+	is.EXPECT().ResetRewardsQueueItems(gomock.Any(), []string{"failed"}).Return(nil)
+	is.EXPECT().ResetRewardsQueueItems(gomock.Any(), []string{"expired"}).Return(nil)
+
+	d := NewDistributor(1000, proportionalPolicy{}, false, alwaysLeader(ctrl), b, is)
+
+	//act
+	d.distributeRewardsIfExist()
+}
+
+func TestDistributor_distributeRewardsIfExist_LeadershipLost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	items := make([]*storage.RewardsQueueItem, 2*chunkSize)
+	for i := range items {
+		items[i] = &storage.RewardsQueueItem{
+			Address: fmt.Sprint("address", i+1),
+			Reward:  int64(i + 1),
+		}
+	}
+	chunk := items[:chunkSize]
+
+	is := mock.NewMockIndexStorage(ctrl)
+	is.EXPECT().GetRewardsQueueItemList(gomock.Any()).Return(items, nil)
+	is.EXPECT().SetRewardsQueueItemsSent(gomock.Any(), addresses(chunk), gomock.Any()).Return(nil)
+	is.EXPECT().SetRewardsQueueItemsTx(gomock.Any(), addresses(chunk), "tx").Return(nil)
+	is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.IndexStorage) error) error {
+		return f(is)
+	})
+	is.EXPECT().CreatePDVRewardsHistoryItem(gomock.Any(), gomock.Any()).Times(chunkSize)
+	is.EXPECT().DeleteRewardsQueueItem(gomock.Any(), gomock.Any()).Times(chunkSize)
+
+	b := blockchainmock.NewMockBlockchain(ctrl)
+	b.EXPECT().SendStakes(gomock.Any(), rewardsMemo).Return("tx", nil)
+	b.EXPECT().WaitForTx(gomock.Any(), "tx").Return(blockchain.TxSucceeded, nil)
+
+	// the second chunk isn't sent
+	elector := leadermock.NewMockElector(ctrl)
+	gomock.InOrder(
+		elector.EXPECT().IsLeader().Return(true),
+		elector.EXPECT().IsLeader().Return(false),
+	)
+
+	d := NewDistributor(1000, proportionalPolicy{}, false, elector, b, is)
+
+	//act
+	d.distributeRewardsIfExist()
+}
+
+func alwaysLeader(ctrl *gomock.Controller) *leadermock.MockElector {
+	elector := leadermock.NewMockElector(ctrl)
+	elector.EXPECT().IsLeader().Return(true).AnyTimes()
+	return elector
+}
+
+func TestResolveRewards(t *testing.T) {
+	sentAt := time.Now()
+
+	tt := []struct {
+		name  string
+		addrs []string
+		tx    string
+		err   error
+	}{
+		{name: "reset", addrs: []string{"unknown"}},
+		{name: "tx", addrs: []string{"unknown"}, tx: "tx"},
+		{name: "not_sent", addrs: []string{"unknown", "queued"}, err: ErrRewardNotUnknown},
+		{name: "sent", addrs: []string{"sent"}, tx: "tx", err: ErrRewardNotUnknown},
+		{name: "not_found", addrs: []string{"missing"}, err: ErrRewardNotUnknown},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			is := mock.NewMockIndexStorage(ctrl)
+			is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.IndexStorage) error) error {
+				return f(is)
+			})
+			is.EXPECT().GetRewardsQueueItemList(gomock.Any()).Return([]*storage.RewardsQueueItem{
+				{Address: "queued", Reward: 1},
+				{Address: "sent", Reward: 1, TxHash: "tx1", SentAt: &sentAt},
+				{Address: "unknown", Reward: 1, SentAt: &sentAt},
+			}, nil)
+
+			if tc.err == nil {
+				if tc.tx == "" {
+					is.EXPECT().ResetRewardsQueueItems(gomock.Any(), tc.addrs).Return(nil)
+				} else {
+					is.EXPECT().SetRewardsQueueItemsTx(gomock.Any(), tc.addrs, tc.tx).Return(nil)
+				}
+			}
+
+			require.ErrorIs(t, ResolveRewards(context.Background(), is, tc.addrs, tc.tx), tc.err)
+		})
+	}
+}
+
 func TestChunkSlice(t *testing.T) {
 	items := make([]*storage.RewardsQueueItem, 50)
 	for i := 0; i < 50; i++ {
@@ -0,0 +1,189 @@
+package pdvrewards
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Decentr-net/cerberus/internal/storage"
+)
+
+const (
+	// ExcludedBanned is a reason of exclusion of banned address.
+	ExcludedBanned = "banned"
+	// ExcludedRoundedToZero is a reason of exclusion of address which reward is less than 1 uDEC.
+	ExcludedRoundedToZero = "rounded to zero"
+	// ExcludedBelowThreshold is a reason of exclusion of address which reward is less than minimal payout.
+	ExcludedBelowThreshold = "below threshold"
+)
+
+var (
+	// ErrStalePlan is returned when plan is approved after another distribution.
+	ErrStalePlan = errors.New("plan is stale")
+	// ErrInvalidPlan is returned when plan's period is invalid, e.g. plan is made by the previous version.
+	ErrInvalidPlan = errors.New("plan is invalid")
+	// ErrQueueNotEmpty is returned when plan is approved while rewards of the previous distribution are being sent.
+	ErrQueueNotEmpty = errors.New("rewards queue isn't empty")
+)
+
+// Plan is a payout which is going to be distributed.
+type Plan struct {
+	// Since is the date of the previous distribution, plan includes PDV created after it.
+	Since time.Time `json:"since"`
+	// Until is the cutoff of the plan, plan includes PDV created until it. It becomes the new distribution date.
+	Until      time.Time   `json:"until"`
+	PoolSize   int64       `json:"pool_size"`
+	TotalDelta sdk.Dec     `json:"total_delta"`
+	Items      []*PlanItem `json:"items"`
+}
+
+// PlanItem is a payout of the address.
+type PlanItem struct {
+	Address string  `json:"address"`
+	Delta   sdk.Dec `json:"delta"`
+	// Multiplier is a delta multiplier applied by the policy.
+	Multiplier sdk.Dec `json:"multiplier"`
+	// Share is a share of the pool.
+	Share sdk.Dec `json:"share"`
+	// Reward is a reward in uDEC, rewards of the plan sum up to the pool size.
+	Reward int64 `json:"reward"`
+	// Excluded contains the reason why the address isn't rewarded.
+	Excluded string `json:"excluded,omitempty"`
+	// CarryOver is true when the delta is added to the next distribution.
+	CarryOver bool `json:"carry_over,omitempty"`
+}
+
+// MakePlan computes the next payout using the policy.
+func MakePlan(ctx context.Context, is storage.IndexStorage, policy RewardPolicy, poolSize int64) (*Plan, error) {
+	since, err := is.GetPDVRewardsDistributedDate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pdv rewards distributed date: %w", err)
+	}
+
+	// postgres keeps microseconds, so the cutoff is truncated to be stored as is
+	until := time.Now().UTC().Truncate(time.Microsecond)
+
+	deltas, err := is.GetPDVDeltaList(ctx, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get PDV delta list: %w", err)
+	}
+
+	p := Plan{
+		Since:      since,
+		Until:      until,
+		PoolSize:   poolSize,
+		TotalDelta: sdk.ZeroDec(),
+		Items:      make([]*PlanItem, 0, len(deltas)),
+	}
+
+	var eligible []*storage.PDVDelta
+	for _, delta := range deltas {
+		if !delta.Banned {
+			eligible = append(eligible, delta)
+			p.TotalDelta = p.TotalDelta.Add(delta.Delta)
+		}
+	}
+
+	// policy returns items in the same order, so the plan is sorted like deltas
+	items := policy.Apply(eligible, poolSize, until)
+
+	for _, delta := range deltas {
+		if delta.Banned {
+			item := newPlanItem(delta.Address, delta.Delta)
+			item.Excluded = ExcludedBanned
+
+			p.Items = append(p.Items, item)
+			continue
+		}
+
+		p.Items = append(p.Items, items[0])
+		items = items[1:]
+	}
+
+	return &p, nil
+}
+
+// ApprovePlan puts plan's rewards into rewards queue.
+func ApprovePlan(ctx context.Context, is storage.IndexStorage, p *Plan) error {
+	return is.InTx(ctx, func(tx storage.IndexStorage) error {
+		// plan shouldn't be approved twice or after automatic distribution
+		date, err := tx.GetPDVRewardsDistributedDate(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get pdv rewards distributed date: %w", err)
+		}
+
+		if !date.Equal(p.Since) {
+			return ErrStalePlan
+		}
+
+		if !p.Until.After(p.Since) {
+			return ErrInvalidPlan
+		}
+
+		// queue items are identified by address, so the plan can't be approved until the previous one is distributed
+		queue, err := tx.GetRewardsQueueItemList(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get rewards queue item list: %w", err)
+		}
+
+		if len(queue) > 0 {
+			return ErrQueueNotEmpty
+		}
+
+		// carried over deltas are included into the plan, so they are replaced with the new ones
+		if err := tx.DeletePDVRewardsCarryOverList(ctx); err != nil {
+			return fmt.Errorf("failed to delete carry over list: %w", err)
+		}
+
+		for _, v := range p.Items {
+			if v.CarryOver {
+				if err := tx.CreatePDVRewardsCarryOverItem(ctx, v.Address, v.Delta); err != nil {
+					return fmt.Errorf("failed to create carry over item: %w", err)
+				}
+			}
+
+			if v.Excluded != "" {
+				continue
+			}
+
+			if err := tx.CreateRewardsQueueItem(ctx, v.Address, v.Reward, p.Since, p.Until); err != nil {
+				return fmt.Errorf("failed to create rewards quque item: %w", err)
+			}
+		}
+
+		return tx.SetPDVRewardsDistributedDate(ctx, p.Until)
+	})
+}
+
+// WriteCSV writes plan as csv.
+func (p *Plan) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"address", "delta", "multiplier", "share", "reward", "excluded", "carry_over"}); err != nil {
+		return err
+	}
+
+	for _, v := range p.Items {
+		if err := cw.Write([]string{
+			v.Address,
+			v.Delta.String(),
+			v.Multiplier.String(),
+			v.Share.String(),
+			strconv.FormatInt(v.Reward, 10),
+			v.Excluded,
+			strconv.FormatBool(v.CarryOver),
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
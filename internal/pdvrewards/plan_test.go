@@ -0,0 +1,169 @@
+package pdvrewards
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Decentr-net/cerberus/internal/storage"
+	"github.com/Decentr-net/cerberus/internal/storage/mock"
+)
+
+var (
+	since = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	until = time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func TestMakePlan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var cutoff time.Time
+
+	is := mock.NewMockIndexStorage(ctrl)
+	is.EXPECT().GetPDVRewardsDistributedDate(gomock.Any()).Return(since, nil)
+	is.EXPECT().GetPDVDeltaList(gomock.Any(), since, gomock.Any()).DoAndReturn(func(_ context.Context, _, u time.Time) ([]*storage.PDVDelta, error) {
+		cutoff = u
+		return []*storage.PDVDelta{
+			{Address: "addr1", Delta: dec("30")},
+			{Address: "addr2", Delta: dec("20")},
+			{Address: "addr3", Delta: dec("0.001")},
+			{Address: "addr4", Delta: dec("50"), Banned: true},
+		}, nil
+	})
+
+	p, err := MakePlan(context.Background(), is, proportionalPolicy{}, 1000)
+	require.NoError(t, err)
+	require.Equal(t, since, p.Since)
+	require.Equal(t, cutoff, p.Until)
+	require.Equal(t, int64(1000), p.PoolSize)
+	require.Equal(t, "50.001000000000000000", p.TotalDelta.String())
+	requireItems(t, []*PlanItem{
+		{Address: "addr1", Delta: dec("30"), Multiplier: sdk.OneDec(), Share: dec("0.599988000239995200"), Reward: 600},
+		{Address: "addr2", Delta: dec("20"), Multiplier: sdk.OneDec(), Share: dec("0.399992000159996800"), Reward: 400},
+		{Address: "addr3", Delta: dec("0.001"), Multiplier: sdk.OneDec(), Share: dec("0.000019999600008000"), Excluded: ExcludedRoundedToZero},
+		{Address: "addr4", Delta: dec("50"), Multiplier: sdk.OneDec(), Share: sdk.ZeroDec(), Excluded: ExcludedBanned},
+	}, p.Items)
+}
+
+func TestApprovePlan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := mock.NewMockIndexStorage(ctrl)
+	is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.IndexStorage) error) error {
+		return f(is)
+	})
+	is.EXPECT().GetPDVRewardsDistributedDate(gomock.Any()).Return(since, nil)
+	is.EXPECT().GetRewardsQueueItemList(gomock.Any()).Return(nil, nil)
+	is.EXPECT().DeletePDVRewardsCarryOverList(gomock.Any())
+	is.EXPECT().CreateRewardsQueueItem(gomock.Any(), "addr1", int64(600), since, until)
+	is.EXPECT().CreatePDVRewardsCarryOverItem(gomock.Any(), "addr3", dec("0.5"))
+	is.EXPECT().SetPDVRewardsDistributedDate(gomock.Any(), until)
+
+	require.NoError(t, ApprovePlan(context.Background(), is, &Plan{
+		Since: since,
+		Until: until,
+		Items: []*PlanItem{
+			{Address: "addr1", Reward: 600},
+			{Address: "addr2", Excluded: ExcludedBanned},
+			{Address: "addr3", Delta: dec("0.5"), Excluded: ExcludedBelowThreshold, CarryOver: true},
+		},
+	}))
+}
+
+func TestApprovePlan_Stale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := mock.NewMockIndexStorage(ctrl)
+	is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.IndexStorage) error) error {
+		return f(is)
+	})
+	is.EXPECT().GetPDVRewardsDistributedDate(gomock.Any()).Return(since.Add(time.Hour), nil)
+
+	require.ErrorIs(t, ApprovePlan(context.Background(), is, &Plan{
+		Since: since,
+		Until: until,
+		Items: []*PlanItem{{Address: "addr1", Reward: 600}},
+	}), ErrStalePlan)
+}
+
+func TestApprovePlan_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := mock.NewMockIndexStorage(ctrl)
+	is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.IndexStorage) error) error {
+		return f(is)
+	})
+	is.EXPECT().GetPDVRewardsDistributedDate(gomock.Any()).Return(since, nil)
+
+	// plan without cutoff
+	require.ErrorIs(t, ApprovePlan(context.Background(), is, &Plan{
+		Since: since,
+		Items: []*PlanItem{{Address: "addr1", Reward: 600}},
+	}), ErrInvalidPlan)
+}
+
+func TestApprovePlan_QueueNotEmpty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := mock.NewMockIndexStorage(ctrl)
+	is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.IndexStorage) error) error {
+		return f(is)
+	})
+	is.EXPECT().GetPDVRewardsDistributedDate(gomock.Any()).Return(since, nil)
+	is.EXPECT().GetRewardsQueueItemList(gomock.Any()).Return([]*storage.RewardsQueueItem{
+		{Address: "addr1", Reward: 100},
+	}, nil)
+
+	require.ErrorIs(t, ApprovePlan(context.Background(), is, &Plan{
+		Since: since,
+		Until: until,
+		Items: []*PlanItem{{Address: "addr1", Reward: 600}},
+	}), ErrQueueNotEmpty)
+}
+
+func TestPlan_WriteCSV(t *testing.T) {
+	p := Plan{
+		Items: []*PlanItem{
+			{Address: "addr1", Delta: dec("30"), Multiplier: sdk.OneDec(), Share: dec("0.75"), Reward: 750},
+			{Address: "addr2", Delta: dec("10"), Multiplier: sdk.OneDec(), Share: sdk.ZeroDec(), Excluded: ExcludedBanned},
+			{Address: "addr3", Delta: dec("1"), Multiplier: dec("1.5"), Share: sdk.ZeroDec(), Excluded: ExcludedBelowThreshold, CarryOver: true},
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, p.WriteCSV(&b))
+	require.Equal(t, `address,delta,multiplier,share,reward,excluded,carry_over
+addr1,30.000000000000000000,1.000000000000000000,0.750000000000000000,750,,false
+addr2,10.000000000000000000,1.000000000000000000,0.000000000000000000,0,banned,false
+addr3,1.000000000000000000,1.500000000000000000,0.000000000000000000,0,below threshold,true
+`, b.String())
+}
+
+func dec(s string) sdk.Dec {
+	return sdk.MustNewDecFromStr(s)
+}
+
+// requireItems compares plan items by value, sdk.Dec can't be compared with require.Equal.
+func requireItems(t *testing.T, expected, actual []*PlanItem) {
+	str := func(items []*PlanItem) []string {
+		out := make([]string, len(items))
+		for i, v := range items {
+			out[i] = fmt.Sprintf("%s delta=%s multiplier=%s share=%s reward=%d excluded=%q carry_over=%t",
+				v.Address, v.Delta, v.Multiplier, v.Share, v.Reward, v.Excluded, v.CarryOver)
+		}
+		return out
+	}
+
+	require.Equal(t, str(expected), str(actual))
+}
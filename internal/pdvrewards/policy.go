@@ -0,0 +1,346 @@
+package pdvrewards
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"gopkg.in/yaml.v2"
+
+	"github.com/Decentr-net/cerberus/internal/storage"
+)
+
+const (
+	// PolicyProportional is a name of the proportional policy.
+	PolicyProportional = "proportional"
+	// PolicyCap is a name of the per-account cap policy.
+	PolicyCap = "cap"
+	// PolicyThreshold is a name of the minimum payout threshold policy.
+	PolicyThreshold = "threshold"
+	// PolicyTiered is a name of the account age tiers policy.
+	PolicyTiered = "tiered"
+)
+
+// RewardPolicy splits rewards pool between accounts.
+type RewardPolicy interface {
+	// Apply returns plan item for every delta in the same order. Deltas of banned accounts are never passed.
+	// now is the end of the rewarded period.
+	Apply(deltas []*storage.PDVDelta, poolSize int64, now time.Time) []*PlanItem
+}
+
+// PolicyConfig is a reward policy configuration.
+type PolicyConfig struct {
+	Policy    string          `yaml:"policy"`
+	Cap       CapConfig       `yaml:"cap"`
+	Threshold ThresholdConfig `yaml:"threshold"`
+	Tiered    TieredConfig    `yaml:"tiered"`
+}
+
+// CapConfig is a configuration of the cap policy.
+type CapConfig struct {
+	// MaxReward is a maximal reward of the account in uDEC.
+	MaxReward int64 `yaml:"max_reward"`
+}
+
+// ThresholdConfig is a configuration of the threshold policy.
+type ThresholdConfig struct {
+	// MinPayout is a minimal reward of the account in uDEC.
+	MinPayout int64 `yaml:"min_payout"`
+}
+
+// TieredConfig is a configuration of the tiered policy.
+type TieredConfig struct {
+	Tiers []Tier `yaml:"tiers"`
+}
+
+// Tier is a delta multiplier of accounts which are older than MinAge.
+type Tier struct {
+	MinAge     time.Duration `yaml:"min_age"`
+	Multiplier float64       `yaml:"multiplier"`
+}
+
+// LoadPolicy reads policy configuration from yaml file and creates the policy.
+func LoadPolicy(path string) (RewardPolicy, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy config: %w", err)
+	}
+
+	var c PolicyConfig
+	if err := yaml.UnmarshalStrict(b, &c); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal policy config: %w", err)
+	}
+
+	return NewPolicy(c)
+}
+
+// NewPolicy creates the policy chosen in the config.
+func NewPolicy(c PolicyConfig) (RewardPolicy, error) {
+	switch c.Policy {
+	case PolicyProportional:
+		return proportionalPolicy{}, nil
+	case PolicyCap:
+		if c.Cap.MaxReward <= 0 {
+			return nil, fmt.Errorf("invalid max reward %d", c.Cap.MaxReward)
+		}
+		return capPolicy{maxReward: c.Cap.MaxReward}, nil
+	case PolicyThreshold:
+		if c.Threshold.MinPayout <= 0 {
+			return nil, fmt.Errorf("invalid min payout %d", c.Threshold.MinPayout)
+		}
+		return thresholdPolicy{minPayout: c.Threshold.MinPayout}, nil
+	case PolicyTiered:
+		tiers := make([]tier, len(c.Tiered.Tiers))
+		for i, v := range c.Tiered.Tiers {
+			if v.MinAge < 0 || v.Multiplier <= 0 {
+				return nil, fmt.Errorf("invalid tier %+v", v)
+			}
+
+			m, err := sdk.NewDecFromStr(strconv.FormatFloat(v.Multiplier, 'f', -1, 64))
+			if err != nil {
+				return nil, fmt.Errorf("invalid tier multiplier %v: %w", v.Multiplier, err)
+			}
+
+			tiers[i] = tier{minAge: v.MinAge, multiplier: m}
+		}
+
+		// the oldest tier goes first
+		sort.Slice(tiers, func(i, j int) bool {
+			return tiers[i].minAge > tiers[j].minAge
+		})
+
+		return tieredPolicy{tiers: tiers}, nil
+	default:
+		return nil, fmt.Errorf("unknown policy %q", c.Policy)
+	}
+}
+
+// proportionalPolicy gives reward proportional to the delta.
+type proportionalPolicy struct{}
+
+func (proportionalPolicy) Apply(deltas []*storage.PDVDelta, poolSize int64, _ time.Time) []*PlanItem {
+	items := newPlanItems(deltas)
+	allocate(items, weights(deltas), poolSize, poolSize)
+
+	return items
+}
+
+// capPolicy gives reward proportional to the delta but not more than maxReward.
+// The excess is split between other accounts.
+type capPolicy struct {
+	maxReward int64
+}
+
+func (p capPolicy) Apply(deltas []*storage.PDVDelta, poolSize int64, _ time.Time) []*PlanItem {
+	var (
+		items  = newPlanItems(deltas)
+		w      = weights(deltas)
+		capped = make([]bool, len(items))
+		pool   = poolSize
+	)
+
+	for {
+		var (
+			total   = sdk.ZeroDec()
+			changed bool
+		)
+
+		for i, v := range w {
+			if !capped[i] {
+				total = total.Add(v)
+			}
+		}
+
+		for i, v := range w {
+			if !capped[i] && v.MulInt64(pool).Quo(total).GT(sdk.NewDec(p.maxReward)) {
+				capped[i] = true
+				pool -= p.maxReward
+				changed = true
+			}
+		}
+
+		if !changed {
+			break
+		}
+	}
+
+	var uncapped []*PlanItem
+	var uncappedWeights []sdk.Dec
+	for i, v := range items {
+		if capped[i] {
+			v.Share = sdk.NewDec(p.maxReward).QuoInt64(poolSize)
+			v.Reward = p.maxReward
+			continue
+		}
+
+		uncapped = append(uncapped, v)
+		uncappedWeights = append(uncappedWeights, w[i])
+	}
+
+	allocate(uncapped, uncappedWeights, pool, poolSize)
+
+	return items
+}
+
+// thresholdPolicy doesn't pay rewards less than minPayout, such deltas are carried over to the next period.
+// The pool is split between other accounts.
+type thresholdPolicy struct {
+	minPayout int64
+}
+
+func (p thresholdPolicy) Apply(deltas []*storage.PDVDelta, poolSize int64, _ time.Time) []*PlanItem {
+	var (
+		items = newPlanItems(deltas)
+		w     = weights(deltas)
+	)
+
+	for {
+		var (
+			paid        []*PlanItem
+			paidWeights []sdk.Dec
+		)
+
+		for i, v := range items {
+			if !v.CarryOver {
+				paid = append(paid, v)
+				paidWeights = append(paidWeights, w[i])
+			}
+		}
+
+		allocate(paid, paidWeights, poolSize, poolSize)
+
+		var changed bool
+		for _, v := range paid {
+			if v.Reward < p.minPayout {
+				*v = *newPlanItem(v.Address, v.Delta)
+				v.Excluded = ExcludedBelowThreshold
+				v.CarryOver = true
+				changed = true
+			}
+		}
+
+		if !changed {
+			return items
+		}
+	}
+}
+
+// tieredPolicy multiplies delta by the multiplier of the highest tier reached by the account age.
+// Accounts without profile and accounts which haven't reached any tier have multiplier 1.
+type tieredPolicy struct {
+	// tiers are sorted by minAge in descending order
+	tiers []tier
+}
+
+type tier struct {
+	minAge     time.Duration
+	multiplier sdk.Dec
+}
+
+func (p tieredPolicy) Apply(deltas []*storage.PDVDelta, poolSize int64, now time.Time) []*PlanItem {
+	var (
+		items = newPlanItems(deltas)
+		w     = make([]sdk.Dec, len(deltas))
+	)
+
+	for i, v := range deltas {
+		items[i].Multiplier = p.multiplier(v.AccountCreatedAt, now)
+		w[i] = v.Delta.Mul(items[i].Multiplier)
+	}
+
+	allocate(items, w, poolSize, poolSize)
+
+	return items
+}
+
+func (p tieredPolicy) multiplier(createdAt *time.Time, now time.Time) sdk.Dec {
+	if createdAt == nil {
+		return sdk.OneDec()
+	}
+
+	age := now.Sub(*createdAt)
+	for _, v := range p.tiers {
+		if age >= v.minAge {
+			return v.multiplier
+		}
+	}
+
+	return sdk.OneDec()
+}
+
+func newPlanItems(deltas []*storage.PDVDelta) []*PlanItem {
+	items := make([]*PlanItem, len(deltas))
+	for i, v := range deltas {
+		items[i] = newPlanItem(v.Address, v.Delta)
+	}
+
+	return items
+}
+
+func newPlanItem(address string, delta sdk.Dec) *PlanItem {
+	return &PlanItem{
+		Address:    address,
+		Delta:      delta,
+		Multiplier: sdk.OneDec(),
+		Share:      sdk.ZeroDec(),
+	}
+}
+
+func weights(deltas []*storage.PDVDelta) []sdk.Dec {
+	w := make([]sdk.Dec, len(deltas))
+	for i, v := range deltas {
+		w[i] = v.Delta
+	}
+
+	return w
+}
+
+// allocate splits pool between items proportionally to weights using the largest remainder method,
+// so rewards sum up to the pool exactly. Share is calculated against poolSize.
+func allocate(items []*PlanItem, weights []sdk.Dec, pool, poolSize int64) {
+	total := sdk.ZeroDec()
+	for _, v := range weights {
+		total = total.Add(v)
+	}
+
+	if !total.IsPositive() || poolSize == 0 {
+		return
+	}
+
+	var (
+		remainders = make([]sdk.Dec, len(items))
+		order      = make([]int, len(items))
+		left       = pool
+	)
+
+	for i, v := range items {
+		// truncation guarantees that rewards don't exceed the pool
+		exact := weights[i].MulInt64(pool).QuoTruncate(total)
+
+		v.Share = weights[i].MulInt64(pool).Quo(total.MulInt64(poolSize))
+		v.Reward = exact.TruncateInt64()
+
+		remainders[i] = exact.Sub(sdk.NewDec(v.Reward))
+		order[i] = i
+		left -= v.Reward
+	}
+
+	// the rest of the pool is given by 1 uDEC to items with the largest remainders
+	sort.SliceStable(order, func(i, j int) bool {
+		return remainders[order[i]].GT(remainders[order[j]])
+	})
+
+	for i := 0; int64(i) < left && i < len(order); i++ {
+		items[order[i]].Reward++
+	}
+
+	for _, v := range items {
+		v.Excluded = ""
+		if v.Reward == 0 {
+			v.Excluded = ExcludedRoundedToZero
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package pdvrewards
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Decentr-net/cerberus/internal/storage"
+)
+
+func TestLoadPolicy(t *testing.T) {
+	p, err := LoadPolicy("../../configs/rewards_policy.yml")
+	require.NoError(t, err)
+	require.Equal(t, proportionalPolicy{}, p)
+}
+
+func TestNewPolicy(t *testing.T) {
+	tt := []struct {
+		name   string
+		config PolicyConfig
+		policy RewardPolicy
+		valid  bool
+	}{
+		{
+			name:   "proportional",
+			config: PolicyConfig{Policy: PolicyProportional},
+			policy: proportionalPolicy{},
+			valid:  true,
+		},
+		{
+			name:   "cap",
+			config: PolicyConfig{Policy: PolicyCap, Cap: CapConfig{MaxReward: 10}},
+			policy: capPolicy{maxReward: 10},
+			valid:  true,
+		},
+		{
+			name:   "invalid cap",
+			config: PolicyConfig{Policy: PolicyCap},
+		},
+		{
+			name:   "threshold",
+			config: PolicyConfig{Policy: PolicyThreshold, Threshold: ThresholdConfig{MinPayout: 10}},
+			policy: thresholdPolicy{minPayout: 10},
+			valid:  true,
+		},
+		{
+			name:   "invalid threshold",
+			config: PolicyConfig{Policy: PolicyThreshold, Threshold: ThresholdConfig{MinPayout: -1}},
+		},
+		{
+			name:   "invalid tier",
+			config: PolicyConfig{Policy: PolicyTiered, Tiered: TieredConfig{Tiers: []Tier{{MinAge: time.Hour}}}},
+		},
+		{
+			name:   "unknown",
+			config: PolicyConfig{Policy: "unknown"},
+		},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := NewPolicy(tc.config)
+			if !tc.valid {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.policy, p)
+		})
+	}
+}
+
+func TestNewPolicy_Tiered(t *testing.T) {
+	p, err := NewPolicy(PolicyConfig{Policy: PolicyTiered, Tiered: TieredConfig{Tiers: []Tier{
+		{MinAge: time.Hour, Multiplier: 1.5},
+		{MinAge: 2 * time.Hour, Multiplier: 2},
+	}}})
+	require.NoError(t, err)
+
+	tiers := p.(tieredPolicy).tiers
+	require.Len(t, tiers, 2)
+	require.Equal(t, 2*time.Hour, tiers[0].minAge)
+	require.Equal(t, "2.000000000000000000", tiers[0].multiplier.String())
+	require.Equal(t, time.Hour, tiers[1].minAge)
+	require.Equal(t, "1.500000000000000000", tiers[1].multiplier.String())
+}
+
+func TestCapPolicy_Apply(t *testing.T) {
+	items := capPolicy{maxReward: 500}.Apply([]*storage.PDVDelta{
+		{Address: "addr1", Delta: dec("80")},
+		{Address: "addr2", Delta: dec("10")},
+		{Address: "addr3", Delta: dec("10")},
+	}, 1000, time.Now())
+
+	requireItems(t, []*PlanItem{
+		{Address: "addr1", Delta: dec("80"), Multiplier: sdk.OneDec(), Share: dec("0.5"), Reward: 500},
+		{Address: "addr2", Delta: dec("10"), Multiplier: sdk.OneDec(), Share: dec("0.25"), Reward: 250},
+		{Address: "addr3", Delta: dec("10"), Multiplier: sdk.OneDec(), Share: dec("0.25"), Reward: 250},
+	}, items)
+}
+
+func TestThresholdPolicy_Apply(t *testing.T) {
+	items := thresholdPolicy{minPayout: 50}.Apply([]*storage.PDVDelta{
+		{Address: "addr1", Delta: dec("90")},
+		{Address: "addr2", Delta: dec("9")},
+		{Address: "addr3", Delta: dec("1")},
+	}, 1000, time.Now())
+
+	requireItems(t, []*PlanItem{
+		{Address: "addr1", Delta: dec("90"), Multiplier: sdk.OneDec(), Share: dec("0.909090909090909091"), Reward: 909},
+		{Address: "addr2", Delta: dec("9"), Multiplier: sdk.OneDec(), Share: dec("0.090909090909090909"), Reward: 91},
+		{Address: "addr3", Delta: dec("1"), Multiplier: sdk.OneDec(), Share: sdk.ZeroDec(), Excluded: ExcludedBelowThreshold, CarryOver: true},
+	}, items)
+}
+
+func TestTieredPolicy_Apply(t *testing.T) {
+	now := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	old, young := now.Add(-1000*time.Hour), now.Add(-time.Hour)
+
+	p, err := NewPolicy(PolicyConfig{Policy: PolicyTiered, Tiered: TieredConfig{Tiers: []Tier{
+		{MinAge: 720 * time.Hour, Multiplier: 1.5},
+		{MinAge: 8760 * time.Hour, Multiplier: 2},
+	}}})
+	require.NoError(t, err)
+
+	items := p.Apply([]*storage.PDVDelta{
+		{Address: "addr1", Delta: dec("10"), AccountCreatedAt: &old},
+		{Address: "addr2", Delta: dec("10")},
+		{Address: "addr3", Delta: dec("10"), AccountCreatedAt: &young},
+	}, 1000, now)
+
+	requireItems(t, []*PlanItem{
+		{Address: "addr1", Delta: dec("10"), Multiplier: dec("1.5"), Share: dec("0.428571428571428571"), Reward: 428},
+		{Address: "addr2", Delta: dec("10"), Multiplier: sdk.OneDec(), Share: dec("0.285714285714285714"), Reward: 286},
+		{Address: "addr3", Delta: dec("10"), Multiplier: sdk.OneDec(), Share: dec("0.285714285714285714"), Reward: 286},
+	}, items)
+}
+
+func TestAllocate(t *testing.T) {
+	items := proportionalPolicy{}.Apply([]*storage.PDVDelta{
+		{Address: "addr1", Delta: dec("0.000001")},
+		{Address: "addr2", Delta: dec("0.000001")},
+		{Address: "addr3", Delta: dec("0.000001")},
+		{Address: "addr4", Delta: dec("0.000000000000000001")},
+	}, 100, time.Now())
+
+	var total int64
+	for _, v := range items {
+		total += v.Reward
+	}
+
+	require.EqualValues(t, 100, total)
+	require.Equal(t, []int64{34, 33, 33, 0}, []int64{items[0].Reward, items[1].Reward, items[2].Reward, items[3].Reward})
+	require.Equal(t, ExcludedRoundedToZero, items[3].Excluded)
+}
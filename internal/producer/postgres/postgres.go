@@ -0,0 +1,38 @@
+// Package postgres is a postgres outbox implementation of producer
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Decentr-net/cerberus/internal/producer"
+)
+
+var _ producer.Producer = &impl{}
+
+type impl struct {
+	db *sql.DB
+}
+
+// New returns new instance of impl.
+func New(db *sql.DB) *impl { // nolint:golint
+	return &impl{
+		db: db,
+	}
+}
+
+// Produce inserts message into pdv_outbox table.
+func (i impl) Produce(ctx context.Context, m *producer.PDVMessage) error {
+	body, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	if _, err := i.db.ExecContext(ctx, `INSERT INTO pdv_outbox(body) VALUES($1)`, body); err != nil {
+		return fmt.Errorf("failed to insert message: %w", err)
+	}
+
+	return nil
+}
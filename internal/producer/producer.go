@@ -15,7 +15,8 @@ type PDVMessage struct {
 	Address string
 	Device  string
 	Meta    *entities.PDVMeta
-	Data    []byte
+	// Data is encrypted pdv. It's empty when pdv is already written into the file storage by producer side.
+	Data []byte
 }
 
 // Producer ...
@@ -1,53 +1,251 @@
-// Package refine ...
+// Package refine checks quality of PDV data, data which doesn't pass rules isn't rewarded.
 package refine
 
 import (
-	"unicode/utf8"
+	"fmt"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v2"
 
 	"github.com/Decentr-net/cerberus/pkg/schema"
 )
 
-// Cookie refines the given cookie is not autogenerated.
-func Cookie(cookie *schema.V1Cookie) bool {
-	runeCount := utf8.RuneCount([]byte(cookie.Value))
+// Rule checks quality of the data.
+type Rule interface {
+	// Name returns name of the rule which is recorded when the data is rejected.
+	Name() string
+	// Check returns false if the data is rejected.
+	Check(d schema.Data) bool
+}
+
+// Registry contains rules by data type.
+type Registry map[schema.Type][]Rule
 
-	if runeCount < 3 {
-		return false
-	}
+// Register adds the rule for the data type, rules are checked in order of registration.
+func (r Registry) Register(t schema.Type, rule Rule) {
+	r[t] = append(r[t], rule)
+}
 
-	if runeCount >= 6 && validateRunesSame(cookie.Value) {
-		return false
+// Check returns name of the first rule which rejected the data or empty string if the data passed all rules.
+func (r Registry) Check(d schema.Data) string {
+	for _, v := range r[d.Type()] {
+		if !v.Check(d) {
+			return v.Name()
+		}
 	}
 
-	return true
+	return ""
+}
+
+// Config is a configuration of rules by data type.
+type Config struct {
+	Cookie          CookieConfig          `yaml:"cookie"`
+	SearchHistory   SearchHistoryConfig   `yaml:"search_history"`
+	Location        LocationConfig        `yaml:"location"`
+	AdvertiserID    AdvertiserIDConfig    `yaml:"advertiser_id"`
+	Profile         ProfileConfig         `yaml:"profile"`
+	BrowsingHistory BrowsingHistoryConfig `yaml:"browsing_history"`
+	Bookmark        BookmarkConfig        `yaml:"bookmark"`
+	InstalledApp    InstalledAppConfig    `yaml:"installed_app"`
+	Interest        InterestConfig        `yaml:"interest"`
+}
+
+// DefaultConfig is used when refine config is absent. It keeps cookie and search history rules which were applied
+// before the rules became configurable and checks text fields of v2 data types.
+var DefaultConfig = Config{
+	Cookie: CookieConfig{
+		Value: TextConfig{MinLength: 3, SameRunesMinLength: 6},
+	},
+	SearchHistory: SearchHistoryConfig{
+		Query: TextConfig{MinLength: 3, SameRunesMinLength: 6},
+	},
+	BrowsingHistory: BrowsingHistoryConfig{
+		Title:       TextConfig{SameRunesMinLength: 6},
+		MinDuration: 1,
+	},
+	Bookmark: BookmarkConfig{
+		Title: TextConfig{SameRunesMinLength: 6},
+	},
+	InstalledApp: InstalledAppConfig{
+		Name: TextConfig{SameRunesMinLength: 6},
+	},
+	Interest: InterestConfig{
+		Category: TextConfig{MinLength: 3, SameRunesMinLength: 6},
+	},
+}
+
+// TextConfig is a configuration of text field rules, zero values disable rules.
+type TextConfig struct {
+	// MinLength is a minimal count of runes.
+	MinLength int `yaml:"min_length"`
+	// SameRunesMinLength rejects texts of at least this count of runes which consist of the same rune.
+	SameRunesMinLength int `yaml:"same_runes_min_length"`
+	// MinEntropy and MaxEntropy are bounds of Shannon entropy in bits per rune.
+	MinEntropy float64 `yaml:"min_entropy"`
+	MaxEntropy float64 `yaml:"max_entropy"`
+}
+
+// CookieConfig is a configuration of cookie rules.
+type CookieConfig struct {
+	Value TextConfig `yaml:"value"`
+	// TrackingNames are regular expressions of tracking cookies names.
+	TrackingNames []string `yaml:"tracking_names"`
+}
+
+// SearchHistoryConfig is a configuration of search history rules.
+type SearchHistoryConfig struct {
+	Query TextConfig `yaml:"query"`
+	// BlockedEngines are search engines which history isn't rewarded.
+	BlockedEngines []string `yaml:"blocked_engines"`
 }
 
-// SearchHistory refines the given search history item is not autogenerated.
-func SearchHistory(search *schema.V1SearchHistory) bool {
-	runeCount := utf8.RuneCount([]byte(search.Query))
+// LocationConfig is a configuration of location rules.
+type LocationConfig struct {
+	// RejectNullIsland rejects zero coordinates which are sent when location is unknown.
+	RejectNullIsland bool `yaml:"reject_null_island"`
+}
+
+// AdvertiserIDConfig is a configuration of advertiser id rules.
+type AdvertiserIDConfig struct {
+	Value TextConfig `yaml:"value"`
+	// BlockedValues are ids which don't identify the user, e.g. zero id sent when ad tracking is limited.
+	BlockedValues []string `yaml:"blocked_values"`
+}
+
+// ProfileConfig is a configuration of profile rules.
+type ProfileConfig struct {
+	FirstName TextConfig `yaml:"first_name"`
+	LastName  TextConfig `yaml:"last_name"`
+}
+
+// BrowsingHistoryConfig is a configuration of browsing history rules.
+type BrowsingHistoryConfig struct {
+	Title TextConfig `yaml:"title"`
+	// MinDuration is a minimal time spent on the page in seconds.
+	MinDuration uint64 `yaml:"min_duration"`
+}
+
+// BookmarkConfig is a configuration of bookmark rules.
+type BookmarkConfig struct {
+	Title TextConfig `yaml:"title"`
+}
+
+// InstalledAppConfig is a configuration of installed app rules.
+type InstalledAppConfig struct {
+	Name TextConfig `yaml:"name"`
+}
+
+// InterestConfig is a configuration of interest rules.
+type InterestConfig struct {
+	Category TextConfig `yaml:"category"`
+}
 
-	if runeCount < 3 {
-		return false
+// Load reads rules configuration from yaml file and creates the registry.
+func Load(path string) (Registry, error) {
+	c, err := loadConfig(path)
+	if err != nil {
+		return nil, err
 	}
 
-	if runeCount >= 6 && validateRunesSame(search.Query) {
-		return false
+	return New(*c)
+}
+
+func loadConfig(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read refine config: %w", err)
 	}
 
-	return true
+	var c Config
+	if err := yaml.UnmarshalStrict(b, &c); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal refine config: %w", err)
+	}
+
+	return &c, nil
 }
 
-func validateRunesSame(str string) bool {
-	var first rune
+// New creates the registry with rules enabled in the config.
+func New(c Config) (Registry, error) {
+	r := make(Registry)
 
-	for pos, char := range str {
-		if pos == 0 {
-			first = char
-		}
-		if first != char {
-			return false
+	if err := r.registerText(schema.PDVCookieType, "value", cookieValue, c.Cookie.Value); err != nil {
+		return nil, err
+	}
+	if len(c.Cookie.TrackingNames) > 0 {
+		patterns := make([]*regexp.Regexp, len(c.Cookie.TrackingNames))
+		for i, v := range c.Cookie.TrackingNames {
+			p, err := regexp.Compile(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tracking name %q: %w", v, err)
+			}
+			patterns[i] = p
 		}
+		r.Register(schema.PDVCookieType, patternRule{name: "tracking_cookie", field: cookieName, patterns: patterns})
+	}
+
+	if err := r.registerText(schema.PDVSearchHistoryType, "query", searchQuery, c.SearchHistory.Query); err != nil {
+		return nil, err
+	}
+	if len(c.SearchHistory.BlockedEngines) > 0 {
+		r.Register(schema.PDVSearchHistoryType, blocklistRule{name: "blocked_engine", field: searchEngine, values: c.SearchHistory.BlockedEngines})
+	}
+
+	if c.Location.RejectNullIsland {
+		r.Register(schema.PDVLocationType, nullIslandRule{})
+	}
+
+	if err := r.registerText(schema.PDVAdvertiserIDType, "value", advertiserIDValue, c.AdvertiserID.Value); err != nil {
+		return nil, err
+	}
+	if len(c.AdvertiserID.BlockedValues) > 0 {
+		r.Register(schema.PDVAdvertiserIDType, blocklistRule{name: "blocked_advertiser_id", field: advertiserIDValue, values: c.AdvertiserID.BlockedValues})
+	}
+
+	if err := r.registerText(schema.PDVProfileType, "first_name", profileFirstName, c.Profile.FirstName); err != nil {
+		return nil, err
+	}
+	if err := r.registerText(schema.PDVProfileType, "last_name", profileLastName, c.Profile.LastName); err != nil {
+		return nil, err
+	}
+
+	if err := r.registerText(schema.PDVBrowsingHistoryType, "title", browsingHistoryTitle, c.BrowsingHistory.Title); err != nil {
+		return nil, err
+	}
+	if c.BrowsingHistory.MinDuration > 0 {
+		r.Register(schema.PDVBrowsingHistoryType, minDurationRule{min: c.BrowsingHistory.MinDuration})
+	}
+
+	if err := r.registerText(schema.PDVBookmarkType, "title", bookmarkTitle, c.Bookmark.Title); err != nil {
+		return nil, err
+	}
+
+	if err := r.registerText(schema.PDVInstalledAppType, "name", installedAppName, c.InstalledApp.Name); err != nil {
+		return nil, err
+	}
+
+	if err := r.registerText(schema.PDVInterestType, "category", interestCategory, c.Interest.Category); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r Registry) registerText(t schema.Type, name string, f field, c TextConfig) error {
+	if c.MinLength < 0 || c.SameRunesMinLength < 0 || c.MinEntropy < 0 || c.MaxEntropy < 0 ||
+		(c.MaxEntropy > 0 && c.MaxEntropy < c.MinEntropy) {
+		return fmt.Errorf("invalid %s %s config %+v", t, name, c)
+	}
+
+	if c.MinLength > 0 {
+		r.Register(t, minLengthRule{name: name + "_min_length", field: f, min: c.MinLength})
+	}
+	if c.SameRunesMinLength > 0 {
+		r.Register(t, sameRunesRule{name: name + "_same_runes", field: f, minLength: c.SameRunesMinLength})
+	}
+	if c.MinEntropy > 0 || c.MaxEntropy > 0 {
+		r.Register(t, entropyRule{name: name + "_entropy", field: f, min: c.MinEntropy, max: c.MaxEntropy})
 	}
 
-	return true
+	return nil
 }
@@ -1,52 +1,223 @@
 package refine
 
 import (
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/Decentr-net/cerberus/pkg/schema"
+	"github.com/Decentr-net/cerberus/pkg/schema/types"
 )
 
+func TestLoad(t *testing.T) {
+	// default config file and config used when the file is absent are the same
+	c, err := loadConfig("../../configs/refine.yml")
+	require.NoError(t, err)
+	require.Equal(t, DefaultConfig, *c)
+
+	r, err := Load("../../configs/refine.yml")
+	require.NoError(t, err)
+
+	require.Len(t, r[schema.PDVCookieType], 2)
+	require.Len(t, r[schema.PDVSearchHistoryType], 2)
+	require.Len(t, r[schema.PDVLocationType], 0)
+	require.Len(t, r[schema.PDVAdvertiserIDType], 0)
+	require.Len(t, r[schema.PDVProfileType], 0)
+	require.Len(t, r[schema.PDVBrowsingHistoryType], 2)
+	require.Len(t, r[schema.PDVBookmarkType], 1)
+	require.Len(t, r[schema.PDVInstalledAppType], 1)
+	require.Len(t, r[schema.PDVInterestType], 2)
+}
+
+func TestLoad_Proposed(t *testing.T) {
+	r, err := Load("../../configs/refine.proposed.yml")
+	require.NoError(t, err)
+
+	require.Len(t, r[schema.PDVCookieType], 3)
+	require.Len(t, r[schema.PDVSearchHistoryType], 2)
+	require.Len(t, r[schema.PDVLocationType], 1)
+	require.Len(t, r[schema.PDVAdvertiserIDType], 2)
+	require.Len(t, r[schema.PDVProfileType], 2)
+	require.Len(t, r[schema.PDVBrowsingHistoryType], 2)
+	require.Len(t, r[schema.PDVBookmarkType], 1)
+	require.Len(t, r[schema.PDVInstalledAppType], 1)
+	require.Len(t, r[schema.PDVInterestType], 2)
+}
+
+func TestLoad_NotExist(t *testing.T) {
+	_, err := Load("../../configs/not_exist.yml")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestNew(t *testing.T) {
+	tt := []struct {
+		name   string
+		config Config
+		valid  bool
+	}{
+		{
+			name:  "empty",
+			valid: true,
+		},
+		{
+			name:   "negative min length",
+			config: Config{Cookie: CookieConfig{Value: TextConfig{MinLength: -1}}},
+		},
+		{
+			name:   "max entropy less than min",
+			config: Config{SearchHistory: SearchHistoryConfig{Query: TextConfig{MinEntropy: 2, MaxEntropy: 1}}},
+		},
+		{
+			name:   "invalid tracking name",
+			config: Config{Cookie: CookieConfig{TrackingNames: []string{"("}}},
+		},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New(tc.config)
+			if !tc.valid {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRegistry_Check_Nil(t *testing.T) {
+	var r Registry
+	require.Equal(t, "", r.Check(&schema.V1Cookie{Value: "a"}))
+}
+
 func TestCookie(t *testing.T) {
+	r, err := Load("../../configs/refine.yml")
+	require.NoError(t, err)
+
 	tt := []struct {
-		str   string
-		valid bool
+		str  string
+		rule string
 	}{
-		{"aaaa", true},
-		{"aaaaaa", false},
-		{"zzzzzzzzz", false},
-		{"123456", true},
-		{"1234567890", true},
-		{"русский", true},
-		{"ђђђђђђђђ", false},
+		{"aaaa", ""},
+		{"aaaaaa", "value_same_runes"},
+		{"zzzzzzzzz", "value_same_runes"},
+		{"123456", ""},
+		{"1234567890", ""},
+		{"русский", ""},
+		{"ђђђђђђђђ", "value_same_runes"},
+		{"ab", "value_min_length"},
 	}
 
 	for i := range tt {
 		tc := tt[i]
 		t.Run(tc.str, func(t *testing.T) {
-			require.Equal(t, tc.valid, Cookie(&schema.V1Cookie{Value: tc.str}))
+			require.Equal(t, tc.rule, r.Check(&schema.V1Cookie{Name: "session", Value: tc.str}))
 		})
 	}
+
+	t.Run("tracking", func(t *testing.T) {
+		// tracking cookies are rewarded by default
+		require.Equal(t, "", r.Check(&schema.V1Cookie{Name: "_ga", Value: "GA1.2.1234567890"}))
+
+		r, err := Load("../../configs/refine.proposed.yml")
+		require.NoError(t, err)
+
+		for _, v := range []string{"_ga", "_ga_ABC123", "_gid", "_fbp", "__utmz", "_gcl_au"} {
+			require.Equal(t, "tracking_cookie", r.Check(&schema.V1Cookie{Name: v, Value: "GA1.2.1234567890"}), v)
+		}
+		require.Equal(t, "", r.Check(&schema.V1Cookie{Name: "_gidx", Value: "GA1.2.1234567890"}))
+	})
 }
 
 func TestSearchHistory(t *testing.T) {
+	r, err := Load("../../configs/refine.yml")
+	require.NoError(t, err)
+
 	tt := []struct {
-		str   string
-		valid bool
+		str  string
+		rule string
 	}{
-		{"aaaa", true},
-		{"aaaaaa", false},
-		{"123456", true},
-		{"1234567890", true},
-		{"русский", true},
-		{"ђђђђђђђђ", false},
+		{"aaaa", ""},
+		{"aaaaaa", "query_same_runes"},
+		{"123456", ""},
+		{"1234567890", ""},
+		{"русский", ""},
+		{"ђђђђђђђђ", "query_same_runes"},
 	}
 
 	for i := range tt {
 		tc := tt[i]
 		t.Run(tc.str, func(t *testing.T) {
-			require.Equal(t, tc.valid, SearchHistory(&schema.V1SearchHistory{Query: tc.str}))
+			require.Equal(t, tc.rule, r.Check(&schema.V1SearchHistory{Query: tc.str}))
 		})
 	}
+
+	t.Run("blocked engine", func(t *testing.T) {
+		r, err := New(Config{SearchHistory: SearchHistoryConfig{BlockedEngines: []string{"bing"}}})
+		require.NoError(t, err)
+
+		require.Equal(t, "blocked_engine", r.Check(&schema.V1SearchHistory{Engine: "Bing", Query: "query"}))
+		require.Equal(t, "", r.Check(&schema.V1SearchHistory{Engine: "google", Query: "query"}))
+	})
+}
+
+func TestLocation(t *testing.T) {
+	r, err := Load("../../configs/refine.proposed.yml")
+	require.NoError(t, err)
+
+	require.Equal(t, "null_island", r.Check(&schema.V1Location{}))
+	require.Equal(t, "", r.Check(&schema.V1Location{Latitude: 0, Longitude: 10}))
+}
+
+func TestAdvertiserID(t *testing.T) {
+	r, err := Load("../../configs/refine.proposed.yml")
+	require.NoError(t, err)
+
+	require.Equal(t, "blocked_advertiser_id", r.Check(&schema.V1AdvertiserID{Value: "00000000-0000-0000-0000-000000000000"}))
+	require.Equal(t, "value_min_length", r.Check(&schema.V1AdvertiserID{Value: "1"}))
+	require.Equal(t, "", r.Check(&schema.V1AdvertiserID{Value: "38400000-8cf0-11bd-b23e-10b96e40000d"}))
+}
+
+func TestProfile(t *testing.T) {
+	r, err := Load("../../configs/refine.proposed.yml")
+	require.NoError(t, err)
+
+	require.Equal(t, "first_name_same_runes", r.Check(&schema.V1Profile{FirstName: "xxx", LastName: "Smith"}))
+	require.Equal(t, "last_name_same_runes", r.Check(&schema.V1Profile{FirstName: "John", LastName: "zzzz"}))
+	require.Equal(t, "", r.Check(&schema.V1Profile{FirstName: "John", LastName: "Smith", Gender: types.GenderMale}))
+}
+
+func TestV2(t *testing.T) {
+	r, err := Load("../../configs/refine.yml")
+	require.NoError(t, err)
+
+	require.Equal(t, "title_same_runes", r.Check(&schema.V2BrowsingHistory{Title: "xxxxxx", Duration: 10}))
+	require.Equal(t, "short_visit", r.Check(&schema.V2BrowsingHistory{Title: "Decentr"}))
+	require.Equal(t, "", r.Check(&schema.V2BrowsingHistory{Title: "Decentr", Duration: 10}))
+
+	require.Equal(t, "title_same_runes", r.Check(&schema.V2Bookmark{Title: "xxxxxx"}))
+	require.Equal(t, "", r.Check(&schema.V2Bookmark{Title: "Decentr"}))
+
+	require.Equal(t, "name_same_runes", r.Check(&schema.V2InstalledApp{Name: "xxxxxx"}))
+	require.Equal(t, "", r.Check(&schema.V2InstalledApp{Name: "Decentr"}))
+
+	require.Equal(t, "category_min_length", r.Check(&schema.V2Interest{Category: "ab"}))
+	require.Equal(t, "", r.Check(&schema.V2Interest{Category: "books"}))
+}
+
+func TestEntropy(t *testing.T) {
+	require.Equal(t, float64(0), entropy(""))
+	require.Equal(t, float64(0), entropy("aaaa"))
+	require.Equal(t, float64(1), entropy("abab"))
+	require.Equal(t, float64(2), entropy("abcd"))
+
+	r, err := New(Config{Cookie: CookieConfig{Value: TextConfig{MinEntropy: 1, MaxEntropy: 3}}})
+	require.NoError(t, err)
+
+	require.Equal(t, "value_entropy", r.Check(&schema.V1Cookie{Value: "aaab"}))
+	require.Equal(t, "", r.Check(&schema.V1Cookie{Value: "abcd"}))
+	require.Equal(t, "value_entropy", r.Check(&schema.V1Cookie{Value: "abcdefghijklmnop"}))
 }
@@ -0,0 +1,245 @@
+package refine
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Decentr-net/cerberus/pkg/schema"
+)
+
+// field returns the text of the data checked by the rule.
+type field func(d schema.Data) string
+
+func cookieValue(d schema.Data) string {
+	if v, ok := d.(*schema.V1Cookie); ok {
+		return v.Value
+	}
+	return ""
+}
+
+func cookieName(d schema.Data) string {
+	if v, ok := d.(*schema.V1Cookie); ok {
+		return v.Name
+	}
+	return ""
+}
+
+func searchQuery(d schema.Data) string {
+	if v, ok := d.(*schema.V1SearchHistory); ok {
+		return v.Query
+	}
+	return ""
+}
+
+func searchEngine(d schema.Data) string {
+	if v, ok := d.(*schema.V1SearchHistory); ok {
+		return v.Engine
+	}
+	return ""
+}
+
+func advertiserIDValue(d schema.Data) string {
+	if v, ok := d.(*schema.V1AdvertiserID); ok {
+		return v.Value
+	}
+	return ""
+}
+
+func profileFirstName(d schema.Data) string {
+	if v, ok := d.(*schema.V1Profile); ok {
+		return v.FirstName
+	}
+	return ""
+}
+
+func profileLastName(d schema.Data) string {
+	if v, ok := d.(*schema.V1Profile); ok {
+		return v.LastName
+	}
+	return ""
+}
+
+func browsingHistoryTitle(d schema.Data) string {
+	if v, ok := d.(*schema.V2BrowsingHistory); ok {
+		return v.Title
+	}
+	return ""
+}
+
+func bookmarkTitle(d schema.Data) string {
+	if v, ok := d.(*schema.V2Bookmark); ok {
+		return v.Title
+	}
+	return ""
+}
+
+func installedAppName(d schema.Data) string {
+	if v, ok := d.(*schema.V2InstalledApp); ok {
+		return v.Name
+	}
+	return ""
+}
+
+func interestCategory(d schema.Data) string {
+	if v, ok := d.(*schema.V2Interest); ok {
+		return v.Category
+	}
+	return ""
+}
+
+// minLengthRule rejects texts shorter than min runes.
+type minLengthRule struct {
+	name  string
+	field field
+	min   int
+}
+
+func (r minLengthRule) Name() string {
+	return r.name
+}
+
+func (r minLengthRule) Check(d schema.Data) bool {
+	return utf8.RuneCountInString(r.field(d)) >= r.min
+}
+
+// sameRunesRule rejects texts of at least minLength runes which consist of the same rune, e.g. "aaaaaa".
+type sameRunesRule struct {
+	name      string
+	field     field
+	minLength int
+}
+
+func (r sameRunesRule) Name() string {
+	return r.name
+}
+
+func (r sameRunesRule) Check(d schema.Data) bool {
+	s := r.field(d)
+
+	if utf8.RuneCountInString(s) < r.minLength {
+		return true
+	}
+
+	first, _ := utf8.DecodeRuneInString(s)
+	for _, v := range s {
+		if v != first {
+			return true
+		}
+	}
+
+	return false
+}
+
+// entropyRule rejects texts which Shannon entropy is out of bounds, max is ignored if it's zero.
+type entropyRule struct {
+	name     string
+	field    field
+	min, max float64
+}
+
+func (r entropyRule) Name() string {
+	return r.name
+}
+
+func (r entropyRule) Check(d schema.Data) bool {
+	e := entropy(r.field(d))
+
+	return e >= r.min && (r.max == 0 || e <= r.max)
+}
+
+// entropy returns Shannon entropy of the text in bits per rune.
+func entropy(s string) float64 {
+	var (
+		total  int
+		counts = make(map[rune]int)
+	)
+
+	for _, v := range s {
+		counts[v]++
+		total++
+	}
+
+	var e float64
+	for _, v := range counts {
+		p := float64(v) / float64(total)
+		e -= p * math.Log2(p)
+	}
+
+	return e
+}
+
+// patternRule rejects texts which match any of patterns.
+type patternRule struct {
+	name     string
+	field    field
+	patterns []*regexp.Regexp
+}
+
+func (r patternRule) Name() string {
+	return r.name
+}
+
+func (r patternRule) Check(d schema.Data) bool {
+	s := r.field(d)
+
+	for _, v := range r.patterns {
+		if v.MatchString(s) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// blocklistRule rejects texts which are equal to any of values ignoring case.
+type blocklistRule struct {
+	name   string
+	field  field
+	values []string
+}
+
+func (r blocklistRule) Name() string {
+	return r.name
+}
+
+func (r blocklistRule) Check(d schema.Data) bool {
+	s := r.field(d)
+
+	for _, v := range r.values {
+		if strings.EqualFold(v, s) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// nullIslandRule rejects zero coordinates.
+type nullIslandRule struct{}
+
+func (nullIslandRule) Name() string {
+	return "null_island"
+}
+
+func (nullIslandRule) Check(d schema.Data) bool {
+	v, ok := d.(*schema.V1Location)
+
+	return !ok || v.Latitude != 0 || v.Longitude != 0
+}
+
+// minDurationRule rejects visits which are shorter than min seconds.
+type minDurationRule struct {
+	min uint64
+}
+
+func (minDurationRule) Name() string {
+	return "short_visit"
+}
+
+func (r minDurationRule) Check(d schema.Data) bool {
+	v, ok := d.(*schema.V2BrowsingHistory)
+
+	return !ok || v.Duration >= r.min
+}
@@ -0,0 +1,524 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+
+	"github.com/Decentr-net/cerberus/internal/service"
+	"github.com/Decentr-net/go-api"
+)
+
+const (
+	bearerPrefix = "Bearer "
+
+	maxBanReasonLength = 1000
+)
+
+type adminActorKey struct{}
+
+// BannedProfile ...
+// swagger:model BannedProfile
+type BannedProfile struct {
+	Address string `json:"address"`
+	// Source is hades for automatic bans or manual for bans made by admins.
+	Source   string    `json:"source"`
+	Reason   string    `json:"reason"`
+	BannedAt time.Time `json:"banned_at"`
+}
+
+// BanRequest ...
+// swagger:model BanRequest
+type BanRequest struct {
+	Reason string `json:"reason"`
+}
+
+// BlacklistEntry ...
+// swagger:model BlacklistEntry
+type BlacklistEntry struct {
+	ID uint64 `json:"id"`
+	// Kind is cookie_source or search_engine.
+	Kind string `json:"kind"`
+	// Pattern without wildcards matches the domain and its subdomains, pattern with *, ? or [] wildcards is matched as a glob.
+	Pattern   string    `json:"pattern"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AddBlacklistEntryRequest ...
+// swagger:model AddBlacklistEntryRequest
+type AddBlacklistEntryRequest struct {
+	// Kind is cookie_source or search_engine.
+	Kind    string `json:"kind"`
+	Pattern string `json:"pattern"`
+}
+
+// AddBlacklistEntryResponse ...
+// swagger:model AddBlacklistEntryResponse
+type AddBlacklistEntryResponse struct {
+	ID uint64 `json:"id"`
+}
+
+// SetupAdminRouter setups admin handlers to chi router. Requests are authenticated with tokens,
+// the key of the tokens map is the admin name which is written into the audit.
+func SetupAdminRouter(s service.Service, r chi.Router, tokens map[string]string) {
+	srv := server{s: s}
+
+	r.Route("/v1/admin", func(r chi.Router) {
+		r.Use(adminAuthMiddleware(tokens))
+
+		r.Get("/bans", srv.listBansHandler)
+		r.Put("/bans/{owner}", srv.banHandler)
+		r.Delete("/bans/{owner}", srv.unbanHandler)
+		r.Get("/bans/{owner}/pdv", srv.listBanPDVHandler)
+
+		r.Get("/blacklist", srv.listBlacklistHandler)
+		r.Post("/blacklist", srv.addBlacklistEntryHandler)
+		r.Delete("/blacklist/{id}", srv.deleteBlacklistEntryHandler)
+	})
+}
+
+// adminAuthMiddleware puts name of the token owner into the request's context.
+func adminAuthMiddleware(tokens map[string]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := r.Header.Get("Authorization")
+			if strings.HasPrefix(h, bearerPrefix) {
+				token := []byte(strings.TrimPrefix(h, bearerPrefix))
+
+				for name, v := range tokens {
+					if v != "" && subtle.ConstantTimeCompare(token, []byte(v)) == 1 {
+						next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), adminActorKey{}, name)))
+						return
+					}
+				}
+			}
+
+			api.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		})
+	}
+}
+
+func getAdminActor(ctx context.Context) string {
+	actor, _ := ctx.Value(adminActorKey{}).(string)
+	return actor
+}
+
+func (s *server) listBansHandler(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /admin/bans Admin ListBans
+	//
+	// List banned profiles
+	//
+	// Returns banned profiles sorted by address.
+	//
+	// ---
+	// security:
+	// - admin_token: []
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: from
+	//   description: address to start from (exclusive)
+	//   in: query
+	//   type: string
+	// - name: limit
+	//   description: how many profiles will be returned
+	//   in: query
+	//   type: integer
+	//   format: uint16
+	//   maximum: 1000
+	// responses:
+	//   '200':
+	//     description: banned profiles
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/BannedProfile"
+	//   '400':
+	//     description: bad request
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '401':
+	//     description: invalid token
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	var err error
+
+	limit := defaultLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		if limit, err = strconv.ParseUint(s, 10, 16); err != nil || limit > 1000 {
+			api.WriteError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+	}
+
+	list, err := s.s.ListBannedProfiles(r.Context(), r.URL.Query().Get("from"), uint16(limit))
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to list banned profiles: %s", err.Error())
+		return
+	}
+
+	out := make([]BannedProfile, len(list))
+	for i, v := range list {
+		out[i] = BannedProfile{
+			Address:  v.Address,
+			Source:   v.Source,
+			Reason:   v.Reason,
+			BannedAt: v.BannedAt,
+		}
+	}
+
+	api.WriteOK(w, http.StatusOK, out)
+}
+
+func (s *server) banHandler(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PUT /admin/bans/{owner} Admin Ban
+	//
+	// Ban profile
+	//
+	// Bans the profile, the reason of already banned profile is updated.
+	//
+	// ---
+	// security:
+	// - admin_token: []
+	// consumes:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   description: account address
+	//   in: path
+	//   required: true
+	//   type: string
+	// - name: request
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/BanRequest"
+	// responses:
+	//   '204':
+	//     description: profile is banned
+	//   '400':
+	//     description: bad request
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '401':
+	//     description: invalid token
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	owner := chi.URLParam(r, "owner")
+	if !isOwnerValid(owner) {
+		api.WriteError(w, http.StatusBadRequest, "invalid owner")
+		return
+	}
+
+	var req BanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	if req.Reason == "" || len(req.Reason) > maxBanReasonLength {
+		api.WriteError(w, http.StatusBadRequest, "invalid reason")
+		return
+	}
+
+	if err := s.s.BanProfile(r.Context(), getAdminActor(r.Context()), owner, req.Reason); err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to ban profile: %s", err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *server) unbanHandler(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /admin/bans/{owner} Admin Unban
+	//
+	// Unban profile
+	//
+	// Unbans the profile.
+	//
+	// ---
+	// security:
+	// - admin_token: []
+	// parameters:
+	// - name: owner
+	//   description: account address
+	//   in: path
+	//   required: true
+	//   type: string
+	// - name: reason
+	//   description: reason of unban written into the audit
+	//   in: query
+	//   type: string
+	// responses:
+	//   '204':
+	//     description: profile is unbanned
+	//   '400':
+	//     description: bad request
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '401':
+	//     description: invalid token
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '404':
+	//     description: profile isn't banned
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	owner := chi.URLParam(r, "owner")
+	if !isOwnerValid(owner) {
+		api.WriteError(w, http.StatusBadRequest, "invalid owner")
+		return
+	}
+
+	reason := r.URL.Query().Get("reason")
+	if len(reason) > maxBanReasonLength {
+		api.WriteError(w, http.StatusBadRequest, "invalid reason")
+		return
+	}
+
+	if err := s.s.UnbanProfile(r.Context(), getAdminActor(r.Context()), owner, reason); err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			api.WriteError(w, http.StatusNotFound, "banned profile not found")
+			return
+		}
+		api.WriteInternalErrorf(r.Context(), w, "failed to unban profile: %s", err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *server) listBanPDVHandler(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /admin/bans/{owner}/pdv Admin ListBanPDV
+	//
+	// List ban PDV
+	//
+	// Returns ids of PDV which triggered the profile ban.
+	//
+	// ---
+	// security:
+	// - admin_token: []
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   description: account address
+	//   in: path
+	//   required: true
+	//   type: string
+	// responses:
+	//   '200':
+	//     description: ids of PDV
+	//     schema:
+	//       type: array
+	//       items:
+	//         type: integer
+	//   '400':
+	//     description: bad request
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '401':
+	//     description: invalid token
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	owner := chi.URLParam(r, "owner")
+	if !isOwnerValid(owner) {
+		api.WriteError(w, http.StatusBadRequest, "invalid owner")
+		return
+	}
+
+	ids, err := s.s.ListProfileBanPDV(r.Context(), owner)
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to list ban pdv: %s", err.Error())
+		return
+	}
+
+	api.WriteOK(w, http.StatusOK, ids)
+}
+
+func (s *server) listBlacklistHandler(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /admin/blacklist Admin ListBlacklist
+	//
+	// List blacklist
+	//
+	// Returns all blacklist entries.
+	//
+	// ---
+	// security:
+	// - admin_token: []
+	// produces:
+	// - application/json
+	// responses:
+	//   '200':
+	//     description: blacklist entries
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/BlacklistEntry"
+	//   '401':
+	//     description: invalid token
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	list, err := s.s.ListBlacklist(r.Context())
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to list blacklist: %s", err.Error())
+		return
+	}
+
+	out := make([]BlacklistEntry, len(list))
+	for i, v := range list {
+		out[i] = BlacklistEntry{
+			ID:        v.ID,
+			Kind:      v.Kind,
+			Pattern:   v.Pattern,
+			CreatedBy: v.CreatedBy,
+			CreatedAt: v.CreatedAt,
+		}
+	}
+
+	api.WriteOK(w, http.StatusOK, out)
+}
+
+func (s *server) addBlacklistEntryHandler(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /admin/blacklist Admin AddBlacklistEntry
+	//
+	// Add blacklist entry
+	//
+	// Adds the pattern to the blacklist. Other instances apply the change on the next blacklist refresh.
+	//
+	// ---
+	// security:
+	// - admin_token: []
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: request
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/AddBlacklistEntryRequest"
+	// responses:
+	//   '201':
+	//     description: entry is added
+	//     schema:
+	//       "$ref": "#/definitions/AddBlacklistEntryResponse"
+	//   '400':
+	//     description: bad request
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '401':
+	//     description: invalid token
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '409':
+	//     description: entry already exists
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	var req AddBlacklistEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	id, err := s.s.AddBlacklistEntry(r.Context(), getAdminActor(r.Context()), req.Kind, req.Pattern)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidBlacklistEntry):
+			api.WriteError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrAlreadyExists):
+			api.WriteError(w, http.StatusConflict, "entry already exists")
+		default:
+			api.WriteInternalErrorf(r.Context(), w, "failed to add blacklist entry: %s", err.Error())
+		}
+		return
+	}
+
+	api.WriteOK(w, http.StatusCreated, AddBlacklistEntryResponse{ID: id})
+}
+
+func (s *server) deleteBlacklistEntryHandler(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /admin/blacklist/{id} Admin DeleteBlacklistEntry
+	//
+	// Delete blacklist entry
+	//
+	// Deletes the entry from the blacklist. Other instances apply the change on the next blacklist refresh.
+	//
+	// ---
+	// security:
+	// - admin_token: []
+	// parameters:
+	// - name: id
+	//   description: entry id
+	//   in: path
+	//   required: true
+	//   type: integer
+	// responses:
+	//   '204':
+	//     description: entry is deleted
+	//   '400':
+	//     description: bad request
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '401':
+	//     description: invalid token
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '404':
+	//     description: entry doesn't exist
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if err := s.s.DeleteBlacklistEntry(r.Context(), getAdminActor(r.Context()), id); err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			api.WriteError(w, http.StatusNotFound, "entry not found")
+			return
+		}
+		api.WriteInternalErrorf(r.Context(), w, "failed to delete blacklist entry: %s", err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,265 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Decentr-net/cerberus/internal/entities"
+	"github.com/Decentr-net/cerberus/internal/service"
+	"github.com/Decentr-net/cerberus/internal/service/mock"
+)
+
+var adminTokens = map[string]string{"admin": "secret"}
+
+func TestAdminAuthMiddleware(t *testing.T) {
+	tt := []struct {
+		name   string
+		header string
+		code   int
+	}{
+		{name: "valid", header: "Bearer secret", code: http.StatusOK},
+		{name: "no header", header: "", code: http.StatusUnauthorized},
+		{name: "no prefix", header: "secret", code: http.StatusUnauthorized},
+		{name: "invalid", header: "Bearer invalid", code: http.StatusUnauthorized},
+		{name: "empty", header: "Bearer ", code: http.StatusUnauthorized},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.name, func(t *testing.T) {
+			h := adminAuthMiddleware(adminTokens)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "admin", getAdminActor(r.Context()))
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+			if tc.header != "" {
+				r.Header.Set("Authorization", tc.header)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+}
+
+func Test_listBansHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := mock.NewMockService(ctrl)
+	srv.EXPECT().ListBannedProfiles(gomock.Any(), "from", uint16(2)).Return([]*entities.BannedProfile{
+		{Address: testOwner, Source: "hades", Reason: "fraud detected", BannedAt: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}, nil)
+
+	router := chi.NewRouter()
+	SetupAdminRouter(srv, router, adminTokens)
+
+	r := httptest.NewRequest(http.MethodGet, "http://localhost/v1/admin/bans?from=from&limit=2", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[
+  {
+    "address": "decentr1u9slwz3sje8j94ccpwlslflg0506yc8y2ylmtz",
+    "source": "hades",
+    "reason": "fraud detected",
+    "banned_at": "2022-01-01T00:00:00Z"
+  }
+]`, w.Body.String())
+}
+
+func Test_banHandler(t *testing.T) {
+	tt := []struct {
+		name   string
+		owner  string
+		body   string
+		err    error
+		code   int
+		called bool
+	}{
+		{name: "success", owner: testOwner, body: `{"reason":"spam"}`, code: http.StatusNoContent, called: true},
+		{name: "error", owner: testOwner, body: `{"reason":"spam"}`, err: errors.New("test error"), code: http.StatusInternalServerError, called: true},
+		{name: "invalid owner", owner: "owner", body: `{"reason":"spam"}`, code: http.StatusBadRequest},
+		{name: "empty reason", owner: testOwner, body: `{}`, code: http.StatusBadRequest},
+		{name: "invalid body", owner: testOwner, body: `reason`, code: http.StatusBadRequest},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock.NewMockService(ctrl)
+			if tc.called {
+				srv.EXPECT().BanProfile(gomock.Any(), "admin", tc.owner, "spam").Return(tc.err)
+			}
+
+			router := chi.NewRouter()
+			SetupAdminRouter(srv, router, adminTokens)
+
+			r := httptest.NewRequest(http.MethodPut, "http://localhost/v1/admin/bans/"+tc.owner, strings.NewReader(tc.body))
+			r.Header.Set("Authorization", "Bearer secret")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+}
+
+func Test_unbanHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := mock.NewMockService(ctrl)
+	srv.EXPECT().UnbanProfile(gomock.Any(), "admin", testOwner, "appeal").Return(nil)
+
+	router := chi.NewRouter()
+	SetupAdminRouter(srv, router, adminTokens)
+
+	r := httptest.NewRequest(http.MethodDelete, "http://localhost/v1/admin/bans/"+testOwner+"?reason=appeal", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func Test_listBanPDVHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := mock.NewMockService(ctrl)
+	srv.EXPECT().ListProfileBanPDV(gomock.Any(), testOwner).Return([]uint64{2, 1}, nil)
+
+	router := chi.NewRouter()
+	SetupAdminRouter(srv, router, adminTokens)
+
+	r := httptest.NewRequest(http.MethodGet, "http://localhost/v1/admin/bans/"+testOwner+"/pdv", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[2, 1]`, w.Body.String())
+}
+
+func Test_listBlacklistHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := mock.NewMockService(ctrl)
+	srv.EXPECT().ListBlacklist(gomock.Any()).Return([]*entities.BlacklistEntry{
+		{ID: 1, Kind: "cookie_source", Pattern: "youtube.com", CreatedBy: "admin", CreatedAt: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}, nil)
+
+	router := chi.NewRouter()
+	SetupAdminRouter(srv, router, adminTokens)
+
+	r := httptest.NewRequest(http.MethodGet, "http://localhost/v1/admin/blacklist", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[
+  {
+    "id": 1,
+    "kind": "cookie_source",
+    "pattern": "youtube.com",
+    "created_by": "admin",
+    "created_at": "2022-01-01T00:00:00Z"
+  }
+]`, w.Body.String())
+}
+
+func Test_addBlacklistEntryHandler(t *testing.T) {
+	tt := []struct {
+		name   string
+		body   string
+		err    error
+		code   int
+		called bool
+	}{
+		{name: "success", body: `{"kind":"cookie_source","pattern":"youtube.com"}`, code: http.StatusCreated, called: true},
+		{name: "invalid entry", body: `{"kind":"cookie_source","pattern":"youtube.com"}`, err: service.ErrInvalidBlacklistEntry, code: http.StatusBadRequest, called: true},
+		{name: "already exists", body: `{"kind":"cookie_source","pattern":"youtube.com"}`, err: service.ErrAlreadyExists, code: http.StatusConflict, called: true},
+		{name: "invalid body", body: `kind`, code: http.StatusBadRequest},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock.NewMockService(ctrl)
+			if tc.called {
+				srv.EXPECT().AddBlacklistEntry(gomock.Any(), "admin", "cookie_source", "youtube.com").Return(uint64(1), tc.err)
+			}
+
+			router := chi.NewRouter()
+			SetupAdminRouter(srv, router, adminTokens)
+
+			r := httptest.NewRequest(http.MethodPost, "http://localhost/v1/admin/blacklist", strings.NewReader(tc.body))
+			r.Header.Set("Authorization", "Bearer secret")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.code, w.Code)
+			if tc.code == http.StatusCreated {
+				assert.JSONEq(t, `{"id":1}`, w.Body.String())
+			}
+		})
+	}
+}
+
+func Test_deleteBlacklistEntryHandler(t *testing.T) {
+	tt := []struct {
+		name   string
+		id     string
+		err    error
+		code   int
+		called bool
+	}{
+		{name: "success", id: "1", code: http.StatusNoContent, called: true},
+		{name: "not found", id: "1", err: service.ErrNotFound, code: http.StatusNotFound, called: true},
+		{name: "invalid id", id: "id", code: http.StatusBadRequest},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock.NewMockService(ctrl)
+			if tc.called {
+				srv.EXPECT().DeleteBlacklistEntry(gomock.Any(), "admin", uint64(1)).Return(tc.err)
+			}
+
+			router := chi.NewRouter()
+			SetupAdminRouter(srv, router, adminTokens)
+
+			r := httptest.NewRequest(http.MethodDelete, "http://localhost/v1/admin/blacklist/"+tc.id, nil)
+			r.Header.Set("Authorization", "Bearer secret")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+}
@@ -48,6 +48,35 @@ type PDVRewardDelta struct {
 	Pool  *PDVRewardsPool `json:"pool"`
 }
 
+// PDVRewardsHistoryItem ...
+// swagger:model PDVRewardsHistoryItem
+type PDVRewardsHistoryItem struct {
+	ID          uint64    `json:"id"`
+	Amount      sdk.Dec   `json:"amount"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	TxHash      string    `json:"tx_hash"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// RewardsConfig ...
+// swagger:model RewardsConfig
+type RewardsConfig struct {
+	// Current is the reward map version effective now.
+	Current RewardMapVersion `json:"current"`
+	// Upcoming are reward map versions which become effective later sorted by effective date.
+	Upcoming []RewardMapVersion `json:"upcoming"`
+}
+
+// RewardMapVersion ...
+// swagger:model RewardMapVersion
+type RewardMapVersion struct {
+	Version       uint64    `json:"version"`
+	EffectiveFrom time.Time `json:"effectiveFrom"`
+	// Rewards are rewards in DEC by pdv type.
+	Rewards map[schema.Type]sdk.Dec `json:"rewards"`
+}
+
 // saveImageHandler resizes and saves the given message into storage.
 func (s *server) saveImageHandler(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation POST /images Image Save
@@ -135,9 +164,9 @@ func (s *server) savePDVHandler(w http.ResponseWriter, r *http.Request) {
 	//     schema:
 	//       "$ref": "#/definitions/Error"
 	//   '400':
-	//      description: bad request
+	//      description: bad request, errors are set when pdv data is invalid
 	//      schema:
-	//        "$ref": "#/definitions/Error"
+	//        "$ref": "#/definitions/ValidationError"
 	//   '403':
 	//      description: profile is banned or fraud detected
 	//      schema:
@@ -146,6 +175,10 @@ func (s *server) savePDVHandler(w http.ResponseWriter, r *http.Request) {
 	//      description: internal server error
 	//      schema:
 	//        "$ref": "#/definitions/Error"
+	//   '503':
+	//      description: fraud check is unavailable
+	//      schema:
+	//        "$ref": "#/definitions/Error"
 
 	if err := api.Verify(r); err != nil {
 		api.WriteVerifyError(r.Context(), w, err)
@@ -166,9 +199,9 @@ func (s *server) savePDVHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !p.Validate() {
+	if errs := p.ValidateWithErrors(); len(errs) > 0 {
 		logging.GetLogger(r.Context()).WithField("body", string(data)).Debug("failed to validate pdv")
-		api.WriteError(w, http.StatusBadRequest, "pdv data is invalid")
+		api.WriteOK(w, http.StatusBadRequest, ValidationError{Error: "pdv data is invalid", Errors: errs})
 		return
 	}
 
@@ -205,6 +238,11 @@ func (s *server) savePDVHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, service.ErrFraudCheckUnavailable) {
+			api.WriteError(w, http.StatusServiceUnavailable, "fraud check unavailable")
+			return
+		}
+
 		api.WriteInternalErrorf(r.Context(), w, "failed to save pdv: %s", err.Error())
 		return
 	}
@@ -218,7 +256,9 @@ func (s *server) savePDVHandler(w http.ResponseWriter, r *http.Request) {
 func (s *server) validatePDVHandler(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation POST /pdv/validate PDV Validate
 	//
-	// Encrypts and saves PDV
+	// Validates PDV
+	//
+	// Duplicates are checked only if the request is signed.
 	//
 	// ---
 	// produces:
@@ -241,11 +281,31 @@ func (s *server) validatePDVHandler(w http.ResponseWriter, r *http.Request) {
 	//      description: bad request
 	//      schema:
 	//        "$ref": "#/definitions/Error"
+	//   '401':
+	//      description: signature is invalid
+	//      schema:
+	//        "$ref": "#/definitions/Error"
 	//   '500':
 	//      description: internal server error
 	//      schema:
 	//        "$ref": "#/definitions/Error"
 
+	var owner string
+	if r.Header.Get(api.PublicKeyHeader) != "" {
+		if err := api.Verify(r); err != nil {
+			api.WriteVerifyError(r.Context(), w, err)
+			return
+		}
+
+		address, err := api.GetAddressFromPubKey(r.Header.Get(api.PublicKeyHeader))
+		if err != nil {
+			api.WriteError(w, http.StatusBadRequest, fmt.Sprintf("failed to decode owner address: %s", err.Error()))
+			return
+		}
+
+		owner = address.String()
+	}
+
 	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		api.WriteError(w, http.StatusBadRequest, fmt.Sprintf("failed to read body: %s", err.Error()))
@@ -259,7 +319,24 @@ func (s *server) validatePDVHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	api.WriteOK(w, http.StatusOK, ValidatePDVResponse{Valid: len(invalidPDV) == 0, InvalidPDV: invalidPDV})
+	errs, err := schema.GetPDVErrors(data)
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, fmt.Sprintf("failed to validate pdv: %s", err.Error()))
+		return
+	}
+
+	resp := ValidatePDVResponse{Valid: len(invalidPDV) == 0, InvalidPDV: invalidPDV, Errors: errs}
+
+	var p schema.PDVWrapper
+	// duplicates are checked only when the whole batch could be decoded
+	if owner != "" && json.Unmarshal(data, &p) == nil {
+		if resp.DuplicatePDV, err = s.s.GetDuplicatePDV(r.Context(), owner, p); err != nil {
+			api.WriteInternalErrorf(r.Context(), w, "failed to get duplicate pdv: %s", err.Error())
+			return
+		}
+	}
+
+	api.WriteOK(w, http.StatusOK, resp)
 }
 
 // listPDVHandler lists pdv from storage.
@@ -579,42 +656,83 @@ func (s *server) getRewardsConfigHandler(w http.ResponseWriter, _ *http.Request)
 	//
 	// Get rewards config
 	//
-	// Returns rewards config.
+	// Returns the reward map version effective now and upcoming versions.
 	//
 	// ---
 	// responses:
 	//   '200':
 	//     description: rewards config
 	//     schema:
-	//       "$ref": "#/definitions/ObjectTypes"
+	//       "$ref": "#/definitions/RewardsConfig"
 	//   '500':
 	//     description: internal server error
 	//     schema:
 	//       "$ref": "#/definitions/Error"
 
-	api.WriteOK(w, http.StatusOK, s.s.GetRewardsMap())
+	current, upcoming := s.s.GetRewards()
+
+	out := RewardsConfig{
+		Current:  toRewardMapVersion(current),
+		Upcoming: make([]RewardMapVersion, len(upcoming)),
+	}
+	for i, v := range upcoming {
+		out.Upcoming[i] = toRewardMapVersion(v)
+	}
+
+	api.WriteOK(w, http.StatusOK, out)
+}
+
+func toRewardMapVersion(v service.RewardMapVersion) RewardMapVersion {
+	return RewardMapVersion{
+		Version:       v.Version,
+		EffectiveFrom: v.EffectiveFrom,
+		Rewards:       v.Rewards,
+	}
 }
 
 // getBlacklistHandler returns blacklist.
-func (s *server) getBlacklistHandler(w http.ResponseWriter, _ *http.Request) {
+func (s *server) getBlacklistHandler(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation GET /configs/blacklist Configs GetBlacklistConfig
 	//
 	// Get blacklist
 	//
-	// Returns blacklist.
+	// Returns blacklist with its version in ETag header. The blacklist isn't returned if If-None-Match header contains the version.
 	//
 	// ---
+	// parameters:
+	// - name: If-None-Match
+	//   description: ETag of the blacklist known by the client
+	//   in: header
+	//   type: string
 	// responses:
 	//   '200':
 	//     description: blacklist
+	//     headers:
+	//       ETag:
+	//         type: string
+	//         description: version of the blacklist
 	//     schema:
 	//       "$ref": "#/definitions/Blacklist"
+	//   '304':
+	//     description: blacklist isn't modified
 	//   '500':
 	//     description: internal server error
 	//     schema:
 	//       "$ref": "#/definitions/Error"
 
-	api.WriteOK(w, http.StatusOK, s.s.GetBlacklist())
+	b := s.s.GetBlacklist()
+	etag := fmt.Sprintf("%q", b.Version)
+
+	w.Header().Set("ETag", etag)
+
+	for _, v := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		if v = strings.TrimSpace(v); v == etag || v == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	api.WriteOK(w, http.StatusOK, b)
 }
 
 func (s *server) getPDVRewardsPool(w http.ResponseWriter, r *http.Request) {
@@ -683,6 +801,93 @@ func (s *server) getAccountPDVDelta(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (s *server) getAccountPDVRewards(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /accounts/{owner}/pdv-rewards PDVRewards PDVRewardsHistory
+	//
+	// Get PDV rewards history of the given account
+	//
+	// Returns distributed PDV rewards sorted by id in descending order. Amount is in uDEC.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   description: account address
+	//   in: path
+	//   required: true
+	//   type: string
+	// - name: from
+	//   description: id of reward to start from (exclusive)
+	//   in: query
+	//   type: integer
+	//   format: uint64
+	// - name: limit
+	//   description: how many rewards will be returned
+	//   in: query
+	//   type: integer
+	//   format: uint16
+	//   maximum: 1000
+	// responses:
+	//   '200':
+	//     description: rewards history
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/PDVRewardsHistoryItem"
+	//   '400':
+	//     description: bad request
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	owner := chi.URLParam(r, "owner")
+	if !isOwnerValid(owner) {
+		api.WriteError(w, http.StatusBadRequest, "invalid owner")
+		return
+	}
+
+	var err error
+
+	var from uint64
+	if s := r.URL.Query().Get("from"); s != "" {
+		if from, err = strconv.ParseUint(s, 10, 64); err != nil {
+			api.WriteError(w, http.StatusBadRequest, "invalid from")
+			return
+		}
+	}
+
+	limit := defaultLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		if limit, err = strconv.ParseUint(s, 10, 16); err != nil || limit > 1000 {
+			api.WriteError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+	}
+
+	list, err := s.s.ListPDVRewardsHistory(r.Context(), owner, from, uint16(limit))
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to list PDV rewards history: %s", err.Error())
+		return
+	}
+
+	out := make([]PDVRewardsHistoryItem, len(list))
+	for i, v := range list {
+		out[i] = PDVRewardsHistoryItem{
+			ID:          v.ID,
+			Amount:      sdk.NewDec(v.Amount),
+			PeriodStart: v.PeriodStart,
+			PeriodEnd:   v.PeriodEnd,
+			TxHash:      v.TxHash,
+			CreatedAt:   v.CreatedAt,
+		}
+	}
+
+	api.WriteOK(w, http.StatusOK, out)
+}
+
 func (s *server) preparePDVRewardsPool(ctx context.Context) (*PDVRewardsPool, error) {
 	total, err := s.s.GetPDVTotalDelta(ctx)
 	if err != nil {
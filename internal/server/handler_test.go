@@ -122,7 +122,23 @@ func TestServer_SavePDVHandler(t *testing.T) {
 			reqBody: []byte(`{"version": "v1"}`),
 			err:     errSkip,
 			rcode:   http.StatusBadRequest,
-			rdata:   `{"error":"pdv data is invalid"}`,
+			rdata:   `{"error":"pdv data is invalid","errors":[{"path":"pdv","code":"required","message":"is required"}]}`,
+			rlog:    "",
+		},
+		{
+			name:    "invalid pdv field",
+			reqBody: []byte(`{"version":"v1","pdv":[{"type":"advertiserId","advertiser":"decentr","name":"name"}]}`),
+			err:     errSkip,
+			rcode:   http.StatusBadRequest,
+			rdata:   `{"error":"pdv data is invalid","errors":[{"path":"pdv[0].value","code":"required","message":"is required"}]}`,
+			rlog:    "",
+		},
+		{
+			name:    "fraud check unavailable",
+			reqBody: pdv,
+			err:     service.ErrFraudCheckUnavailable,
+			rcode:   http.StatusServiceUnavailable,
+			rdata:   `{"error":"fraud check unavailable"}`,
 			rlog:    "",
 		},
 		{
@@ -214,6 +230,88 @@ func TestServerSavePDV_Throttler(t *testing.T) {
 	assert.Equal(t, http.StatusTooManyRequests, w.Code)
 }
 
+func TestServer_ValidatePDVHandler(t *testing.T) {
+	tt := []struct {
+		name         string
+		reqBody      []byte
+		unauthorized bool
+		duplicates   []int
+		err          error
+		rcode        int
+		rdata        string
+	}{
+		{
+			name:    "valid",
+			reqBody: pdv,
+			rcode:   http.StatusOK,
+			rdata:   `{"valid":true}`,
+		},
+		{
+			name:       "duplicates",
+			reqBody:    pdv,
+			duplicates: []int{1},
+			rcode:      http.StatusOK,
+			rdata:      `{"valid":true,"duplicatePDV":[1]}`,
+		},
+		{
+			name:         "unauthorized",
+			reqBody:      pdv,
+			unauthorized: true,
+			rcode:        http.StatusOK,
+			rdata:        `{"valid":true}`,
+		},
+		{
+			name:         "invalid",
+			reqBody:      []byte(`{"version":"v1","pdv":[{"type":"advertiserId","advertiser":"decentr","name":"name"},{"type":"unknown"}]}`),
+			unauthorized: true,
+			rcode:        http.StatusOK,
+			rdata: `{"valid":false,"invalidPDV":[0,1],"errors":[
+				{"path":"pdv[0].value","code":"required","message":"is required"},
+				{"path":"pdv[1]","code":"malformed","message":"failed to unmarshal PDV Data meta: unknown PDVType"}
+			]}`,
+		},
+		{
+			name:    "internal error",
+			reqBody: pdv,
+			err:     errors.New("test error"),
+			rcode:   http.StatusInternalServerError,
+			rdata:   `{"error":"internal error"}`,
+		},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, w, r := newTestParameters(t, http.MethodPost, "v1/pdv/validate", tc.reqBody)
+
+			if tc.unauthorized {
+				r.Header.Del(api.SignatureHeader)
+				r.Header.Del(api.PublicKeyHeader)
+			}
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock.NewMockService(ctrl)
+
+			if !tc.unauthorized {
+				srv.EXPECT().GetDuplicatePDV(gomock.Any(), testOwner, gomock.Any()).Return(tc.duplicates, tc.err)
+			}
+
+			router := chi.NewRouter()
+			s := server{s: srv}
+			router.Post("/v1/pdv/validate", s.validatePDVHandler)
+
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.rcode, w.Code)
+			assert.JSONEq(t, tc.rdata, w.Body.String())
+		})
+	}
+}
+
 func TestServer_ListPDVHandler(t *testing.T) {
 	tt := []struct {
 		name  string
@@ -450,10 +548,10 @@ func TestServer_GetPDVMeta(t *testing.T) {
 			owner: testOwner,
 			id:    "1",
 			f: func(_ context.Context, owner string, id uint64) (*entities.PDVMeta, error) {
-				return &entities.PDVMeta{ObjectTypes: map[schema.Type]uint16{schema.PDVCookieType: 1}, Reward: sdk.NewDec(2)}, nil
+				return &entities.PDVMeta{ObjectTypes: map[schema.Type]uint16{schema.PDVCookieType: 1}, Reward: sdk.NewDec(2), RewardMapVersion: 1}, nil
 			},
 			rcode: http.StatusOK,
-			rdata: `{"object_types":{"cookie": 1}, "reward": "2.000000000000000000"}`,
+			rdata: `{"object_types":{"cookie": 1}, "reward": "2.000000000000000000", "reward_map_version": 1}`,
 			rlog:  "",
 		},
 		{
@@ -693,9 +791,21 @@ func Test_getRewardsConfig(t *testing.T) {
 
 	srv := mock.NewMockService(ctrl)
 
-	srv.EXPECT().GetRewardsMap().Return(map[schema.Type]sdk.Dec{
-		"cookie":  sdk.NewDecWithPrec(1, 6),
-		"history": sdk.NewDecWithPrec(2, 6),
+	srv.EXPECT().GetRewards().Return(service.RewardMapVersion{
+		Version:       1,
+		EffectiveFrom: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		Rewards: map[schema.Type]sdk.Dec{
+			"cookie":  sdk.NewDecWithPrec(1, 6),
+			"history": sdk.NewDecWithPrec(2, 6),
+		},
+	}, []service.RewardMapVersion{
+		{
+			Version:       2,
+			EffectiveFrom: time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC),
+			Rewards: map[schema.Type]sdk.Dec{
+				"cookie": sdk.NewDecWithPrec(3, 6),
+			},
+		},
 	})
 
 	router := chi.NewRouter()
@@ -709,11 +819,81 @@ func Test_getRewardsConfig(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.JSONEq(t, `{
-		"cookie": "0.000001000000000000",
-		"history": "0.000002000000000000"
+		"current": {
+			"version": 1,
+			"effectiveFrom": "2022-01-01T00:00:00Z",
+			"rewards": {
+				"cookie": "0.000001000000000000",
+				"history": "0.000002000000000000"
+			}
+		},
+		"upcoming": [
+			{
+				"version": 2,
+				"effectiveFrom": "2022-02-01T00:00:00Z",
+				"rewards": {
+					"cookie": "0.000003000000000000"
+				}
+			}
+		]
 	}`, w.Body.String())
 }
 
+func Test_getBlacklistHandler(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name        string
+		ifNoneMatch string
+		code        int
+	}{
+		{name: "no etag", code: http.StatusOK},
+		{name: "old etag", ifNoneMatch: `"old"`, code: http.StatusOK},
+		{name: "actual etag", ifNoneMatch: `"version"`, code: http.StatusNotModified},
+		{name: "list", ifNoneMatch: `"old", "version"`, code: http.StatusNotModified},
+		{name: "any", ifNoneMatch: `*`, code: http.StatusNotModified},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock.NewMockService(ctrl)
+			srv.EXPECT().GetBlacklist().Return(service.Blacklist{
+				Version:      "version",
+				CookieSource: []string{"youtube.com"},
+				SearchEngine: []string{},
+			})
+
+			router := chi.NewRouter()
+
+			s := server{s: srv}
+			router.Get("/v1/configs/blacklist", s.getBlacklistHandler)
+
+			r := httptest.NewRequest(http.MethodGet, "http://localhost/v1/configs/blacklist", nil)
+			if tc.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tc.ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.code, w.Code)
+			assert.Equal(t, `"version"`, w.Header().Get("ETag"))
+			if tc.code == http.StatusOK {
+				assert.JSONEq(t, `{
+					"version": "version",
+					"cookieSource": ["youtube.com"],
+					"searchEngine": []
+				}`, w.Body.String())
+			} else {
+				assert.Empty(t, w.Body.String())
+			}
+		})
+	}
+}
+
 func Test_getPDVRewardsPool(t *testing.T) {
 	t.Parallel()
 
@@ -786,6 +966,42 @@ func Test_getAccountPDVDelta(t *testing.T) {
 }`, w.Body.String())
 }
 
+func Test_getAccountPDVRewards(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	start, end := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	srv := mock.NewMockService(ctrl)
+	srv.EXPECT().ListPDVRewardsHistory(gomock.Any(), testOwner, uint64(10), uint16(2)).Return([]*entities.PDVRewardsHistoryItem{
+		{ID: 2, Amount: 15, PeriodStart: start, PeriodEnd: end, TxHash: "tx", CreatedAt: end},
+	}, nil)
+
+	router := chi.NewRouter()
+
+	s := server{s: srv}
+
+	router.Get("/accounts/{owner}", s.getAccountPDVRewards)
+
+	r := httptest.NewRequest(http.MethodGet, "http://localhost/accounts/"+testOwner+"?from=10&limit=2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[
+  {
+    "id": 2,
+    "amount": "15.000000000000000000",
+    "period_start": "2022-01-01T00:00:00Z",
+    "period_end": "2022-02-01T00:00:00Z",
+    "tx_hash": "tx",
+    "created_at": "2022-02-01T00:00:00Z"
+  }
+]`, w.Body.String())
+}
+
 func Test_savePDVHander_Amount(t *testing.T) {
 	tt := []struct {
 		name  string
@@ -17,6 +17,11 @@
 //          name: Public-Key
 //          in: header
 //          description: Blockchain account's public key
+//     admin_token:
+//          type: apiKey
+//          name: Authorization
+//          in: header
+//          description: Admin token in `Bearer {token}` format
 //     signature:
 //          type: apiKey
 //          name: Signature
@@ -51,6 +56,7 @@ import (
 	_ "github.com/Decentr-net/cerberus/internal/server/swagger" // import models to be generated into swagger.json
 	"github.com/Decentr-net/cerberus/internal/service"
 	"github.com/Decentr-net/cerberus/internal/throttler"
+	"github.com/Decentr-net/cerberus/pkg/schema"
 	"github.com/Decentr-net/decentr/config"
 	"github.com/Decentr-net/go-api"
 )
@@ -100,6 +106,19 @@ type Profile struct {
 type ValidatePDVResponse struct {
 	Valid      bool  `json:"valid"`
 	InvalidPDV []int `json:"invalidPDV,omitempty"`
+	// Errors describe invalid fields of pdv.
+	Errors []schema.FieldError `json:"errors,omitempty"`
+	// DuplicatePDV are indices of cookies and search history which the owner has already sent, they aren't rewarded.
+	// It's returned only for signed requests.
+	DuplicatePDV []int `json:"duplicatePDV,omitempty"`
+}
+
+// ValidationError is returned when pdv data is invalid.
+// swagger:model ValidationError
+type ValidationError struct {
+	Error string `json:"error"`
+	// Errors describe invalid fields of pdv.
+	Errors []schema.FieldError `json:"errors"`
 }
 
 // SetupRouter setups handlers to chi router.
@@ -142,6 +161,7 @@ func SetupRouter(s service.Service, r chi.Router, timeout time.Duration, maxBody
 
 	r.Get("/v1/pdv-rewards/pool", srv.getPDVRewardsPool)
 	r.Get("/v1/accounts/{owner}/pdv-delta", srv.getAccountPDVDelta)
+	r.Get("/v1/accounts/{owner}/pdv-rewards", srv.getAccountPDVRewards)
 }
 
 func isOwnerValid(s string) bool {
@@ -149,6 +169,8 @@ func isOwnerValid(s string) bool {
 	return err == nil
 }
 
+// startPolling refreshes rewards pool every hour. The pool is a read-only cache which is served by every replica,
+// so the refresh isn't limited to the leader.
 func (s *server) startPolling() {
 	refresh := func() {
 		val, err := s.preparePDVRewardsPool(context.Background())
@@ -4,6 +4,7 @@ import (
 	"github.com/Decentr-net/cerberus/pkg/schema"
 	"github.com/Decentr-net/cerberus/pkg/schema/types"
 	v1 "github.com/Decentr-net/cerberus/pkg/schema/v1"
+	v2 "github.com/Decentr-net/cerberus/pkg/schema/v2"
 )
 
 // swagger:model PDV
@@ -75,12 +76,87 @@ type SearchHistoryV1 struct {
 	v1.SearchHistory
 }
 
+// PDVV2 is main data object, it contains v1 data types and new ones.
+// swagger:model v2
+type PDVV2 struct {
+	// swagger:allOf v2
+	PDVInterface
+
+	PDV []DataV2 `json:"pdv"`
+}
+
+// DataV2 is interface for all data types.
+// swagger:model DataV2
+type DataV2 interface {
+	// discriminator: true
+	// swagger:name type
+	TypeV2() types.Type
+}
+
+// BrowsingHistoryV2 contains visited page.
+// swagger:model browsingHistory
+type BrowsingHistoryV2 struct {
+	// swagger:allOf browsingHistory
+	DataV2
+
+	v2.BrowsingHistory
+}
+
+// BookmarkV2 contains user's bookmark.
+// swagger:model bookmark
+type BookmarkV2 struct {
+	// swagger:allOf bookmark
+	DataV2
+
+	v2.Bookmark
+}
+
+// InstalledAppV2 contains browser extension or app installed on user's device.
+// swagger:model installedApp
+type InstalledAppV2 struct {
+	// swagger:allOf installedApp
+	DataV2
+
+	v2.InstalledApp
+}
+
+// DeviceInfoV2 contains information about user's device.
+// swagger:model deviceInfo
+type DeviceInfoV2 struct {
+	// swagger:allOf deviceInfo
+	DataV2
+
+	v2.DeviceInfo
+}
+
+// InterestV2 contains purchase or interest signal.
+// swagger:model interest
+type InterestV2 struct {
+	// swagger:allOf interest
+	DataV2
+
+	v2.Interest
+}
+
 // PDVMeta contains info about PDV.
 // swagger:model PDVMeta
 type PDVMeta struct {
 	// ObjectTypes represents how much certain pdv data pdv contains.
 	ObjectTypes ObjectTypes `json:"object_types"`
 	Reward      uint64      `json:"reward"`
+	// RewardMapVersion is a version of the reward map used to calculate the reward.
+	RewardMapVersion uint64 `json:"reward_map_version"`
+	// Rejected contains data which isn't rewarded.
+	Rejected []RejectedPDV `json:"rejected,omitempty"`
+}
+
+// RejectedPDV is a data which isn't rewarded.
+// swagger:model RejectedPDV
+type RejectedPDV struct {
+	// Index is an index of the data in the batch.
+	Index int `json:"index"`
+	// Rule is a name of the rule which rejected the data, blacklist and duplicate are reported for blacklisted and already sent data.
+	Rule string `json:"rule"`
 }
 
 // ObjectTypes contains count of each pdv type in batch.
@@ -90,4 +166,9 @@ type ObjectTypes struct {
 	Location        uint16 `json:"location"`
 	Profile         uint16 `json:"profile"`
 	SearchHistoryV1 uint16 `json:"searchHistory"`
+	BrowsingHistory uint16 `json:"browsingHistory"`
+	Bookmark        uint16 `json:"bookmark"`
+	InstalledApp    uint16 `json:"installedApp"`
+	DeviceInfo      uint16 `json:"deviceInfo"`
+	Interest        uint16 `json:"interest"`
 }
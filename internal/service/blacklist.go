@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Decentr-net/cerberus/internal/entities"
+	"github.com/Decentr-net/cerberus/internal/storage"
+	"github.com/Decentr-net/cerberus/pkg/schema"
+)
+
+// admin audit actions of the blacklist
+const (
+	auditActionBlacklistAdd    = "blacklist_add"
+	auditActionBlacklistDelete = "blacklist_delete"
+)
+
+const maxBlacklistPatternLength = 255
+
+// globChars are special characters of path.Match patterns.
+const globChars = "*?["
+
+// Blacklist contains attributes of worthless pdv.
+// Pattern without wildcards matches the domain and its subdomains, pattern with *, ? or [] wildcards is matched as a glob.
+// swagger:model Blacklist
+type Blacklist struct {
+	// Version changes every time the content changes.
+	Version      string   `json:"version"`
+	CookieSource []string `json:"cookieSource"`
+	SearchEngine []string `json:"searchEngine"`
+}
+
+// BlacklistCache keeps the blacklist in memory, it's refreshed from the storage.
+type BlacklistCache struct {
+	is storage.IndexStorage
+
+	mu           sync.RWMutex
+	blacklist    Blacklist
+	cookieSource []string
+	searchEngine []string
+}
+
+// NewBlacklistCache creates a new instance of BlacklistCache. The cache is empty until it's refreshed.
+func NewBlacklistCache(is storage.IndexStorage) *BlacklistCache {
+	return &BlacklistCache{
+		is:        is,
+		blacklist: newBlacklist(nil, nil),
+	}
+}
+
+// Refresh loads the blacklist from the storage.
+func (c *BlacklistCache) Refresh(ctx context.Context) error {
+	entries, err := c.is.GetBlacklistEntryList(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get blacklist entry list: %w", err)
+	}
+
+	var cookieSource, searchEngine []string
+	for _, v := range entries {
+		switch v.Kind {
+		case storage.BlacklistKindCookieSource:
+			cookieSource = append(cookieSource, v.Pattern)
+		case storage.BlacklistKindSearchEngine:
+			searchEngine = append(searchEngine, v.Pattern)
+		default:
+			log.WithField("id", v.ID).WithField("kind", v.Kind).Warn("unknown blacklist entry kind")
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.blacklist = newBlacklist(cookieSource, searchEngine)
+	c.cookieSource = normalizePatterns(cookieSource)
+	c.searchEngine = normalizePatterns(searchEngine)
+
+	return nil
+}
+
+// RunAsync refreshes the blacklist in the background every interval until ctx is done.
+func (c *BlacklistCache) RunAsync(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if err := c.Refresh(ctx); err != nil {
+				log.WithError(err).Error("failed to refresh blacklist")
+			}
+		}
+	}()
+}
+
+// Get returns the blacklist.
+func (c *BlacklistCache) Get() Blacklist {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.blacklist
+}
+
+// IsCookieSourceBlacklisted returns true if the host matches any cookie source pattern.
+func (c *BlacklistCache) IsCookieSourceBlacklisted(host string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return matchAny(c.cookieSource, host)
+}
+
+// IsSearchEngineBlacklisted returns true if the engine matches any search engine pattern.
+func (c *BlacklistCache) IsSearchEngineBlacklisted(engine string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return matchAny(c.searchEngine, engine)
+}
+
+func newBlacklist(cookieSource, searchEngine []string) Blacklist {
+	b := Blacklist{
+		CookieSource: cookieSource,
+		SearchEngine: searchEngine,
+	}
+	if b.CookieSource == nil {
+		b.CookieSource = []string{}
+	}
+	if b.SearchEngine == nil {
+		b.SearchEngine = []string{}
+	}
+
+	data, _ := json.Marshal(b)
+	sum := sha256.Sum256(data)
+	b.Version = hex.EncodeToString(sum[:8])
+
+	return b
+}
+
+func normalizePatterns(patterns []string) []string {
+	out := make([]string, len(patterns))
+	for i, v := range patterns {
+		out[i] = strings.ToLower(v)
+	}
+
+	return out
+}
+
+// matchAny returns true if s matches any of patterns, patterns should be in lower case.
+func matchAny(patterns []string, s string) bool {
+	s = strings.ToLower(s)
+
+	for _, v := range patterns {
+		if strings.ContainsAny(v, globChars) {
+			if ok, _ := path.Match(v, s); ok {
+				return true
+			}
+			continue
+		}
+
+		if s == v || strings.HasSuffix(s, "."+v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isBlacklisted returns true if the data has blacklisted attributes.
+// Cookie source patterns are domains, so they are applied to visited and bookmarked pages too.
+func (s *service) isBlacklisted(d schema.Data) bool {
+	switch v := d.(type) {
+	case *schema.V1Cookie:
+		return s.blacklist.IsCookieSourceBlacklisted(v.Source.Host)
+	case *schema.V1SearchHistory:
+		return s.blacklist.IsSearchEngineBlacklisted(v.Engine)
+	case *schema.V2BrowsingHistory:
+		return s.blacklist.IsCookieSourceBlacklisted(urlHost(v.URL))
+	case *schema.V2Bookmark:
+		return s.blacklist.IsCookieSourceBlacklisted(urlHost(v.URL))
+	default:
+		return false
+	}
+}
+
+// urlHost returns host of the url without port, it returns empty string if the url is invalid.
+func urlHost(s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		return ""
+	}
+
+	return u.Hostname()
+}
+
+// GetBlacklist returns cached blacklist.
+func (s *service) GetBlacklist() Blacklist {
+	return s.blacklist.Get()
+}
+
+// ListBlacklist returns all blacklist entries.
+func (s *service) ListBlacklist(ctx context.Context) ([]*entities.BlacklistEntry, error) {
+	list, err := s.is.GetBlacklistEntryList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blacklist entry list: %w", err)
+	}
+
+	out := make([]*entities.BlacklistEntry, len(list))
+	for i, v := range list {
+		out[i] = &entities.BlacklistEntry{
+			ID:        v.ID,
+			Kind:      string(v.Kind),
+			Pattern:   v.Pattern,
+			CreatedBy: v.CreatedBy,
+			CreatedAt: v.CreatedAt,
+		}
+	}
+
+	return out, nil
+}
+
+// AddBlacklistEntry adds the pattern to the blacklist on behalf of the admin and returns id of the entry.
+func (s *service) AddBlacklistEntry(ctx context.Context, actor, kind, pattern string) (uint64, error) {
+	if err := validateBlacklistEntry(kind, pattern); err != nil {
+		return 0, err
+	}
+
+	var id uint64
+	if err := s.is.InTx(ctx, func(tx storage.IndexStorage) error {
+		var err error
+		if id, err = tx.CreateBlacklistEntry(ctx, &storage.BlacklistEntry{
+			Kind:      storage.BlacklistKind(kind),
+			Pattern:   pattern,
+			CreatedBy: actor,
+		}); err != nil {
+			if errors.Is(err, storage.ErrAlreadyExists) {
+				return ErrAlreadyExists
+			}
+			return fmt.Errorf("failed to create blacklist entry: %w", err)
+		}
+
+		if err := tx.CreateAdminAuditItem(ctx, &storage.AdminAuditItem{
+			Actor:  actor,
+			Action: auditActionBlacklistAdd,
+			Reason: fmt.Sprintf("%s %s", kind, pattern),
+		}); err != nil {
+			return fmt.Errorf("failed to create admin audit item: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	s.refreshBlacklistOrLog(ctx)
+
+	return id, nil
+}
+
+// DeleteBlacklistEntry deletes the entry from the blacklist on behalf of the admin.
+func (s *service) DeleteBlacklistEntry(ctx context.Context, actor string, id uint64) error {
+	if err := s.is.InTx(ctx, func(tx storage.IndexStorage) error {
+		e, err := tx.DeleteBlacklistEntry(ctx, id)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				return ErrNotFound
+			}
+			return fmt.Errorf("failed to delete blacklist entry: %w", err)
+		}
+
+		if err := tx.CreateAdminAuditItem(ctx, &storage.AdminAuditItem{
+			Actor:  actor,
+			Action: auditActionBlacklistDelete,
+			Reason: fmt.Sprintf("%s %s", e.Kind, e.Pattern),
+		}); err != nil {
+			return fmt.Errorf("failed to create admin audit item: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	s.refreshBlacklistOrLog(ctx)
+
+	return nil
+}
+
+// refreshBlacklistOrLog applies changes to the instance's cache immediately, other instances get them on the next refresh.
+func (s *service) refreshBlacklistOrLog(ctx context.Context) {
+	if err := s.blacklist.Refresh(ctx); err != nil {
+		log.WithError(err).Error("failed to refresh blacklist")
+	}
+}
+
+func validateBlacklistEntry(kind, pattern string) error {
+	switch storage.BlacklistKind(kind) {
+	case storage.BlacklistKindCookieSource, storage.BlacklistKindSearchEngine:
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidBlacklistEntry, kind)
+	}
+
+	if pattern == "" || strings.TrimSpace(pattern) != pattern || len(pattern) > maxBlacklistPatternLength {
+		return fmt.Errorf("%w: invalid pattern", ErrInvalidBlacklistEntry)
+	}
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("%w: invalid pattern: %s", ErrInvalidBlacklistEntry, err.Error())
+	}
+
+	return nil
+}
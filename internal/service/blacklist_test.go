@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Decentr-net/cerberus/internal/storage"
+	storagemock "github.com/Decentr-net/cerberus/internal/storage/mock"
+	"github.com/Decentr-net/cerberus/pkg/schema"
+)
+
+func TestMatchAny(t *testing.T) {
+	patterns := normalizePatterns([]string{"YouTube.com", "*.doubleclick.net", "ad?.example.org", "bing"})
+
+	tt := []struct {
+		s     string
+		match bool
+	}{
+		{"youtube.com", true},
+		{"m.YOUTUBE.com", true},
+		{"notyoutube.com", false},
+		{"youtube.com.ua", false},
+		{"ad.doubleclick.net", true},
+		{"a.b.doubleclick.net", true},
+		{"doubleclick.net", false},
+		{"ads.example.org", true},
+		{"adsx.example.org", false},
+		{"bing", true},
+		{"google", false},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.s, func(t *testing.T) {
+			require.Equal(t, tc.match, matchAny(patterns, tc.s))
+		})
+	}
+}
+
+func TestBlacklistCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := storagemock.NewMockIndexStorage(ctrl)
+	c := NewBlacklistCache(is)
+
+	empty := c.Get()
+	require.Equal(t, []string{}, empty.CookieSource)
+	require.Equal(t, []string{}, empty.SearchEngine)
+	require.NotEmpty(t, empty.Version)
+
+	entries := []*storage.BlacklistEntry{
+		{ID: 1, Kind: storage.BlacklistKindCookieSource, Pattern: "youtube.com"},
+		{ID: 2, Kind: storage.BlacklistKindSearchEngine, Pattern: "bing"},
+		{ID: 3, Kind: "unknown", Pattern: "pattern"},
+	}
+	is.EXPECT().GetBlacklistEntryList(ctx).Return(entries, nil).Times(2)
+
+	require.NoError(t, c.Refresh(ctx))
+	b := c.Get()
+	require.Equal(t, []string{"youtube.com"}, b.CookieSource)
+	require.Equal(t, []string{"bing"}, b.SearchEngine)
+	require.NotEqual(t, empty.Version, b.Version)
+
+	// version depends only on the content
+	require.NoError(t, c.Refresh(ctx))
+	require.Equal(t, b.Version, c.Get().Version)
+
+	require.True(t, c.IsCookieSourceBlacklisted("www.youtube.com"))
+	require.False(t, c.IsCookieSourceBlacklisted("bing"))
+	require.True(t, c.IsSearchEngineBlacklisted("Bing"))
+}
+
+func TestService_isBlacklisted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := storagemock.NewMockIndexStorage(ctrl)
+	is.EXPECT().GetBlacklistEntryList(ctx).Return([]*storage.BlacklistEntry{
+		{ID: 1, Kind: storage.BlacklistKindCookieSource, Pattern: "youtube.com"},
+		{ID: 2, Kind: storage.BlacklistKindSearchEngine, Pattern: "bing"},
+	}, nil)
+
+	c := NewBlacklistCache(is)
+	require.NoError(t, c.Refresh(ctx))
+
+	s := &service{blacklist: c}
+
+	require.True(t, s.isBlacklisted(&schema.V1Cookie{Source: schema.Source{Host: "m.youtube.com"}}))
+	require.False(t, s.isBlacklisted(&schema.V1Cookie{Source: schema.Source{Host: "decentr.net"}}))
+	require.True(t, s.isBlacklisted(&schema.V1SearchHistory{Engine: "bing"}))
+	require.False(t, s.isBlacklisted(&schema.V1SearchHistory{Engine: "google"}))
+	require.True(t, s.isBlacklisted(&schema.V2BrowsingHistory{URL: "https://m.youtube.com:443/watch"}))
+	require.False(t, s.isBlacklisted(&schema.V2BrowsingHistory{URL: "https://decentr.net"}))
+	require.True(t, s.isBlacklisted(&schema.V2Bookmark{URL: "https://youtube.com"}))
+	require.False(t, s.isBlacklisted(&schema.V1Location{}))
+}
+
+func TestService_AddBlacklistEntry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := storagemock.NewMockIndexStorage(ctrl)
+
+	s := New(nil, nil, is, nil, nil, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
+
+	is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.IndexStorage) error) error {
+		return f(is)
+	}).Times(2)
+	is.EXPECT().CreateBlacklistEntry(gomock.Any(), &storage.BlacklistEntry{
+		Kind:      storage.BlacklistKindCookieSource,
+		Pattern:   "*.youtube.com",
+		CreatedBy: "admin",
+	}).Return(uint64(1), nil)
+	is.EXPECT().CreateAdminAuditItem(gomock.Any(), &storage.AdminAuditItem{
+		Actor:  "admin",
+		Action: "blacklist_add",
+		Reason: "cookie_source *.youtube.com",
+	}).Return(nil)
+	is.EXPECT().GetBlacklistEntryList(gomock.Any()).Return([]*storage.BlacklistEntry{
+		{ID: 1, Kind: storage.BlacklistKindCookieSource, Pattern: "*.youtube.com"},
+	}, nil)
+
+	id, err := s.AddBlacklistEntry(ctx, "admin", "cookie_source", "*.youtube.com")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, id)
+	require.Equal(t, []string{"*.youtube.com"}, s.GetBlacklist().CookieSource)
+
+	is.EXPECT().CreateBlacklistEntry(gomock.Any(), gomock.Any()).Return(uint64(0), storage.ErrAlreadyExists)
+
+	_, err = s.AddBlacklistEntry(ctx, "admin", "cookie_source", "*.youtube.com")
+	require.ErrorIs(t, err, ErrAlreadyExists)
+
+	for _, v := range [][2]string{{"unknown", "youtube.com"}, {"cookie_source", ""}, {"cookie_source", " youtube.com"}, {"cookie_source", "[youtube.com"}} {
+		_, err = s.AddBlacklistEntry(ctx, "admin", v[0], v[1])
+		require.ErrorIs(t, err, ErrInvalidBlacklistEntry, v)
+	}
+}
+
+func TestService_DeleteBlacklistEntry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := storagemock.NewMockIndexStorage(ctrl)
+
+	s := New(nil, nil, is, nil, nil, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
+
+	is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.IndexStorage) error) error {
+		return f(is)
+	}).Times(2)
+	is.EXPECT().DeleteBlacklistEntry(gomock.Any(), uint64(1)).Return(&storage.BlacklistEntry{
+		ID: 1, Kind: storage.BlacklistKindSearchEngine, Pattern: "bing",
+	}, nil)
+	is.EXPECT().CreateAdminAuditItem(gomock.Any(), &storage.AdminAuditItem{
+		Actor:  "admin",
+		Action: "blacklist_delete",
+		Reason: "search_engine bing",
+	}).Return(nil)
+	is.EXPECT().GetBlacklistEntryList(gomock.Any()).Return(nil, nil)
+
+	require.NoError(t, s.DeleteBlacklistEntry(ctx, "admin", 1))
+
+	is.EXPECT().DeleteBlacklistEntry(gomock.Any(), uint64(2)).Return(nil, storage.ErrNotFound)
+
+	require.ErrorIs(t, s.DeleteBlacklistEntry(ctx, "admin", 2), ErrNotFound)
+}
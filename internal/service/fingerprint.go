@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Decentr-net/cerberus/internal/leader"
+	"github.com/Decentr-net/cerberus/internal/storage"
+	"github.com/Decentr-net/cerberus/pkg/schema"
+	logging "github.com/Decentr-net/logrus/context"
+)
+
+// fingerprint returns hash which identifies the data between batches.
+// Device info has the same fingerprint for every device, so it's rewarded once per retention window.
+// Location, advertiser id and profile don't have fingerprints.
+func fingerprint(d schema.Data) ([]byte, bool) {
+	var parts []string
+
+	switch v := d.(type) {
+	case *schema.V1Cookie:
+		parts = []string{string(v.Type()), v.Name, v.Domain, v.Value}
+	case *schema.V1SearchHistory:
+		parts = []string{string(v.Type()), v.Engine, v.Query}
+	case *schema.V2BrowsingHistory:
+		parts = []string{string(v.Type()), normalizeURL(v.URL)}
+	case *schema.V2Bookmark:
+		parts = []string{string(v.Type()), normalizeURL(v.URL)}
+	case *schema.V2InstalledApp:
+		parts = []string{string(v.Type()), string(v.Kind), v.ID}
+	case *schema.V2Interest:
+		parts = []string{string(v.Type()), string(v.Kind), strings.ToLower(v.Category)}
+	case *schema.V2DeviceInfo:
+		parts = []string{string(v.Type())}
+	default:
+		return nil, false
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return sum[:], true
+}
+
+// normalizeURL returns the url without fragment and with lower case scheme and host, so the same page has the same url.
+func normalizeURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		return s
+	}
+
+	u.Fragment = ""
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+
+	return u.String()
+}
+
+// fingerprintSet is a set of fingerprints which are already seen.
+type fingerprintSet map[string]struct{}
+
+// seen returns true if the data was seen before, the data is marked as seen.
+func (fs fingerprintSet) seen(d schema.Data) bool {
+	fp, ok := fingerprint(d)
+	if !ok {
+		return false
+	}
+
+	if _, ok := fs[string(fp)]; ok {
+		return true
+	}
+	fs[string(fp)] = struct{}{}
+
+	return false
+}
+
+// getSeenFingerprints returns fingerprints of the data sent by the owner during retention window.
+func (s *service) getSeenFingerprints(ctx context.Context, owner string, data []schema.Data) (fingerprintSet, error) {
+	fs := make(fingerprintSet)
+
+	if s.fingerprintRetention == 0 {
+		return fs, nil
+	}
+
+	var fingerprints [][]byte
+	for _, d := range data {
+		if fp, ok := fingerprint(d); ok {
+			fingerprints = append(fingerprints, fp)
+		}
+	}
+
+	if len(fingerprints) == 0 {
+		return fs, nil
+	}
+
+	list, err := s.is.GetPDVFingerprintList(ctx, owner, fingerprints, time.Now().UTC().Add(-s.fingerprintRetention))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pdv fingerprint list: %w", err)
+	}
+
+	for _, v := range list {
+		fs[string(v)] = struct{}{}
+	}
+
+	return fs, nil
+}
+
+// saveFingerprintsOrLog saves fingerprints of accepted pdv, so rejected pdv could be sent again.
+// Failure is only logged because pdv is already accepted.
+func (s *service) saveFingerprintsOrLog(ctx context.Context, owner string, data []schema.Data) {
+	if err := s.saveFingerprints(ctx, owner, data); err != nil {
+		logging.GetLogger(ctx).WithError(err).WithField("owner", owner).Error("failed to save fingerprints")
+	}
+}
+
+// saveFingerprints saves fingerprints of the data sent by the owner.
+func (s *service) saveFingerprints(ctx context.Context, owner string, data []schema.Data) error {
+	if s.fingerprintRetention == 0 {
+		return nil
+	}
+
+	var (
+		fingerprints [][]byte
+		fs           = make(fingerprintSet)
+	)
+
+	for _, d := range data {
+		if fs.seen(d) {
+			continue
+		}
+
+		if fp, ok := fingerprint(d); ok {
+			fingerprints = append(fingerprints, fp)
+		}
+	}
+
+	if len(fingerprints) == 0 {
+		return nil
+	}
+
+	if err := s.is.SetPDVFingerprints(ctx, owner, fingerprints); err != nil {
+		return fmt.Errorf("failed to set pdv fingerprints: %w", err)
+	}
+
+	return nil
+}
+
+// GetDuplicatePDV returns indices of data which were sent by the owner during retention window or repeated in the batch.
+func (s *service) GetDuplicatePDV(ctx context.Context, owner string, p schema.PDV) ([]int, error) {
+	fs, err := s.getSeenFingerprints(ctx, owner, p.Data())
+	if err != nil {
+		return nil, err
+	}
+
+	var out []int
+	for i, d := range p.Data() {
+		if fs.seen(d) {
+			out = append(out, i)
+		}
+	}
+
+	return out, nil
+}
+
+// FingerprintCleaner deletes fingerprints which are out of retention window.
+type FingerprintCleaner struct {
+	is        storage.IndexStorage
+	elector   leader.Elector
+	retention time.Duration
+}
+
+// NewFingerprintCleaner creates a new instance of FingerprintCleaner. Fingerprints are deleted only while the instance is the leader.
+func NewFingerprintCleaner(is storage.IndexStorage, elector leader.Elector, retention time.Duration) *FingerprintCleaner {
+	return &FingerprintCleaner{
+		is:        is,
+		elector:   elector,
+		retention: retention,
+	}
+}
+
+// Run deletes fingerprints which are out of retention window.
+func (c *FingerprintCleaner) Run(ctx context.Context) error {
+	return c.is.DeletePDVFingerprints(ctx, time.Now().UTC().Add(-c.retention))
+}
+
+// RunAsync runs cleanup in the background every interval until ctx is done.
+func (c *FingerprintCleaner) RunAsync(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if !c.elector.IsLeader() {
+				continue
+			}
+
+			if err := c.Run(ctx); err != nil {
+				log.WithError(err).Error("failed to delete pdv fingerprints")
+			}
+		}
+	}()
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	cryptomock "github.com/Decentr-net/cerberus/internal/crypto/mock"
+	"github.com/Decentr-net/cerberus/internal/entities"
+	hadesclient "github.com/Decentr-net/cerberus/internal/hades"
+	hadesmock "github.com/Decentr-net/cerberus/internal/hades/mock"
+	producermock "github.com/Decentr-net/cerberus/internal/producer/mock"
+	storagemock "github.com/Decentr-net/cerberus/internal/storage/mock"
+	"github.com/Decentr-net/cerberus/pkg/schema"
+	v1 "github.com/Decentr-net/cerberus/pkg/schema/v1"
+	v2 "github.com/Decentr-net/cerberus/pkg/schema/v2"
+)
+
+func TestFingerprint(t *testing.T) {
+	cookie := func(name, value, path string) schema.Data {
+		return &v1.Cookie{Name: name, Value: value, Domain: "decentr.net", Path: path}
+	}
+	search := func(engine, query string) schema.Data {
+		return &v1.SearchHistory{Engine: engine, Query: query, Domain: "google.com"}
+	}
+
+	fp := func(d schema.Data) []byte {
+		b, ok := fingerprint(d)
+		require.True(t, ok)
+		return b
+	}
+
+	require.Equal(t, fp(cookie("name", "value", "/")), fp(cookie("name", "value", "/path")))
+	require.NotEqual(t, fp(cookie("name", "value", "/")), fp(cookie("name", "value2", "/")))
+	require.Equal(t, fp(search("google", "query")), fp(search("google", "query")))
+	require.NotEqual(t, fp(search("google", "query")), fp(search("bing", "query")))
+
+	history := func(url string) schema.Data {
+		return &schema.V2BrowsingHistory{URL: url, Title: "title", VisitCount: 1}
+	}
+
+	require.Equal(t, fp(history("https://Decentr.net/path#top")), fp(history("https://decentr.net/path")))
+	require.NotEqual(t, fp(history("https://decentr.net/path")), fp(history("https://decentr.net/other")))
+	require.NotEqual(t, fp(history("https://decentr.net/path")), fp(&schema.V2Bookmark{URL: "https://decentr.net/path"}))
+	require.Equal(t,
+		fp(&schema.V2InstalledApp{Kind: v2.AppKindExtension, ID: "id", Version: "1.0"}),
+		fp(&schema.V2InstalledApp{Kind: v2.AppKindExtension, ID: "id", Version: "2.0"}),
+	)
+	require.Equal(t,
+		fp(&schema.V2Interest{Kind: v2.InterestKindPurchase, Category: "Books"}),
+		fp(&schema.V2Interest{Kind: v2.InterestKindPurchase, Category: "books"}),
+	)
+	// device info is rewarded once per retention window
+	require.Equal(t, fp(&schema.V2DeviceInfo{OS: "linux"}), fp(&schema.V2DeviceInfo{OS: "windows"}))
+
+	_, ok := fingerprint(&v1.Location{})
+	require.False(t, ok)
+}
+
+func TestService_SavePDV_Duplicates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fs := storagemock.NewMockFileStorage(ctrl)
+	is := storagemock.NewMockIndexStorage(ctrl)
+	cr := cryptomock.NewMockCrypto(ctrl)
+	p := producermock.NewMockProducer(ctrl)
+	hades := hadesmock.NewMockHades(ctrl)
+
+	s := New(ownerCrypto(ctrl, cr), fs, is, p, hades, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, time.Hour)
+
+	cookieFingerprint, _ := fingerprint(pdv[0])
+
+	expectWritePDV(t, cr, fs)
+	is.EXPECT().IsProfileBanned(gomock.Any(), testOwner).Return(false, nil)
+	is.EXPECT().GetPDVFingerprintList(ctx, testOwner, [][]byte{cookieFingerprint}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, fingerprints [][]byte, since time.Time) ([][]byte, error) {
+			require.WithinDuration(t, time.Now().Add(-time.Hour), since, time.Minute)
+			return fingerprints, nil
+		})
+	hades.EXPECT().AntiFraud(ctx, gomock.Any()).Return(&hadesclient.AntiFraudResponse{}, nil)
+	p.EXPECT().Produce(ctx, gomock.Any()).Return(nil)
+	is.EXPECT().SetPDVFingerprints(ctx, testOwner, [][]byte{cookieFingerprint}).Return(nil)
+
+	_, meta, err := s.SavePDV(ctx, schema.NewPDVWrapper(testDevice, pdv), testOwnerSdkAddr)
+	require.NoError(t, err)
+	// only location is rewarded
+	require.Equal(t, sdk.NewDecWithPrec(4, 6).String(), meta.Reward.String())
+	require.Equal(t, []entities.RejectedPDV{{Index: 0, Rule: RejectedDuplicate}}, meta.Rejected)
+}
+
+func TestService_GetDuplicatePDV(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := storagemock.NewMockIndexStorage(ctrl)
+
+	s := New(nil, nil, is, nil, nil, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, time.Hour)
+
+	seen := &v1.SearchHistory{Engine: "google", Query: "seen"}
+	repeated := &v1.SearchHistory{Engine: "google", Query: "repeated"}
+	seenFingerprint, _ := fingerprint(seen)
+
+	is.EXPECT().GetPDVFingerprintList(ctx, testOwner, gomock.Len(3), gomock.Any()).Return([][]byte{seenFingerprint}, nil)
+
+	ids, err := s.GetDuplicatePDV(ctx, testOwner, v1.PDV{seen, repeated, &v1.Location{}, repeated})
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 3}, ids)
+}
+
+func TestService_GetDuplicatePDV_Disabled(t *testing.T) {
+	s := New(nil, nil, nil, nil, nil, nil, rewards, nil, NewBlacklistCache(nil), pdvRewardsInterval, FraudCheckFailOpen, 0)
+
+	ids, err := s.GetDuplicatePDV(ctx, testOwner, pdv)
+	require.NoError(t, err)
+	require.Empty(t, ids)
+}
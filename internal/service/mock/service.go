@@ -71,6 +71,21 @@ func (mr *MockServiceMockRecorder) SavePDV(ctx, p, owner interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePDV", reflect.TypeOf((*MockService)(nil).SavePDV), ctx, p, owner)
 }
 
+// GetDuplicatePDV mocks base method
+func (m *MockService) GetDuplicatePDV(ctx context.Context, owner string, p schema.PDV) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDuplicatePDV", ctx, owner, p)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDuplicatePDV indicates an expected call of GetDuplicatePDV
+func (mr *MockServiceMockRecorder) GetDuplicatePDV(ctx, owner, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDuplicatePDV", reflect.TypeOf((*MockService)(nil).GetDuplicatePDV), ctx, owner, p)
+}
+
 // ListPDV mocks base method
 func (m *MockService) ListPDV(ctx context.Context, owner string, from uint64, limit uint16) ([]uint64, error) {
 	m.ctrl.T.Helper()
@@ -131,18 +146,19 @@ func (mr *MockServiceMockRecorder) GetProfiles(ctx, owner interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfiles", reflect.TypeOf((*MockService)(nil).GetProfiles), ctx, owner)
 }
 
-// GetRewardsMap mocks base method
-func (m *MockService) GetRewardsMap() service.RewardMap {
+// GetRewards mocks base method
+func (m *MockService) GetRewards() (service.RewardMapVersion, []service.RewardMapVersion) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRewardsMap")
-	ret0, _ := ret[0].(service.RewardMap)
-	return ret0
+	ret := m.ctrl.Call(m, "GetRewards")
+	ret0, _ := ret[0].(service.RewardMapVersion)
+	ret1, _ := ret[1].([]service.RewardMapVersion)
+	return ret0, ret1
 }
 
-// GetRewardsMap indicates an expected call of GetRewardsMap
-func (mr *MockServiceMockRecorder) GetRewardsMap() *gomock.Call {
+// GetRewards indicates an expected call of GetRewards
+func (mr *MockServiceMockRecorder) GetRewards() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRewardsMap", reflect.TypeOf((*MockService)(nil).GetRewardsMap))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRewards", reflect.TypeOf((*MockService)(nil).GetRewards))
 }
 
 // GetBlacklist mocks base method
@@ -159,6 +175,50 @@ func (mr *MockServiceMockRecorder) GetBlacklist() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlacklist", reflect.TypeOf((*MockService)(nil).GetBlacklist))
 }
 
+// ListBlacklist mocks base method
+func (m *MockService) ListBlacklist(ctx context.Context) ([]*entities.BlacklistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBlacklist", ctx)
+	ret0, _ := ret[0].([]*entities.BlacklistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBlacklist indicates an expected call of ListBlacklist
+func (mr *MockServiceMockRecorder) ListBlacklist(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBlacklist", reflect.TypeOf((*MockService)(nil).ListBlacklist), ctx)
+}
+
+// AddBlacklistEntry mocks base method
+func (m *MockService) AddBlacklistEntry(ctx context.Context, actor, kind, pattern string) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBlacklistEntry", ctx, actor, kind, pattern)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddBlacklistEntry indicates an expected call of AddBlacklistEntry
+func (mr *MockServiceMockRecorder) AddBlacklistEntry(ctx, actor, kind, pattern interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBlacklistEntry", reflect.TypeOf((*MockService)(nil).AddBlacklistEntry), ctx, actor, kind, pattern)
+}
+
+// DeleteBlacklistEntry mocks base method
+func (m *MockService) DeleteBlacklistEntry(ctx context.Context, actor string, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBlacklistEntry", ctx, actor, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBlacklistEntry indicates an expected call of DeleteBlacklistEntry
+func (mr *MockServiceMockRecorder) DeleteBlacklistEntry(ctx, actor, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBlacklistEntry", reflect.TypeOf((*MockService)(nil).DeleteBlacklistEntry), ctx, actor, id)
+}
+
 // GetPDVDelta mocks base method
 func (m *MockService) GetPDVDelta(ctx context.Context, owner string) (types.Dec, error) {
 	m.ctrl.T.Helper()
@@ -203,3 +263,76 @@ func (mr *MockServiceMockRecorder) GetPDVRewardsNextDistributionDate(ctx interfa
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPDVRewardsNextDistributionDate", reflect.TypeOf((*MockService)(nil).GetPDVRewardsNextDistributionDate), ctx)
 }
+
+// ListPDVRewardsHistory mocks base method
+func (m *MockService) ListPDVRewardsHistory(ctx context.Context, owner string, from uint64, limit uint16) ([]*entities.PDVRewardsHistoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPDVRewardsHistory", ctx, owner, from, limit)
+	ret0, _ := ret[0].([]*entities.PDVRewardsHistoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPDVRewardsHistory indicates an expected call of ListPDVRewardsHistory
+func (mr *MockServiceMockRecorder) ListPDVRewardsHistory(ctx, owner, from, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPDVRewardsHistory", reflect.TypeOf((*MockService)(nil).ListPDVRewardsHistory), ctx, owner, from, limit)
+}
+
+// ListBannedProfiles mocks base method
+func (m *MockService) ListBannedProfiles(ctx context.Context, from string, limit uint16) ([]*entities.BannedProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBannedProfiles", ctx, from, limit)
+	ret0, _ := ret[0].([]*entities.BannedProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBannedProfiles indicates an expected call of ListBannedProfiles
+func (mr *MockServiceMockRecorder) ListBannedProfiles(ctx, from, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBannedProfiles", reflect.TypeOf((*MockService)(nil).ListBannedProfiles), ctx, from, limit)
+}
+
+// BanProfile mocks base method
+func (m *MockService) BanProfile(ctx context.Context, actor, owner, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BanProfile", ctx, actor, owner, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BanProfile indicates an expected call of BanProfile
+func (mr *MockServiceMockRecorder) BanProfile(ctx, actor, owner, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BanProfile", reflect.TypeOf((*MockService)(nil).BanProfile), ctx, actor, owner, reason)
+}
+
+// UnbanProfile mocks base method
+func (m *MockService) UnbanProfile(ctx context.Context, actor, owner, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnbanProfile", ctx, actor, owner, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnbanProfile indicates an expected call of UnbanProfile
+func (mr *MockServiceMockRecorder) UnbanProfile(ctx, actor, owner, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnbanProfile", reflect.TypeOf((*MockService)(nil).UnbanProfile), ctx, actor, owner, reason)
+}
+
+// ListProfileBanPDV mocks base method
+func (m *MockService) ListProfileBanPDV(ctx context.Context, owner string) ([]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProfileBanPDV", ctx, owner)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProfileBanPDV indicates an expected call of ListProfileBanPDV
+func (mr *MockServiceMockRecorder) ListProfileBanPDV(ctx, owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProfileBanPDV", reflect.TypeOf((*MockService)(nil).ListProfileBanPDV), ctx, owner)
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Decentr-net/cerberus/internal/crypto"
+	"github.com/Decentr-net/cerberus/internal/hades"
+	"github.com/Decentr-net/cerberus/internal/leader"
+	"github.com/Decentr-net/cerberus/internal/producer"
+	"github.com/Decentr-net/cerberus/internal/storage"
+	"github.com/Decentr-net/cerberus/pkg/schema"
+)
+
+const (
+	recheckBatchSize = 100
+	// pdv is marked as failed after maxRecheckAttempts failed rechecks, it should be checked manually
+	maxRecheckAttempts = 10
+	// delay before the next recheck is doubled after each failed recheck up to maxRecheckBackoff
+	recheckBackoff    = time.Minute
+	maxRecheckBackoff = 6 * time.Hour
+)
+
+// FraudRechecker checks PDV which skipped antifraud check because Hades failed.
+type FraudRechecker struct {
+	c       crypto.OwnerCrypto
+	fs      storage.FileStorage
+	is      storage.IndexStorage
+	p       producer.Producer
+	hades   hades.Hades
+	elector leader.Elector
+}
+
+// NewFraudRechecker creates a new instance of FraudRechecker. PDV are rechecked only while the instance is the leader.
+func NewFraudRechecker(
+	c crypto.OwnerCrypto,
+	fs storage.FileStorage,
+	is storage.IndexStorage,
+	p producer.Producer,
+	hades hades.Hades,
+	elector leader.Elector,
+) *FraudRechecker {
+	return &FraudRechecker{
+		c:       c,
+		fs:      fs,
+		is:      is,
+		p:       p,
+		hades:   hades,
+		elector: elector,
+	}
+}
+
+// Run rechecks the oldest due PDV. Fraud PDV owners are banned, held PDV without fraud are produced.
+// Failed recheck is retried with backoff, PDV is marked as failed after maxRecheckAttempts.
+// It returns count of rechecked PDV.
+func (r *FraudRechecker) Run(ctx context.Context) (int, error) {
+	items, err := r.is.GetFraudRecheckItemList(ctx, recheckBatchSize, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get fraud recheck item list: %w", err)
+	}
+
+	var count int
+	for _, v := range items {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+
+		if err := r.recheck(ctx, v); err != nil {
+			// pdv isn't blamed for Hades outage, the rest are rechecked when circuit is closed
+			if errors.Is(err, hades.ErrCircuitOpen) {
+				log.WithError(err).Warn("stop recheck")
+				return count, nil
+			}
+
+			log.WithError(err).WithField("owner", v.Address).WithField("id", v.ID).Error("failed to recheck pdv")
+
+			if err := r.postpone(ctx, v, err); err != nil {
+				return count, err
+			}
+			continue
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// postpone schedules the next recheck of the item or marks it as failed if attempts are exhausted.
+func (r *FraudRechecker) postpone(ctx context.Context, item *storage.FraudRecheckItem, reason error) error {
+	if item.Attempts+1 >= maxRecheckAttempts {
+		log.WithField("owner", item.Address).WithField("id", item.ID).
+			Error("pdv recheck attempts are exhausted, it should be checked manually")
+
+		if err := r.is.FailFraudRecheckItem(ctx, item.Address, item.ID, reason.Error()); err != nil {
+			return fmt.Errorf("failed to mark fraud recheck item as failed: %w", err)
+		}
+		return nil
+	}
+
+	if err := r.is.PostponeFraudRecheckItem(
+		ctx, item.Address, item.ID, time.Now().UTC().Add(nextRecheckDelay(item.Attempts)), reason.Error(),
+	); err != nil {
+		return fmt.Errorf("failed to postpone fraud recheck item: %w", err)
+	}
+
+	return nil
+}
+
+// nextRecheckDelay returns delay before the next recheck after attempts failed rechecks.
+func nextRecheckDelay(attempts int) time.Duration {
+	delay := recheckBackoff
+	for i := 0; i < attempts && delay < maxRecheckBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > maxRecheckBackoff {
+		return maxRecheckBackoff
+	}
+
+	return delay
+}
+
+// RunAsync runs recheck in the background every interval until ctx is done.
+func (r *FraudRechecker) RunAsync(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if !r.elector.IsLeader() {
+				continue
+			}
+
+			count, err := r.Run(ctx)
+			if err != nil {
+				log.WithError(err).Error("failed to recheck pdv")
+			}
+			if count > 0 {
+				log.Infof("%d pdv rechecked", count)
+			}
+		}
+	}()
+}
+
+func (r *FraudRechecker) recheck(ctx context.Context, item *storage.FraudRecheckItem) error {
+	var msg producer.PDVMessage
+	if err := json.Unmarshal(item.Message, &msg); err != nil {
+		return fmt.Errorf("failed to unmarshal pdv message: %w", err)
+	}
+
+	pdv, err := r.decrypt(ctx, &msg)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.hades.AntiFraud(ctx, &hades.AntiFraudRequest{
+		ID:      msg.ID,
+		Address: msg.Address,
+		Data:    *pdv,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to anti fraud: %w", err)
+	}
+
+	switch {
+	case resp.IsFraud:
+		log.WithField("owner", msg.Address).WithField("id", msg.ID).WithField("rule", resp.Rule).Warn("fraud detected on recheck")
+
+		if err := banForFraud(ctx, r.is, msg.Address, msg.ID); err != nil {
+			return fmt.Errorf("failed to ban: %w", err)
+		}
+
+		r.deletePDV(ctx, msg.Address, msg.ID)
+	case item.Held:
+		banned, err := r.is.IsProfileBanned(ctx, msg.Address)
+		if err != nil {
+			return fmt.Errorf("failed to check if profile banned: %w", err)
+		}
+
+		// profile could be banned while pdv was held, its data is dropped then
+		if banned {
+			r.deletePDV(ctx, msg.Address, msg.ID)
+			break
+		}
+
+		if err := r.p.Produce(ctx, &msg); err != nil {
+			return fmt.Errorf("failed to produce pdv message: %w", err)
+		}
+	}
+
+	return r.is.DeleteFraudRecheckItem(ctx, item.Address, item.ID)
+}
+
+// deletePDV deletes pdv data written into file storage. The error is only logged, since pdv is already rechecked.
+func (r *FraudRechecker) deletePDV(ctx context.Context, owner string, id uint64) {
+	if err := r.fs.Delete(ctx, getPDVFilePath(owner, id)); err != nil {
+		log.WithError(err).WithField("owner", owner).WithField("id", id).Error("failed to delete pdv")
+	}
+}
+
+func (r *FraudRechecker) decrypt(ctx context.Context, msg *producer.PDVMessage) (*schema.PDVWrapper, error) {
+	c, err := r.c.ForOwnerRead(ctx, msg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get owner's crypto: %w", err)
+	}
+
+	var src io.Reader = bytes.NewReader(msg.Data)
+	if len(msg.Data) == 0 {
+		f, err := r.fs.Read(ctx, getPDVFilePath(msg.Address, msg.ID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get data from storage: %w", err)
+		}
+		defer f.Close() // nolint
+
+		src = f
+	}
+
+	dr, err := c.Decrypt(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create decrypting reader: %w", err)
+	}
+
+	data, err := ioutil.ReadAll(dr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+
+	var pdv schema.PDVWrapper
+	if err := json.Unmarshal(data, &pdv); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pdv: %w", err)
+	}
+
+	return &pdv, nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	cryptomock "github.com/Decentr-net/cerberus/internal/crypto/mock"
+	hadesclient "github.com/Decentr-net/cerberus/internal/hades"
+	hadesmock "github.com/Decentr-net/cerberus/internal/hades/mock"
+	leadermock "github.com/Decentr-net/cerberus/internal/leader/mock"
+	"github.com/Decentr-net/cerberus/internal/producer"
+	producermock "github.com/Decentr-net/cerberus/internal/producer/mock"
+	"github.com/Decentr-net/cerberus/internal/storage"
+	storagemock "github.com/Decentr-net/cerberus/internal/storage/mock"
+	"github.com/Decentr-net/cerberus/pkg/schema"
+)
+
+func TestFraudRechecker_Run(t *testing.T) {
+	tt := []struct {
+		name    string
+		held    bool
+		fraud   bool
+		banned  bool
+		produce bool
+	}{
+		{name: "held", held: true, produce: true},
+		{name: "held banned", held: true, banned: true},
+		{name: "held fraud", held: true, fraud: true},
+		{name: "not held", held: false},
+		{name: "not held fraud", held: false, fraud: true},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fs := storagemock.NewMockFileStorage(ctrl)
+			is := storagemock.NewMockIndexStorage(ctrl)
+			cr := cryptomock.NewMockCrypto(ctrl)
+			p := producermock.NewMockProducer(ctrl)
+			hades := hadesmock.NewMockHades(ctrl)
+
+			r := NewFraudRechecker(ownerCrypto(ctrl, cr), fs, is, p, hades, leadermock.NewMockElector(ctrl))
+
+			msg := &producer.PDVMessage{ID: 1, Address: testOwner, Device: testDevice}
+			b, err := json.Marshal(msg)
+			require.NoError(t, err)
+
+			plain, err := json.Marshal(schema.NewPDVWrapper(testDevice, pdv))
+			require.NoError(t, err)
+
+			is.EXPECT().GetFraudRecheckItemList(gomock.Any(), uint16(recheckBatchSize), gomock.Any()).Return([]*storage.FraudRecheckItem{
+				{Address: testOwner, ID: 1, Held: tc.held, Message: b},
+			}, nil)
+			fs.EXPECT().Read(gomock.Any(), getPDVFilePath(testOwner, 1)).Return(ioutil.NopCloser(bytes.NewReader(testEncryptedData)), nil)
+			cr.EXPECT().Decrypt(gomock.Any()).Return(bytes.NewReader(plain), nil)
+			hades.EXPECT().AntiFraud(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req *hadesclient.AntiFraudRequest) (*hadesclient.AntiFraudResponse, error) {
+				require.Equal(t, uint64(1), req.ID)
+				require.Equal(t, testOwner, req.Address)
+				require.Equal(t, testDevice, req.Data.Device)
+				return &hadesclient.AntiFraudResponse{IsFraud: tc.fraud}, nil
+			})
+
+			if tc.fraud {
+				is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.IndexStorage) error) error {
+					return f(is)
+				})
+				is.EXPECT().SetProfileBanned(gomock.Any(), testOwner, storage.BanSourceHades, "fraud detected").Return(nil)
+				is.EXPECT().CreateProfileBanPDV(gomock.Any(), testOwner, uint64(1)).Return(nil)
+				is.EXPECT().CreateAdminAuditItem(gomock.Any(), gomock.Any()).Return(nil)
+			} else if tc.held {
+				is.EXPECT().IsProfileBanned(gomock.Any(), testOwner).Return(tc.banned, nil)
+			}
+
+			// data of fraud and banned pdv is dropped
+			if tc.fraud || tc.banned {
+				fs.EXPECT().Delete(gomock.Any(), getPDVFilePath(testOwner, 1)).Return(nil)
+			}
+
+			if tc.produce {
+				p.EXPECT().Produce(gomock.Any(), msg).Return(nil)
+			}
+
+			is.EXPECT().DeleteFraudRecheckItem(gomock.Any(), testOwner, uint64(1)).Return(nil)
+
+			count, err := r.Run(ctx)
+			require.NoError(t, err)
+			require.Equal(t, 1, count)
+		})
+	}
+}
+
+func TestFraudRechecker_Run_HadesError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := storagemock.NewMockIndexStorage(ctrl)
+	cr := cryptomock.NewMockCrypto(ctrl)
+	hades := hadesmock.NewMockHades(ctrl)
+
+	r := NewFraudRechecker(ownerCrypto(ctrl, cr), nil, is, nil, hades, nil)
+
+	// message produced before pdv was written by cerberus contains data
+	b, err := json.Marshal(&producer.PDVMessage{ID: 1, Address: testOwner, Data: testEncryptedData})
+	require.NoError(t, err)
+
+	plain, err := json.Marshal(schema.NewPDVWrapper(testDevice, pdv))
+	require.NoError(t, err)
+
+	is.EXPECT().GetFraudRecheckItemList(gomock.Any(), uint16(recheckBatchSize), gomock.Any()).Return([]*storage.FraudRecheckItem{
+		{Address: testOwner, ID: 1, Held: true, Message: b},
+	}, nil)
+	cr.EXPECT().Decrypt(gomock.Any()).Return(bytes.NewReader(plain), nil)
+	hades.EXPECT().AntiFraud(gomock.Any(), gomock.Any()).Return(nil, hadesclient.ErrCircuitOpen)
+
+	// item is kept until the check succeeds, attempt isn't counted while Hades is unavailable
+	count, err := r.Run(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+}
+
+func TestFraudRechecker_Run_Error(t *testing.T) {
+	tt := []struct {
+		name     string
+		attempts int
+		fail     bool
+	}{
+		{name: "postpone", attempts: 2},
+		{name: "fail", attempts: maxRecheckAttempts - 1, fail: true},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			is := storagemock.NewMockIndexStorage(ctrl)
+
+			r := NewFraudRechecker(nil, nil, is, nil, nil, nil)
+
+			is.EXPECT().GetFraudRecheckItemList(gomock.Any(), uint16(recheckBatchSize), gomock.Any()).Return([]*storage.FraudRecheckItem{
+				{Address: testOwner, ID: 1, Message: []byte("invalid"), Attempts: tc.attempts},
+			}, nil)
+
+			if tc.fail {
+				is.EXPECT().FailFraudRecheckItem(gomock.Any(), testOwner, uint64(1), gomock.Any()).Return(nil)
+			} else {
+				is.EXPECT().PostponeFraudRecheckItem(gomock.Any(), testOwner, uint64(1), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, _ uint64, next time.Time, _ string) error {
+						require.WithinDuration(t, time.Now().Add(4*recheckBackoff), next, time.Minute)
+						return nil
+					})
+			}
+
+			count, err := r.Run(ctx)
+			require.NoError(t, err)
+			require.Equal(t, 0, count)
+		})
+	}
+}
+
+func TestNextRecheckDelay(t *testing.T) {
+	require.Equal(t, recheckBackoff, nextRecheckDelay(0))
+	require.Equal(t, 2*recheckBackoff, nextRecheckDelay(1))
+	require.Equal(t, 8*recheckBackoff, nextRecheckDelay(3))
+	require.Equal(t, maxRecheckBackoff, nextRecheckDelay(maxRecheckAttempts))
+}
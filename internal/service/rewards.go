@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+
+	"github.com/Decentr-net/cerberus/pkg/schema"
+)
+
+// RewardMap contains dictionary with PDV types and rewards for them.
+type RewardMap map[schema.Type]sdk.Dec
+
+// RewardMapVersion is a reward map applied to PDV saved since EffectiveFrom.
+type RewardMapVersion struct {
+	Version       uint64    `json:"version"`
+	EffectiveFrom time.Time `json:"effectiveFrom"`
+	Rewards       RewardMap `json:"rewards"`
+}
+
+// RewardScheduleConfig is a configuration of reward map versions.
+type RewardScheduleConfig struct {
+	Versions []RewardMapVersionConfig `yaml:"versions"`
+}
+
+// RewardMapVersionConfig is a configuration of reward map version, rewards are decimals in DEC.
+type RewardMapVersionConfig struct {
+	Version       uint64                 `yaml:"version"`
+	EffectiveFrom time.Time              `yaml:"effective_from"`
+	Rewards       map[schema.Type]string `yaml:"rewards"`
+}
+
+// RewardSchedule keeps reward map versions sorted by EffectiveFrom.
+// Versions loaded from the file are reloaded with Reload, RunAsync reloads them when the file changes.
+type RewardSchedule struct {
+	path string
+
+	mu       sync.RWMutex
+	versions []RewardMapVersion
+	modTime  time.Time
+}
+
+// NewRewardSchedule creates a new instance of RewardSchedule with static versions.
+func NewRewardSchedule(versions ...RewardMapVersion) *RewardSchedule {
+	s := &RewardSchedule{versions: versions}
+	sortRewardMapVersions(s.versions)
+
+	return s
+}
+
+// LoadRewardSchedule reads reward map versions from yaml file.
+func LoadRewardSchedule(path string) (*RewardSchedule, error) {
+	s := &RewardSchedule{path: path}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Reload reads reward map versions from the file again, the previous versions are kept if the file is invalid.
+// Versions which are already effective can't be changed or removed, only upcoming versions could be added or edited,
+// so rewards of saved PDV aren't changed retroactively.
+func (s *RewardSchedule) Reload() error {
+	if s.path == "" {
+		return nil
+	}
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to stat reward map config: %w", err)
+	}
+
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read reward map config: %w", err)
+	}
+
+	versions, err := parseRewardSchedule(b)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := checkEffectiveVersions(s.versions, versions, time.Now()); err != nil {
+		return fmt.Errorf("reward map config is rejected: %w", err)
+	}
+
+	s.versions = versions
+	s.modTime = info.ModTime()
+
+	return nil
+}
+
+// RunAsync checks the file every interval and reloads versions if the file is modified until ctx is done.
+func (s *RewardSchedule) RunAsync(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			info, err := os.Stat(s.path)
+			if err != nil {
+				log.WithError(err).Error("failed to stat reward map config")
+				continue
+			}
+
+			s.mu.RLock()
+			modified := !info.ModTime().Equal(s.modTime)
+			s.mu.RUnlock()
+
+			if !modified {
+				continue
+			}
+
+			if err := s.Reload(); err != nil {
+				log.WithError(err).Error("failed to reload reward map config")
+				continue
+			}
+			log.Info("reward map config reloaded")
+		}
+	}()
+}
+
+// At returns the version effective at the time. Zero version with empty rewards is returned if no version is effective.
+func (s *RewardSchedule) At(t time.Time) RewardMapVersion {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := RewardMapVersion{Rewards: RewardMap{}}
+	for _, v := range s.versions {
+		if v.EffectiveFrom.After(t) {
+			break
+		}
+		out = v
+	}
+
+	return out
+}
+
+// Upcoming returns versions which become effective after the time.
+func (s *RewardSchedule) Upcoming(t time.Time) []RewardMapVersion {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := []RewardMapVersion{}
+	for _, v := range s.versions {
+		if v.EffectiveFrom.After(t) {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+func parseRewardSchedule(b []byte) ([]RewardMapVersion, error) {
+	var c RewardScheduleConfig
+	if err := yaml.UnmarshalStrict(b, &c); err != nil {
+		// the former config is a plain reward map which is effective forever
+		var legacy map[schema.Type]string
+		if yaml.UnmarshalStrict(b, &legacy) != nil {
+			return nil, fmt.Errorf("failed to unmarshal reward map config: %w", err)
+		}
+
+		c.Versions = []RewardMapVersionConfig{{Rewards: legacy}}
+	}
+
+	if len(c.Versions) == 0 {
+		return nil, errors.New("reward map config has no versions")
+	}
+
+	var (
+		versions = make([]RewardMapVersion, len(c.Versions))
+		seen     = make(map[uint64]struct{}, len(c.Versions))
+	)
+
+	for i, v := range c.Versions {
+		if _, ok := seen[v.Version]; ok {
+			return nil, fmt.Errorf("duplicated reward map version %d", v.Version)
+		}
+		seen[v.Version] = struct{}{}
+
+		rewards := make(RewardMap, len(v.Rewards))
+		for t, r := range v.Rewards {
+			d, err := sdk.NewDecFromStr(r)
+			if err != nil || d.IsNegative() {
+				return nil, fmt.Errorf("invalid %s reward %q of version %d", t, r, v.Version)
+			}
+			rewards[t] = d
+		}
+
+		versions[i] = RewardMapVersion{
+			Version:       v.Version,
+			EffectiveFrom: v.EffectiveFrom.UTC(),
+			Rewards:       rewards,
+		}
+	}
+
+	sortRewardMapVersions(versions)
+
+	for i := 1; i < len(versions); i++ {
+		if !versions[i].EffectiveFrom.After(versions[i-1].EffectiveFrom) {
+			return nil, fmt.Errorf("versions %d and %d have the same effective date", versions[i-1].Version, versions[i].Version)
+		}
+		if versions[i].Version < versions[i-1].Version {
+			return nil, fmt.Errorf("version %d is effective after version %d", versions[i-1].Version, versions[i].Version)
+		}
+	}
+
+	return versions, nil
+}
+
+// checkEffectiveVersions checks that versions effective at now are the same in old and new versions.
+func checkEffectiveVersions(old, new []RewardMapVersion, now time.Time) error {
+	newByVersion := make(map[uint64]RewardMapVersion, len(new))
+	for _, v := range new {
+		newByVersion[v.Version] = v
+	}
+
+	oldByVersion := make(map[uint64]RewardMapVersion, len(old))
+	for _, v := range old {
+		oldByVersion[v.Version] = v
+
+		if v.EffectiveFrom.After(now) {
+			continue
+		}
+
+		n, ok := newByVersion[v.Version]
+		if !ok {
+			return fmt.Errorf("effective version %d is removed", v.Version)
+		}
+		if !equalRewardMapVersions(v, n) {
+			return fmt.Errorf("effective version %d is changed", v.Version)
+		}
+	}
+
+	// the first load has nothing to compare with
+	if len(old) == 0 {
+		return nil
+	}
+
+	for _, v := range new {
+		if v.EffectiveFrom.After(now) {
+			continue
+		}
+
+		if o, ok := oldByVersion[v.Version]; !ok || o.EffectiveFrom.After(now) {
+			return fmt.Errorf("version %d becomes effective retroactively", v.Version)
+		}
+	}
+
+	return nil
+}
+
+func equalRewardMapVersions(a, b RewardMapVersion) bool {
+	if !a.EffectiveFrom.Equal(b.EffectiveFrom) || len(a.Rewards) != len(b.Rewards) {
+		return false
+	}
+
+	for t, r := range a.Rewards {
+		if v, ok := b.Rewards[t]; !ok || !v.Equal(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortRewardMapVersions(versions []RewardMapVersion) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].EffectiveFrom.Before(versions[j].EffectiveFrom)
+	})
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Decentr-net/cerberus/pkg/schema"
+)
+
+func TestLoadRewardSchedule(t *testing.T) {
+	s, err := LoadRewardSchedule("../../configs/rewards.yml")
+	require.NoError(t, err)
+
+	v := s.At(time.Now())
+	require.EqualValues(t, 2, v.Version)
+	require.Len(t, v.Rewards, 10)
+	require.Equal(t, sdk.NewDecWithPrec(1, 8).String(), v.Rewards[schema.PDVCookieType].String())
+	require.Equal(t, sdk.NewDecWithPrec(1, 7).String(), v.Rewards[schema.PDVBrowsingHistoryType].String())
+}
+
+func TestParseRewardSchedule(t *testing.T) {
+	tt := []struct {
+		name     string
+		config   string
+		versions []uint64
+		valid    bool
+	}{
+		{
+			name: "sorted by effective date",
+			config: `
+versions:
+  - version: 2
+    effective_from: 2022-02-01T00:00:00Z
+    rewards:
+      cookie: "0.000002"
+  - version: 1
+    effective_from: 2022-01-01T00:00:00Z
+    rewards:
+      cookie: "0.000001"
+`,
+			versions: []uint64{1, 2},
+			valid:    true,
+		},
+		{
+			name:     "legacy",
+			config:   `{"cookie": "0.000001", "location": "0.000002"}`,
+			versions: []uint64{0},
+			valid:    true,
+		},
+		{
+			name:   "empty",
+			config: `versions: []`,
+		},
+		{
+			name: "unknown type",
+			config: `
+versions:
+  - version: 1
+    rewards:
+      unknown: "0.000001"
+`,
+		},
+		{
+			name: "negative reward",
+			config: `
+versions:
+  - version: 1
+    rewards:
+      cookie: "-0.000001"
+`,
+		},
+		{
+			name: "duplicated version",
+			config: `
+versions:
+  - version: 1
+    effective_from: 2022-01-01T00:00:00Z
+  - version: 1
+    effective_from: 2022-02-01T00:00:00Z
+`,
+		},
+		{
+			name: "same effective date",
+			config: `
+versions:
+  - version: 1
+    effective_from: 2022-01-01T00:00:00Z
+  - version: 2
+    effective_from: 2022-01-01T00:00:00Z
+`,
+		},
+		{
+			name: "older version is effective later",
+			config: `
+versions:
+  - version: 2
+    effective_from: 2022-01-01T00:00:00Z
+  - version: 1
+    effective_from: 2022-02-01T00:00:00Z
+`,
+		},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.name, func(t *testing.T) {
+			versions, err := parseRewardSchedule([]byte(tc.config))
+			if !tc.valid {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			ids := make([]uint64, len(versions))
+			for i, v := range versions {
+				ids[i] = v.Version
+			}
+			require.Equal(t, tc.versions, ids)
+		})
+	}
+}
+
+func TestRewardSchedule_At(t *testing.T) {
+	jan := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	v1 := RewardMapVersion{Version: 1, EffectiveFrom: jan, Rewards: RewardMap{schema.PDVCookieType: sdk.NewDecWithPrec(1, 6)}}
+	v2 := RewardMapVersion{Version: 2, EffectiveFrom: feb, Rewards: RewardMap{schema.PDVCookieType: sdk.NewDecWithPrec(2, 6)}}
+
+	s := NewRewardSchedule(v2, v1)
+
+	require.Equal(t, RewardMapVersion{Rewards: RewardMap{}}, s.At(jan.Add(-time.Second)))
+	require.Equal(t, v1, s.At(jan))
+	require.Equal(t, v1, s.At(feb.Add(-time.Second)))
+	require.Equal(t, v2, s.At(feb))
+
+	require.Equal(t, []RewardMapVersion{v1, v2}, s.Upcoming(jan.Add(-time.Second)))
+	require.Equal(t, []RewardMapVersion{v2}, s.Upcoming(jan))
+	require.Equal(t, []RewardMapVersion{}, s.Upcoming(feb))
+}
+
+func TestRewardSchedule_RunAsync(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rewards.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`{"cookie": "0.000001"}`), 0600))
+
+	s, err := LoadRewardSchedule(path)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, s.At(time.Now()).Version)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.RunAsync(ctx, 10*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(path, []byte(`
+versions:
+  - version: 0
+    rewards:
+      cookie: "0.000001"
+  - version: 1
+    effective_from: 2100-01-01T00:00:00Z
+    rewards:
+      cookie: "0.000002"
+`), 0600))
+	// modification time could be the same on file systems with low resolution
+	require.NoError(t, os.Chtimes(path, time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+
+	require.Eventually(t, func() bool {
+		return len(s.Upcoming(time.Now())) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// invalid file doesn't replace valid versions
+	require.NoError(t, os.WriteFile(path, []byte(`invalid`), 0600))
+	require.Error(t, s.Reload())
+	require.Len(t, s.Upcoming(time.Now()), 1)
+
+	// effective version can't be changed
+	require.NoError(t, os.WriteFile(path, []byte(`{"cookie": "0.000003"}`), 0600))
+	require.Error(t, s.Reload())
+	require.Equal(t, sdk.NewDecWithPrec(1, 6).String(), s.At(time.Now()).Rewards[schema.PDVCookieType].String())
+}
+
+func TestCheckEffectiveVersions(t *testing.T) {
+	jan := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+	mar := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	now := feb.Add(time.Hour)
+
+	version := func(id uint64, from time.Time, cookie int64) RewardMapVersion {
+		return RewardMapVersion{Version: id, EffectiveFrom: from, Rewards: RewardMap{schema.PDVCookieType: sdk.NewDecWithPrec(cookie, 6)}}
+	}
+
+	old := []RewardMapVersion{version(1, jan, 1), version(2, feb, 2), version(3, mar, 3)}
+
+	tt := []struct {
+		name  string
+		new   []RewardMapVersion
+		valid bool
+	}{
+		{name: "same", new: old, valid: true},
+		{name: "upcoming is edited", new: []RewardMapVersion{version(1, jan, 1), version(2, feb, 2), version(3, mar, 4)}, valid: true},
+		{name: "upcoming is removed", new: []RewardMapVersion{version(1, jan, 1), version(2, feb, 2)}, valid: true},
+		{name: "upcoming is added", new: []RewardMapVersion{version(1, jan, 1), version(2, feb, 2), version(4, mar, 3)}, valid: true},
+		{name: "effective is changed", new: []RewardMapVersion{version(1, jan, 1), version(2, feb, 5), version(3, mar, 3)}},
+		{name: "effective date is changed", new: []RewardMapVersion{version(1, jan, 1), version(2, mar, 2)}},
+		{name: "effective is removed", new: []RewardMapVersion{version(1, jan, 1), version(3, mar, 3)}},
+		{name: "effective is added", new: []RewardMapVersion{version(1, jan, 1), version(2, feb, 2), version(4, now, 3)}},
+		{name: "upcoming becomes effective", new: []RewardMapVersion{version(1, jan, 1), version(2, feb, 2), version(3, now, 3)}},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkEffectiveVersions(old, tc.new, now)
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+
+	// the first load accepts any versions
+	require.NoError(t, checkEffectiveVersions(nil, old, now))
+}
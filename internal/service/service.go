@@ -12,14 +12,12 @@ import (
 	"io/ioutil"
 	"math"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/disintegration/imaging"
 	"github.com/google/uuid"
-	log "github.com/sirupsen/logrus"
 
 	"github.com/Decentr-net/cerberus/internal/crypto"
 	"github.com/Decentr-net/cerberus/internal/entities"
@@ -40,16 +38,42 @@ var (
 	ErrUploadTimeout      = errors.New("upload timeout")
 	ErrPDVFraud           = errors.New("PDV fraud detected")
 	ErrProfileBanned      = errors.New("profile banned")
+	// ErrFraudCheckUnavailable is returned when Hades fails and policy is FraudCheckFailClosed.
+	ErrFraudCheckUnavailable = errors.New("fraud check unavailable")
+	// ErrAlreadyExists means that object already exists.
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrInvalidBlacklistEntry means that kind or pattern of the blacklist entry is invalid.
+	ErrInvalidBlacklistEntry = errors.New("invalid blacklist entry")
 )
 
-// RewardMap contains dictionary with PDV types and rewards for them.
-type RewardMap map[schema.Type]sdk.Dec
+// FraudCheckPolicy defines what happens with PDV when Hades fails to check it.
+type FraudCheckPolicy string
 
-// Blacklist contains attributes of worthless pdv.
-// swagger:model Blacklist
-type Blacklist struct {
-	CookieSource []string `json:"cookieSource"`
-}
+const (
+	// FraudCheckFailOpen saves and rewards PDV, the PDV is rechecked later.
+	FraudCheckFailOpen FraudCheckPolicy = "fail-open"
+	// FraudCheckFailClosed rejects PDV.
+	FraudCheckFailClosed FraudCheckPolicy = "fail-closed"
+	// FraudCheckReview holds PDV until it's rechecked, it's saved and rewarded only if no fraud is detected.
+	FraudCheckReview FraudCheckPolicy = "review"
+)
+
+// Reasons of rejection which aren't refine rules.
+const (
+	// RejectedBlacklist means that cookie source is blacklisted.
+	RejectedBlacklist = "blacklist"
+	// RejectedDuplicate means that the data was already sent by the owner.
+	RejectedDuplicate = "duplicate"
+)
+
+// admin audit actions
+const (
+	auditActionBan   = "ban"
+	auditActionUnban = "unban"
+)
+
+// hadesActor is an actor of automatic bans in the admin audit.
+const hadesActor = "hades"
 
 // Service interface provides service's logic's methods.
 type Service interface {
@@ -57,6 +81,8 @@ type Service interface {
 	SaveImage(ctx context.Context, r io.Reader, owner string) (string, string, error)
 	// SavePDV sends PDV to storage.
 	SavePDV(ctx context.Context, p schema.PDVWrapper, owner sdk.AccAddress) (uint64, *entities.PDVMeta, error)
+	// GetDuplicatePDV returns indices of cookies and search history which the owner has already sent.
+	GetDuplicatePDV(ctx context.Context, owner string, p schema.PDV) ([]int, error)
 	// ListPDV lists PDVs.
 	ListPDV(ctx context.Context, owner string, from uint64, limit uint16) ([]uint64, error)
 	// ReceivePDV returns slice of bytes of PDV requested by address from storage.
@@ -67,11 +93,17 @@ type Service interface {
 	// GetProfiles ...
 	GetProfiles(ctx context.Context, owner []string) ([]*entities.Profile, error)
 
-	// GetRewardsMap ...
-	GetRewardsMap() RewardMap
+	// GetRewards returns the reward map version effective now and upcoming versions.
+	GetRewards() (RewardMapVersion, []RewardMapVersion)
 
-	// GetBlacklist ...
+	// GetBlacklist returns cached blacklist.
 	GetBlacklist() Blacklist
+	// ListBlacklist returns all blacklist entries.
+	ListBlacklist(ctx context.Context) ([]*entities.BlacklistEntry, error)
+	// AddBlacklistEntry adds the pattern to the blacklist on behalf of the admin and returns id of the entry.
+	AddBlacklistEntry(ctx context.Context, actor, kind, pattern string) (uint64, error)
+	// DeleteBlacklistEntry deletes the entry from the blacklist on behalf of the admin.
+	DeleteBlacklistEntry(ctx context.Context, actor string, id uint64) error
 
 	// GetPDVDelta ...
 	GetPDVDelta(ctx context.Context, owner string) (sdk.Dec, error)
@@ -81,40 +113,70 @@ type Service interface {
 
 	// GetPDVRewardsNextDistributionDate ...
 	GetPDVRewardsNextDistributionDate(ctx context.Context) (time.Time, error)
+
+	// ListPDVRewardsHistory lists distributed PDV rewards of the owner.
+	ListPDVRewardsHistory(ctx context.Context, owner string, from uint64, limit uint16) ([]*entities.PDVRewardsHistoryItem, error)
+
+	// ListBannedProfiles lists banned profiles sorted by address starting after from address.
+	ListBannedProfiles(ctx context.Context, from string, limit uint16) ([]*entities.BannedProfile, error)
+	// BanProfile bans the profile on behalf of the admin.
+	BanProfile(ctx context.Context, actor, owner, reason string) error
+	// UnbanProfile unbans the profile on behalf of the admin.
+	UnbanProfile(ctx context.Context, actor, owner, reason string) error
+	// ListProfileBanPDV returns ids of pdv which triggered the profile ban.
+	ListProfileBanPDV(ctx context.Context, owner string) ([]uint64, error)
 }
 
 // service is Service interface implementation.
 type service struct {
-	c     crypto.Crypto
+	c     crypto.OwnerCrypto
 	is    storage.IndexStorage
 	fs    storage.FileStorage
 	p     producer.Producer
 	hades hades.Hades
+	// shadow's verdicts are only saved, it's nil if shadow mode is disabled
+	shadow hades.Hades
 
-	rewardMap RewardMap
+	rewards   *RewardSchedule
+	refiner   refine.Registry
+	blacklist *BlacklistCache
 
 	pdvRewardsInterval time.Duration
+	fraudCheckPolicy   FraudCheckPolicy
+	// cookies and search history sent during retention window aren't rewarded, 0 disables the check
+	fingerprintRetention time.Duration
 }
 
-// New returns new instance of service.
+// New returns new instance of service. Verdicts of shadow antifraud are saved without affecting pdv, shadow could be nil.
 func New(
-	c crypto.Crypto,
+	c crypto.OwnerCrypto,
 	fs storage.FileStorage,
 	is storage.IndexStorage,
 	p producer.Producer,
 	hades hades.Hades,
-	rewardMap RewardMap,
+	shadow hades.Hades,
+	rewards *RewardSchedule,
+	refiner refine.Registry,
+	blacklist *BlacklistCache,
 	pdvRewardsInterval time.Duration,
+	fraudCheckPolicy FraudCheckPolicy,
+	fingerprintRetention time.Duration,
 ) Service {
 	return &service{
-		c:     c,
-		fs:    fs,
-		is:    is,
-		p:     p,
-		hades: hades,
-
-		rewardMap:          rewardMap,
+		c:      c,
+		fs:     fs,
+		is:     is,
+		p:      p,
+		hades:  hades,
+		shadow: shadow,
+
+		rewards:            rewards,
+		refiner:            refiner,
+		blacklist:          blacklist,
 		pdvRewardsInterval: pdvRewardsInterval,
+		fraudCheckPolicy:   fraudCheckPolicy,
+
+		fingerprintRetention: fingerprintRetention,
 	}
 }
 
@@ -145,44 +207,145 @@ func (s *service) SavePDV(ctx context.Context, p schema.PDVWrapper, owner sdk.Ac
 		return 0, nil, fmt.Errorf("failed to marshal meta: %w", err)
 	}
 
-	log.Debug("encrypting pdv")
-	enc, err := s.c.Encrypt(data)
-	if err != nil {
-		return 0, nil, fmt.Errorf("failed to create encrypting reader: %w", err)
-	}
-
 	id := uint64(time.Now().Unix())
 
-	fraudCheck, err := s.hades.AntiFraud(ctx, &hades.AntiFraudRequest{
+	msg := &producer.PDVMessage{
+		ID:      id,
+		Device:  p.Device,
+		Address: owner.String(),
+		Meta:    meta,
+	}
+
+	fraudCheckReq := &hades.AntiFraudRequest{
 		ID:      id,
 		Address: owner.String(),
 		Data:    p,
-	})
-	if err != nil {
-		log.WithError(err).Error("failed to anti fraud")
+	}
+
+	fraudCheck, fraudCheckErr := s.hades.AntiFraud(ctx, fraudCheckReq)
+
+	if s.shadow != nil {
+		s.checkShadow(ctx, fraudCheckReq)
+	}
+
+	if fraudCheckErr != nil {
+		log.WithError(fraudCheckErr).WithField("policy", s.fraudCheckPolicy).Error("failed to anti fraud")
+
+		if s.fraudCheckPolicy == FraudCheckFailClosed {
+			return 0, nil, ErrFraudCheckUnavailable
+		}
 	}
 
 	if fraudCheck != nil && fraudCheck.IsFraud {
+		log.WithField("id", id).WithField("rule", fraudCheck.Rule).Warn("fraud detected")
+
 		// autoban for fraud
-		if err := s.is.SetProfileBanned(context.Background(), owner.String()); err != nil {
+		if err := banForFraud(context.Background(), s.is, owner.String(), id); err != nil {
 			log.WithError(err).Error("failed to ban")
 		}
 		return id, nil, ErrPDVFraud
 	}
 
-	if err := s.p.Produce(ctx, &producer.PDVMessage{
-		ID:      id,
-		Device:  p.Device,
-		Address: owner.String(),
-		Meta:    meta,
-		Data:    enc,
-	}); err != nil {
+	// encrypted pdv is streamed into the storage, message refers to it by owner and id
+	log.Debug("encrypting pdv")
+	if err := s.writePDV(ctx, owner.String(), id, data); err != nil {
+		return 0, nil, err
+	}
+
+	if fraudCheckErr != nil {
+		if s.fraudCheckPolicy == FraudCheckReview {
+			// pdv is produced by rechecker
+			if err := createFraudRecheckItem(ctx, s.is, msg, true); err != nil {
+				s.deletePDV(ctx, owner.String(), id)
+				return 0, nil, err
+			}
+			s.saveFingerprintsOrLog(ctx, owner.String(), p.Data())
+			return id, meta, nil
+		}
+
+		if err := createFraudRecheckItem(ctx, s.is, msg, false); err != nil {
+			s.deletePDV(ctx, owner.String(), id)
+			return 0, nil, err
+		}
+	}
+
+	if err := s.p.Produce(ctx, msg); err != nil {
+		s.deletePDV(ctx, owner.String(), id)
 		return 0, nil, fmt.Errorf("failed to produce pdv message: %w", err)
 	}
 
+	s.saveFingerprintsOrLog(ctx, owner.String(), p.Data())
+
 	return id, meta, nil
 }
 
+// writePDV encrypts pdv data and writes it into the file storage without buffering ciphertext.
+// Owner's data key is created on the first write.
+func (s *service) writePDV(ctx context.Context, owner string, id uint64, data []byte) error {
+	c, err := s.c.ForOwner(ctx, owner)
+	if err != nil {
+		return fmt.Errorf("failed to get owner's crypto: %w", err)
+	}
+
+	r, size := c.EncryptReader(bytes.NewReader(data))
+
+	if _, err := s.fs.Write(ctx, r, size, getPDVFilePath(owner, id), "binary/octet-stream", false); err != nil {
+		return fmt.Errorf("failed to write data to storage: %w", err)
+	}
+
+	return nil
+}
+
+// deletePDV removes written pdv which wasn't saved, so the storage doesn't keep orphaned files.
+func (s *service) deletePDV(ctx context.Context, owner string, id uint64) {
+	if err := s.fs.Delete(ctx, getPDVFilePath(owner, id)); err != nil {
+		logging.GetLogger(ctx).WithError(err).WithField("id", id).Error("failed to delete pdv")
+	}
+}
+
+// createFraudRecheckItem saves pdv message which skipped antifraud check.
+func createFraudRecheckItem(ctx context.Context, is storage.IndexStorage, msg *producer.PDVMessage, held bool) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pdv message: %w", err)
+	}
+
+	if err := is.CreateFraudRecheckItem(ctx, &storage.FraudRecheckItem{
+		Address: msg.Address,
+		ID:      msg.ID,
+		Held:    held,
+		Message: b,
+	}); err != nil {
+		return fmt.Errorf("failed to create fraud recheck item: %w", err)
+	}
+
+	return nil
+}
+
+// banForFraud bans the owner of fraud pdv.
+func banForFraud(ctx context.Context, is storage.IndexStorage, owner string, id uint64) error {
+	return is.InTx(ctx, func(tx storage.IndexStorage) error {
+		if err := tx.SetProfileBanned(ctx, owner, storage.BanSourceHades, "fraud detected"); err != nil {
+			return fmt.Errorf("failed to set profile banned: %w", err)
+		}
+
+		if err := tx.CreateProfileBanPDV(ctx, owner, id); err != nil {
+			return fmt.Errorf("failed to create profile ban pdv: %w", err)
+		}
+
+		if err := tx.CreateAdminAuditItem(ctx, &storage.AdminAuditItem{
+			Actor:   hadesActor,
+			Action:  auditActionBan,
+			Address: owner,
+			Reason:  fmt.Sprintf("fraud detected in pdv %d", id),
+		}); err != nil {
+			return fmt.Errorf("failed to create admin audit item: %w", err)
+		}
+
+		return nil
+	})
+}
+
 func (s *service) SaveImage(ctx context.Context, r io.Reader, owner string) (string, string, error) {
 	dataImage, err := ioutil.ReadAll(r)
 	if err != nil {
@@ -277,9 +440,18 @@ func (s *service) ReceivePDV(ctx context.Context, owner string, id uint64) ([]by
 	}
 	defer r.Close() // nolint
 
+	c, err := s.c.ForOwnerRead(ctx, owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get owner's crypto: %w", err)
+	}
+
 	log.Debug("decrypting meta")
-	dr, err := s.c.Decrypt(r)
+	dr, err := c.Decrypt(r)
 	if err != nil {
+		// data key is deleted on account reset, so data can't be read anymore
+		if errors.Is(err, crypto.ErrKeyNotFound) {
+			return nil, ErrNotFound
+		}
 		return nil, fmt.Errorf("failed to create decrypting reader: %w", err)
 	}
 
@@ -305,31 +477,121 @@ func (s *service) GetPDVMeta(ctx context.Context, owner string, id uint64) (*ent
 }
 
 func (s *service) GetPDVDelta(ctx context.Context, owner string) (sdk.Dec, error) {
-	total, err := s.is.GetPDVDelta(ctx, owner)
+	delta, err := s.is.GetPDVDelta(ctx, owner)
 	if err != nil {
 		return sdk.ZeroDec(), fmt.Errorf("failed to get pdv delta: %w", err)
 	}
 
-	dec, err := float64ToDecimal(total)
+	return delta, nil
+}
+
+// ListPDVRewardsHistory lists distributed PDV rewards of the owner.
+func (s *service) ListPDVRewardsHistory(ctx context.Context, owner string, from uint64, limit uint16) ([]*entities.PDVRewardsHistoryItem, error) {
+	items, err := s.is.ListPDVRewardsHistory(ctx, owner, from, limit)
 	if err != nil {
-		return sdk.ZeroDec(), fmt.Errorf("failed to convert to sdk.Dec: %w", err)
+		return nil, fmt.Errorf("failed to list pdv rewards history: %w", err)
+	}
+
+	out := make([]*entities.PDVRewardsHistoryItem, len(items))
+	for i, v := range items {
+		out[i] = &entities.PDVRewardsHistoryItem{
+			ID:          v.ID,
+			Amount:      v.Amount,
+			PeriodStart: v.PeriodStart,
+			PeriodEnd:   v.PeriodEnd,
+			TxHash:      v.TxHash,
+			CreatedAt:   v.CreatedAt,
+		}
 	}
 
-	return dec, nil
+	return out, nil
 }
 
-func (s *service) GetPDVTotalDelta(ctx context.Context) (sdk.Dec, error) {
-	total, err := s.is.GetPDVTotalDelta(ctx)
+// ListBannedProfiles lists banned profiles sorted by address starting after from address.
+func (s *service) ListBannedProfiles(ctx context.Context, from string, limit uint16) ([]*entities.BannedProfile, error) {
+	pp, err := s.is.ListBannedProfiles(ctx, from, limit)
 	if err != nil {
-		return sdk.ZeroDec(), fmt.Errorf("failed to get pdv delta total: %w", err)
+		return nil, fmt.Errorf("failed to list banned profiles: %w", err)
 	}
 
-	dec, err := float64ToDecimal(total)
+	out := make([]*entities.BannedProfile, len(pp))
+	for i, v := range pp {
+		out[i] = &entities.BannedProfile{
+			Address:  v.Address,
+			Source:   string(v.Source),
+			Reason:   v.Reason,
+			BannedAt: v.BannedAt,
+		}
+	}
+
+	return out, nil
+}
+
+// BanProfile bans the profile on behalf of the admin.
+func (s *service) BanProfile(ctx context.Context, actor, owner, reason string) error {
+	return s.is.InTx(ctx, func(tx storage.IndexStorage) error {
+		if err := tx.SetProfileBanned(ctx, owner, storage.BanSourceManual, reason); err != nil {
+			return fmt.Errorf("failed to set profile banned: %w", err)
+		}
+
+		if err := tx.CreateAdminAuditItem(ctx, &storage.AdminAuditItem{
+			Actor:   actor,
+			Action:  auditActionBan,
+			Address: owner,
+			Reason:  reason,
+		}); err != nil {
+			return fmt.Errorf("failed to create admin audit item: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// UnbanProfile unbans the profile on behalf of the admin.
+func (s *service) UnbanProfile(ctx context.Context, actor, owner, reason string) error {
+	return s.is.InTx(ctx, func(tx storage.IndexStorage) error {
+		if err := tx.UnsetProfileBanned(ctx, owner); err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				return ErrNotFound
+			}
+			return fmt.Errorf("failed to unset profile banned: %w", err)
+		}
+
+		// ids of pdv which triggered hades ban are kept in the audit
+		if err := tx.DeleteProfileBanPDV(ctx, owner); err != nil {
+			return fmt.Errorf("failed to delete profile ban pdv: %w", err)
+		}
+
+		if err := tx.CreateAdminAuditItem(ctx, &storage.AdminAuditItem{
+			Actor:   actor,
+			Action:  auditActionUnban,
+			Address: owner,
+			Reason:  reason,
+		}); err != nil {
+			return fmt.Errorf("failed to create admin audit item: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// ListProfileBanPDV returns ids of pdv which triggered the profile ban.
+func (s *service) ListProfileBanPDV(ctx context.Context, owner string) ([]uint64, error) {
+	ids, err := s.is.ListProfileBanPDV(ctx, owner)
 	if err != nil {
-		return sdk.ZeroDec(), fmt.Errorf("failed to convert to sdk.Dec: %w", err)
+		return nil, fmt.Errorf("failed to list profile ban pdv: %w", err)
 	}
 
-	return dec, nil
+	return ids, nil
+}
+
+func (s *service) GetPDVTotalDelta(ctx context.Context) (sdk.Dec, error) {
+	total, err := s.is.GetPDVTotalDelta(ctx)
+	if err != nil {
+		return sdk.ZeroDec(), fmt.Errorf("failed to get pdv delta total: %w", err)
+	}
+
+	return total, nil
 }
 
 func (s *service) GetPDVRewardsNextDistributionDate(ctx context.Context) (time.Time, error) {
@@ -341,10 +603,6 @@ func (s *service) GetPDVRewardsNextDistributionDate(ctx context.Context) (time.T
 	return date.Add(s.pdvRewardsInterval), nil
 }
 
-func float64ToDecimal(f float64) (sdk.Dec, error) {
-	return sdk.NewDecFromStr(strconv.FormatFloat(f, 'f', 6, 64))
-}
-
 // GetProfiles ...
 func (s *service) GetProfiles(ctx context.Context, owner []string) ([]*entities.Profile, error) {
 	pp, err := s.is.GetProfiles(ctx, owner)
@@ -360,57 +618,70 @@ func (s *service) GetProfiles(ctx context.Context, owner []string) ([]*entities.
 	return out, nil
 }
 
-// GetRewardsMap ...
-func (s *service) GetRewardsMap() RewardMap {
-	return s.rewardMap
-}
+// GetRewards returns the reward map version effective now and upcoming versions.
+func (s *service) GetRewards() (RewardMapVersion, []RewardMapVersion) {
+	now := time.Now().UTC()
 
-func (s *service) GetBlacklist() Blacklist {
-	return Blacklist{
-		CookieSource: []string{"youtube.com"},
-	}
+	return s.rewards.At(now), s.rewards.Upcoming(now)
 }
 
 func (s *service) calculateMeta(ctx context.Context, owner sdk.AccAddress, p schema.PDV) (*entities.PDVMeta, error) {
 	t := make(map[schema.Type]uint16)
 	reward := sdk.ZeroDec()
+	rewards := s.rewards.At(time.Now().UTC())
 
-	for _, d := range p.Data() {
+	seen, err := s.getSeenFingerprints(ctx, owner.String(), p.Data())
+	if err != nil {
+		return nil, err
+	}
+
+	var rejected []entities.RejectedPDV
+	for i, d := range p.Data() {
 		t[d.Type()] = t[d.Type()] + 1
 
-		switch d.Type() {
-		case schema.PDVProfileType:
+		if d.Type() == schema.PDVProfileType {
 			if _, err := s.is.GetProfile(ctx, owner.String()); err == nil {
 				continue // we want reward user only for initial profile
 			} else if err != storage.ErrNotFound {
 				return nil, fmt.Errorf("failed to check profile: %w", err)
 			}
-		case schema.PDVCookieType:
-			cookie, ok := d.(*schema.V1Cookie)
-			if !ok {
-				log.WithField("cookie", p).Error("failed to cast cookie to V1Cookie")
-			} else if s.isCookieBlacklisted(cookie) || !refine.Cookie(cookie) {
-				continue
-			}
-		case schema.PDVSearchHistoryType:
-			sh, ok := d.(*schema.V1SearchHistory)
-			if !ok {
-				log.WithField("search history", p).Error("failed to cast search history to V1SearchHistory")
-			} else if !refine.SearchHistory(sh) {
-				continue
-			}
-		default:
 		}
 
-		reward = reward.Add(s.rewardMap[d.Type()])
+		if rule := s.reject(d, seen); rule != "" {
+			rejected = append(rejected, entities.RejectedPDV{Index: i, Rule: rule})
+			continue
+		}
+
+		if v, ok := rewards.Rewards[d.Type()]; ok {
+			reward = reward.Add(v)
+		}
 	}
 
 	return &entities.PDVMeta{
-		ObjectTypes: t,
-		Reward:      reward,
+		ObjectTypes:      t,
+		Reward:           reward,
+		RewardMapVersion: rewards.Version,
+		Rejected:         rejected,
 	}, nil
 }
 
+// reject returns the reason why the data isn't rewarded or empty string if it is.
+func (s *service) reject(d schema.Data, seen fingerprintSet) string {
+	if s.isBlacklisted(d) {
+		return RejectedBlacklist
+	}
+
+	if rule := s.refiner.Check(d); rule != "" {
+		return rule
+	}
+
+	if seen.seen(d) {
+		return RejectedDuplicate
+	}
+
+	return ""
+}
+
 func (s *service) processPDV(ctx context.Context, owner sdk.AccAddress, p schema.PDV) error {
 	for _, d := range p.Data() {
 		switch d.Type() {
@@ -425,15 +696,6 @@ func (s *service) processPDV(ctx context.Context, owner sdk.AccAddress, p schema
 	return nil
 }
 
-func (s *service) isCookieBlacklisted(cookie *schema.V1Cookie) bool {
-	for _, v := range s.GetBlacklist().CookieSource {
-		if strings.EqualFold(v, cookie.Source.Host) {
-			return true
-		}
-	}
-	return false
-}
-
 func getSetProfileParams(owner sdk.AccAddress, p schema.V1Profile) *storage.SetProfileParams { // nolint:gocritic
 	params := storage.SetProfileParams{
 		Address:   owner.String(),
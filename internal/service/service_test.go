@@ -4,12 +4,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"image"
 	"io"
 	"io/ioutil"
 	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -19,12 +22,14 @@ import (
 	"github.com/stretchr/testify/require"
 
 	_ "github.com/Decentr-net/cerberus/internal/blockchain"
+	"github.com/Decentr-net/cerberus/internal/crypto"
 	cryptomock "github.com/Decentr-net/cerberus/internal/crypto/mock"
 	"github.com/Decentr-net/cerberus/internal/entities"
 	hadesclient "github.com/Decentr-net/cerberus/internal/hades"
 	hadesmock "github.com/Decentr-net/cerberus/internal/hades/mock"
 	"github.com/Decentr-net/cerberus/internal/producer"
 	producermock "github.com/Decentr-net/cerberus/internal/producer/mock"
+	"github.com/Decentr-net/cerberus/internal/refine"
 	"github.com/Decentr-net/cerberus/internal/storage"
 	storagemock "github.com/Decentr-net/cerberus/internal/storage/mock"
 	"github.com/Decentr-net/cerberus/pkg/schema"
@@ -47,6 +52,7 @@ var (
 		schema.PDVLocationType: sdk.NewDecWithPrec(4, 6),
 		schema.PDVProfileType:  sdk.NewDecWithPrec(6, 6),
 	}
+	rewards = NewRewardSchedule(RewardMapVersion{Version: 1, Rewards: rewardsMap})
 )
 
 var pdv = v1.PDV{
@@ -125,6 +131,28 @@ func TestService_SaveImage(t *testing.T) {
 	}
 }
 
+// ownerCrypto returns OwnerCrypto mock which returns cr for any owner.
+func ownerCrypto(ctrl *gomock.Controller, cr crypto.Crypto) crypto.OwnerCrypto {
+	oc := cryptomock.NewMockOwnerCrypto(ctrl)
+	oc.EXPECT().ForOwner(gomock.Any(), gomock.Any()).Return(cr, nil).AnyTimes()
+	oc.EXPECT().ForOwnerRead(gomock.Any(), gomock.Any()).Return(cr, nil).AnyTimes()
+	return oc
+}
+
+// expectWritePDV expects pdv to be encrypted and written into the file storage.
+func expectWritePDV(t *testing.T, cr *cryptomock.MockCrypto, fs *storagemock.MockFileStorage) {
+	cr.EXPECT().EncryptReader(gomock.Any()).Return(bytes.NewReader(testEncryptedData), int64(len(testEncryptedData)))
+	fs.EXPECT().Write(gomock.Any(), gomock.Any(), int64(len(testEncryptedData)), gomock.Any(), "binary/octet-stream", false).
+		DoAndReturn(func(_ context.Context, r io.Reader, _ int64, path, _ string, _ bool) (string, error) {
+			require.Contains(t, path, testOwner+"/pdv/")
+
+			b, err := ioutil.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, testEncryptedData, b)
+			return path, nil
+		})
+}
+
 func TestService_SavePDV(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -135,18 +163,19 @@ func TestService_SavePDV(t *testing.T) {
 	p := producermock.NewMockProducer(ctrl)
 	hades := hadesmock.NewMockHades(ctrl)
 
-	s := New(cr, fs, is, p, hades, rewardsMap, pdvRewardsInterval)
+	s := New(ownerCrypto(ctrl, cr), fs, is, p, hades, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
 
 	expectedID := uint64(time.Now().Unix())
 
-	cr.EXPECT().Encrypt(gomock.Any()).Return(testEncryptedData, nil)
+	expectWritePDV(t, cr, fs)
 
 	expectedMeta := &entities.PDVMeta{
 		ObjectTypes: map[schema.Type]uint16{
 			schema.PDVCookieType:   1,
 			schema.PDVLocationType: 1,
 		},
-		Reward: sdk.NewDecWithPrec(6, 6),
+		Reward:           sdk.NewDecWithPrec(6, 6),
+		RewardMapVersion: 1,
 	}
 
 	is.EXPECT().IsProfileBanned(gomock.Any(), testOwnerSdkAddr.String()).Return(false, nil)
@@ -163,7 +192,6 @@ func TestService_SavePDV(t *testing.T) {
 		Address: testOwner,
 		Meta:    expectedMeta,
 		Device:  testDevice,
-		Data:    testEncryptedData,
 	}))
 
 	id, meta, err := s.SavePDV(ctx, schema.NewPDVWrapper(testDevice, pdv), testOwnerSdkAddr)
@@ -172,6 +200,68 @@ func TestService_SavePDV(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestService_SavePDV_FraudCheckPolicy(t *testing.T) {
+	tt := []struct {
+		policy  FraudCheckPolicy
+		held    bool
+		produce bool
+		err     error
+	}{
+		{policy: FraudCheckFailOpen, produce: true},
+		{policy: FraudCheckReview, held: true},
+		{policy: FraudCheckFailClosed, err: ErrFraudCheckUnavailable},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(string(tc.policy), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fs := storagemock.NewMockFileStorage(ctrl)
+			is := storagemock.NewMockIndexStorage(ctrl)
+			cr := cryptomock.NewMockCrypto(ctrl)
+			p := producermock.NewMockProducer(ctrl)
+			hades := hadesmock.NewMockHades(ctrl)
+
+			s := New(ownerCrypto(ctrl, cr), fs, is, p, hades, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, tc.policy, 0)
+
+			expectedID := uint64(time.Now().Unix())
+
+			is.EXPECT().IsProfileBanned(gomock.Any(), testOwnerSdkAddr.String()).Return(false, nil)
+			hades.EXPECT().AntiFraud(ctx, gomock.Any()).Return(nil, hadesclient.ErrCircuitOpen)
+
+			if tc.err == nil {
+				expectWritePDV(t, cr, fs)
+				is.EXPECT().CreateFraudRecheckItem(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, item *storage.FraudRecheckItem) error {
+					require.Equal(t, testOwner, item.Address)
+					require.Equal(t, expectedID, item.ID)
+					require.Equal(t, tc.held, item.Held)
+
+					var msg producer.PDVMessage
+					require.NoError(t, json.Unmarshal(item.Message, &msg))
+					require.Empty(t, msg.Data)
+					return nil
+				})
+			}
+
+			if tc.produce {
+				p.EXPECT().Produce(ctx, gomock.Any())
+			}
+
+			id, meta, err := s.SavePDV(ctx, schema.NewPDVWrapper(testDevice, pdv), testOwnerSdkAddr)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, expectedID, id)
+			require.NotNil(t, meta)
+		})
+	}
+}
+
 func TestService_SavePDV_Blacklist(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -182,17 +272,26 @@ func TestService_SavePDV_Blacklist(t *testing.T) {
 	p := producermock.NewMockProducer(ctrl)
 	hades := hadesmock.NewMockHades(ctrl)
 
-	s := New(cr, fs, is, p, hades, rewardsMap, pdvRewardsInterval)
+	is.EXPECT().GetBlacklistEntryList(ctx).Return([]*storage.BlacklistEntry{
+		{ID: 1, Kind: storage.BlacklistKindCookieSource, Pattern: "youtube.com"},
+	}, nil)
+
+	blacklist := NewBlacklistCache(is)
+	require.NoError(t, blacklist.Refresh(ctx))
+
+	s := New(ownerCrypto(ctrl, cr), fs, is, p, hades, nil, rewards, nil, blacklist, pdvRewardsInterval, FraudCheckFailOpen, 0)
 
 	expectedID := uint64(time.Now().Unix())
 
-	cr.EXPECT().Encrypt(gomock.Any()).Return(testEncryptedData, nil)
+	expectWritePDV(t, cr, fs)
 
 	expectedMeta := &entities.PDVMeta{
 		ObjectTypes: map[schema.Type]uint16{
 			schema.PDVCookieType: 1,
 		},
-		Reward: sdk.ZeroDec(),
+		Reward:           sdk.ZeroDec(),
+		RewardMapVersion: 1,
+		Rejected:         []entities.RejectedPDV{{Index: 0, Rule: RejectedBlacklist}},
 	}
 
 	is.EXPECT().IsProfileBanned(gomock.Any(), testOwnerSdkAddr.String()).Return(false, nil)
@@ -209,7 +308,6 @@ func TestService_SavePDV_Blacklist(t *testing.T) {
 		Address: testOwner,
 		Meta:    expectedMeta,
 		Device:  testDevice,
-		Data:    testEncryptedData,
 	}))
 
 	id, meta, err := s.SavePDV(ctx, schema.NewPDVWrapper(testDevice, v1.PDV{
@@ -233,14 +331,47 @@ func TestService_SavePDV_Blacklist(t *testing.T) {
 	require.NoError(t, err)
 }
 
-func TestFloat64ToDecimal(t *testing.T) {
-	d, err := float64ToDecimal(0.5)
+func TestService_SavePDV_Refine(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fs := storagemock.NewMockFileStorage(ctrl)
+	is := storagemock.NewMockIndexStorage(ctrl)
+	cr := cryptomock.NewMockCrypto(ctrl)
+	p := producermock.NewMockProducer(ctrl)
+	hades := hadesmock.NewMockHades(ctrl)
+
+	refiner, err := refine.New(refine.Config{
+		Cookie:   refine.CookieConfig{TrackingNames: []string{"^_ga"}},
+		Location: refine.LocationConfig{RejectNullIsland: true},
+	})
 	require.NoError(t, err)
-	require.Equal(t, "0.500000000000000000", d.String())
 
-	d, err = float64ToDecimal(201.53)
+	s := New(ownerCrypto(ctrl, cr), fs, is, p, hades, nil, rewards, refiner, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
+
+	expectWritePDV(t, cr, fs)
+	is.EXPECT().IsProfileBanned(gomock.Any(), testOwner).Return(false, nil)
+	hades.EXPECT().AntiFraud(ctx, gomock.Any()).Return(&hadesclient.AntiFraudResponse{}, nil)
+	p.EXPECT().Produce(ctx, gomock.Any()).Return(nil)
+
+	_, meta, err := s.SavePDV(ctx, schema.NewPDVWrapper(testDevice, v1.PDV{
+		&v1.Cookie{
+			Source: schema.Source{Host: "decentr.net", Path: "/"},
+			Name:   "_ga",
+			Value:  "GA1.2.1234567890",
+			Domain: "*",
+			Path:   "*",
+		},
+		&v1.Location{},
+		pdv[0],
+	}), testOwnerSdkAddr)
 	require.NoError(t, err)
-	require.Equal(t, "201.530000000000000000", d.String())
+	// only the last cookie is rewarded
+	require.Equal(t, rewardsMap[schema.PDVCookieType].String(), meta.Reward.String())
+	require.Equal(t, []entities.RejectedPDV{
+		{Index: 0, Rule: "tracking_cookie"},
+		{Index: 1, Rule: "null_island"},
+	}, meta.Rejected)
 }
 
 func TestService_SavePDV_Profile(t *testing.T) {
@@ -268,7 +399,8 @@ func TestService_SavePDV_Profile(t *testing.T) {
 				ObjectTypes: map[schema.Type]uint16{
 					schema.PDVProfileType: 1,
 				},
-				Reward: sdk.ZeroDec(),
+				Reward:           sdk.ZeroDec(),
+				RewardMapVersion: 1,
 			},
 		},
 		{
@@ -278,7 +410,8 @@ func TestService_SavePDV_Profile(t *testing.T) {
 				ObjectTypes: map[schema.Type]uint16{
 					schema.PDVProfileType: 1,
 				},
-				Reward: sdk.NewDecWithPrec(6, 6),
+				Reward:           sdk.NewDecWithPrec(6, 6),
+				RewardMapVersion: 1,
 			},
 		},
 	}
@@ -296,7 +429,7 @@ func TestService_SavePDV_Profile(t *testing.T) {
 			p := producermock.NewMockProducer(ctrl)
 			hades := hadesmock.NewMockHades(ctrl)
 
-			s := New(cr, fs, is, p, hades, rewardsMap, pdvRewardsInterval)
+			s := New(ownerCrypto(ctrl, cr), fs, is, p, hades, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
 
 			is.EXPECT().GetProfile(ctx, testOwner).DoAndReturn(func(_ context.Context, _ string) (*storage.Profile, error) {
 				if tc.exist {
@@ -321,7 +454,7 @@ func TestService_SavePDV_Profile(t *testing.T) {
 
 			expectedID := uint64(time.Now().Unix())
 
-			cr.EXPECT().Encrypt(gomock.Any()).Return(testEncryptedData, nil)
+			expectWritePDV(t, cr, fs)
 
 			hades.EXPECT().AntiFraud(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, req *hadesclient.AntiFraudRequest) (*hadesclient.AntiFraudResponse, error) {
 				require.Equal(t, expectedID, req.ID)
@@ -335,7 +468,6 @@ func TestService_SavePDV_Profile(t *testing.T) {
 				Address: testOwner,
 				Meta:    tc.meta,
 				Device:  testDevice,
-				Data:    testEncryptedData,
 			}).Return(nil)
 
 			id, meta, err := s.SavePDV(ctx, schema.NewPDVWrapper(testDevice, pdv), testOwnerSdkAddr)
@@ -356,11 +488,17 @@ func TestService_SavePDV_EncryptError(t *testing.T) {
 	p := producermock.NewMockProducer(ctrl)
 	hades := hadesmock.NewMockHades(ctrl)
 
-	s := New(cr, fs, is, p, hades, rewardsMap, pdvRewardsInterval)
+	s := New(ownerCrypto(ctrl, cr), fs, is, p, hades, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
 
 	is.EXPECT().IsProfileBanned(gomock.Any(), testOwnerSdkAddr.String()).Return(false, nil)
+	hades.EXPECT().AntiFraud(ctx, gomock.Any()).Return(&hadesclient.AntiFraudResponse{}, nil)
 
-	cr.EXPECT().Encrypt(gomock.Any()).Return(nil, errTest)
+	cr.EXPECT().EncryptReader(gomock.Any()).Return(iotest.ErrReader(errTest), int64(-1))
+	fs.EXPECT().Write(ctx, gomock.Any(), int64(-1), getPDVFilePath(testOwner, uint64(time.Now().Unix())), "binary/octet-stream", false).
+		DoAndReturn(func(_ context.Context, r io.Reader, _ int64, _, _ string, _ bool) (string, error) {
+			_, err := ioutil.ReadAll(r)
+			return "", err
+		})
 
 	_, _, err := s.SavePDV(ctx, schema.NewPDVWrapper(testDevice, pdv), testOwnerSdkAddr)
 	require.Error(t, err)
@@ -377,12 +515,11 @@ func TestService_SavePDV_Fraud(t *testing.T) {
 	p := producermock.NewMockProducer(ctrl)
 	hades := hadesmock.NewMockHades(ctrl)
 
-	s := New(cr, fs, is, p, hades, rewardsMap, pdvRewardsInterval)
+	s := New(ownerCrypto(ctrl, cr), fs, is, p, hades, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
 
 	expectedID := uint64(time.Now().Unix())
 
-	cr.EXPECT().Encrypt(gomock.Any()).Return(testEncryptedData, nil)
-
+	// fraud pdv isn't written
 	hades.EXPECT().AntiFraud(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, req *hadesclient.AntiFraudRequest) (*hadesclient.AntiFraudResponse, error) {
 		require.Equal(t, expectedID, req.ID)
 		require.Equal(t, testOwner, req.Address)
@@ -390,7 +527,17 @@ func TestService_SavePDV_Fraud(t *testing.T) {
 		return &hadesclient.AntiFraudResponse{IsFraud: true}, nil
 	})
 
-	is.EXPECT().SetProfileBanned(gomock.Any(), testOwnerSdkAddr.String()).Return(nil)
+	is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.IndexStorage) error) error {
+		return f(is)
+	})
+	is.EXPECT().SetProfileBanned(gomock.Any(), testOwnerSdkAddr.String(), storage.BanSourceHades, "fraud detected").Return(nil)
+	is.EXPECT().CreateProfileBanPDV(gomock.Any(), testOwnerSdkAddr.String(), expectedID).Return(nil)
+	is.EXPECT().CreateAdminAuditItem(gomock.Any(), &storage.AdminAuditItem{
+		Actor:   "hades",
+		Action:  "ban",
+		Address: testOwnerSdkAddr.String(),
+		Reason:  fmt.Sprintf("fraud detected in pdv %d", expectedID),
+	}).Return(nil)
 	is.EXPECT().IsProfileBanned(gomock.Any(), testOwnerSdkAddr.String()).Return(false, nil)
 
 	id, meta, err := s.SavePDV(ctx, schema.NewPDVWrapper(testDevice, pdv), testOwnerSdkAddr)
@@ -409,11 +556,11 @@ func TestService_SavePDV_ProducerError(t *testing.T) {
 	p := producermock.NewMockProducer(ctrl)
 	hades := hadesmock.NewMockHades(ctrl)
 
-	s := New(cr, fs, is, p, hades, rewardsMap, pdvRewardsInterval)
+	s := New(ownerCrypto(ctrl, cr), fs, is, p, hades, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
 
 	is.EXPECT().IsProfileBanned(gomock.Any(), testOwnerSdkAddr.String()).Return(false, nil)
 
-	cr.EXPECT().Encrypt(gomock.Any()).Return(testEncryptedData, nil)
+	expectWritePDV(t, cr, fs)
 
 	hades.EXPECT().AntiFraud(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, req *hadesclient.AntiFraudRequest) (*hadesclient.AntiFraudResponse, error) {
 		require.Equal(t, testOwner, req.Address)
@@ -422,12 +569,35 @@ func TestService_SavePDV_ProducerError(t *testing.T) {
 	})
 
 	p.EXPECT().Produce(gomock.Any(), gomock.Any()).Return(errTest)
+	fs.EXPECT().Delete(gomock.Any(), getPDVFilePath(testOwner, uint64(time.Now().Unix()))).Return(nil)
 
 	_, _, err := s.SavePDV(ctx, schema.NewPDVWrapper(testDevice, pdv), testOwnerSdkAddr)
 	require.Error(t, err)
 	assert.True(t, errors.Is(err, errTest))
 }
 
+func TestService_SavePDV_FraudRecheckItemError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fs := storagemock.NewMockFileStorage(ctrl)
+	is := storagemock.NewMockIndexStorage(ctrl)
+	cr := cryptomock.NewMockCrypto(ctrl)
+	p := producermock.NewMockProducer(ctrl)
+	hades := hadesmock.NewMockHades(ctrl)
+
+	s := New(ownerCrypto(ctrl, cr), fs, is, p, hades, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckReview, 0)
+
+	is.EXPECT().IsProfileBanned(gomock.Any(), testOwnerSdkAddr.String()).Return(false, nil)
+	hades.EXPECT().AntiFraud(ctx, gomock.Any()).Return(nil, hadesclient.ErrCircuitOpen)
+	expectWritePDV(t, cr, fs)
+	is.EXPECT().CreateFraudRecheckItem(ctx, gomock.Any()).Return(errTest)
+	fs.EXPECT().Delete(gomock.Any(), getPDVFilePath(testOwner, uint64(time.Now().Unix()))).Return(nil)
+
+	_, _, err := s.SavePDV(ctx, schema.NewPDVWrapper(testDevice, pdv), testOwnerSdkAddr)
+	require.ErrorIs(t, err, errTest)
+}
+
 func TestService_ReceivePDV(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -438,7 +608,7 @@ func TestService_ReceivePDV(t *testing.T) {
 	p := producermock.NewMockProducer(ctrl)
 	hades := hadesmock.NewMockHades(ctrl)
 
-	s := New(cr, fs, is, p, hades, rewardsMap, pdvRewardsInterval)
+	s := New(ownerCrypto(ctrl, cr), fs, is, p, hades, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
 
 	fs.EXPECT().Read(ctx, getPDVFilePath(testOwner, testID)).Return(ioutil.NopCloser(bytes.NewReader(testEncryptedData)), nil)
 
@@ -465,7 +635,7 @@ func TestService_ReceivePDV_StorageError(t *testing.T) {
 	p := producermock.NewMockProducer(ctrl)
 	hades := hadesmock.NewMockHades(ctrl)
 
-	s := New(cr, fs, is, p, hades, rewardsMap, pdvRewardsInterval)
+	s := New(ownerCrypto(ctrl, cr), fs, is, p, hades, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
 
 	fs.EXPECT().Read(ctx, getPDVFilePath(testOwner, testID)).Return(nil, errTest)
 
@@ -485,7 +655,7 @@ func TestService_ReceivePDV_StorageError_NotFound(t *testing.T) {
 	p := producermock.NewMockProducer(ctrl)
 	hades := hadesmock.NewMockHades(ctrl)
 
-	s := New(cr, fs, is, p, hades, rewardsMap, pdvRewardsInterval)
+	s := New(ownerCrypto(ctrl, cr), fs, is, p, hades, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
 
 	fs.EXPECT().Read(ctx, getPDVFilePath(testOwner, testID)).Return(nil, storage.ErrNotFound)
 
@@ -505,7 +675,7 @@ func TestService_ReceivePDV_DecryptError(t *testing.T) {
 	p := producermock.NewMockProducer(ctrl)
 	hades := hadesmock.NewMockHades(ctrl)
 
-	s := New(cr, fs, is, p, hades, rewardsMap, pdvRewardsInterval)
+	s := New(ownerCrypto(ctrl, cr), fs, is, p, hades, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
 
 	fs.EXPECT().Read(ctx, getPDVFilePath(testOwner, testID)).Return(ioutil.NopCloser(bytes.NewReader(testEncryptedData)), nil)
 
@@ -517,6 +687,47 @@ func TestService_ReceivePDV_DecryptError(t *testing.T) {
 	assert.Nil(t, data)
 }
 
+func TestService_ReceivePDV_OwnerCryptoError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fs := storagemock.NewMockFileStorage(ctrl)
+	is := storagemock.NewMockIndexStorage(ctrl)
+	oc := cryptomock.NewMockOwnerCrypto(ctrl)
+	p := producermock.NewMockProducer(ctrl)
+	hades := hadesmock.NewMockHades(ctrl)
+
+	s := New(oc, fs, is, p, hades, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
+
+	fs.EXPECT().Read(ctx, getPDVFilePath(testOwner, testID)).Return(ioutil.NopCloser(bytes.NewReader(testEncryptedData)), nil)
+
+	oc.EXPECT().ForOwnerRead(gomock.Any(), testOwner).Return(nil, errTest)
+
+	data, err := s.ReceivePDV(ctx, testOwner, testID)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, errTest))
+	assert.Nil(t, data)
+}
+
+func TestService_ReceivePDV_KeyNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fs := storagemock.NewMockFileStorage(ctrl)
+	is := storagemock.NewMockIndexStorage(ctrl)
+	cr := cryptomock.NewMockCrypto(ctrl)
+
+	s := New(ownerCrypto(ctrl, cr), fs, is, nil, nil, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
+
+	fs.EXPECT().Read(ctx, getPDVFilePath(testOwner, testID)).Return(ioutil.NopCloser(bytes.NewReader(testEncryptedData)), nil)
+
+	// data key is deleted on reset
+	cr.EXPECT().Decrypt(gomock.Any()).Return(nil, crypto.ErrKeyNotFound)
+
+	_, err := s.ReceivePDV(ctx, testOwner, testID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
 func TestService_GetPDVMeta(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -527,7 +738,7 @@ func TestService_GetPDVMeta(t *testing.T) {
 	p := producermock.NewMockProducer(ctrl)
 	hades := hadesmock.NewMockHades(ctrl)
 
-	s := New(cr, fs, is, p, hades, rewardsMap, pdvRewardsInterval)
+	s := New(ownerCrypto(ctrl, cr), fs, is, p, hades, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
 
 	exp := &entities.PDVMeta{
 		ObjectTypes: map[schema.Type]uint16{
@@ -553,7 +764,7 @@ func TestService_GetPDVMeta_StorageError(t *testing.T) {
 	p := producermock.NewMockProducer(ctrl)
 	hades := hadesmock.NewMockHades(ctrl)
 
-	s := New(cr, fs, is, p, hades, rewardsMap, pdvRewardsInterval)
+	s := New(ownerCrypto(ctrl, cr), fs, is, p, hades, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
 
 	is.EXPECT().GetPDVMeta(gomock.Any(), testOwner, testID).Return(nil, errTest)
 
@@ -572,7 +783,7 @@ func TestService_GetPDVMeta_NotFound(t *testing.T) {
 	p := producermock.NewMockProducer(ctrl)
 	hades := hadesmock.NewMockHades(ctrl)
 
-	s := New(cr, fs, is, p, hades, rewardsMap, pdvRewardsInterval)
+	s := New(ownerCrypto(ctrl, cr), fs, is, p, hades, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
 
 	is.EXPECT().GetPDVMeta(gomock.Any(), testOwner, testID).Return(nil, storage.ErrNotFound)
 
@@ -595,7 +806,7 @@ func TestService_ListPDV(t *testing.T) {
 	p := producermock.NewMockProducer(ctrl)
 	hades := hadesmock.NewMockHades(ctrl)
 
-	s := New(cr, fs, is, p, hades, rewardsMap, pdvRewardsInterval)
+	s := New(ownerCrypto(ctrl, cr), fs, is, p, hades, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
 
 	is.EXPECT().ListPDV(gomock.Any(), "owner", uint64(5), uint16(10)).Return([]uint64{1, 2, 3}, nil)
 
@@ -604,6 +815,105 @@ func TestService_ListPDV(t *testing.T) {
 	require.Equal(t, []uint64{1, 2, 3}, l)
 }
 
+func TestService_ListPDVRewardsHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fs := storagemock.NewMockFileStorage(ctrl)
+	is := storagemock.NewMockIndexStorage(ctrl)
+	cr := cryptomock.NewMockCrypto(ctrl)
+	p := producermock.NewMockProducer(ctrl)
+	hades := hadesmock.NewMockHades(ctrl)
+
+	s := New(ownerCrypto(ctrl, cr), fs, is, p, hades, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
+
+	start, end := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	is.EXPECT().ListPDVRewardsHistory(gomock.Any(), "owner", uint64(5), uint16(10)).Return([]*storage.PDVRewardsHistoryItem{
+		{ID: 4, Address: "owner", Amount: 100, PeriodStart: start, PeriodEnd: end, TxHash: "tx", CreatedAt: end},
+	}, nil)
+
+	l, err := s.ListPDVRewardsHistory(ctx, "owner", 5, 10)
+	require.NoError(t, err)
+	require.Equal(t, []*entities.PDVRewardsHistoryItem{
+		{ID: 4, Amount: 100, PeriodStart: start, PeriodEnd: end, TxHash: "tx", CreatedAt: end},
+	}, l)
+}
+
+func TestService_ListBannedProfiles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := storagemock.NewMockIndexStorage(ctrl)
+
+	s := New(nil, nil, is, nil, nil, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
+
+	date := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	is.EXPECT().ListBannedProfiles(gomock.Any(), "from", uint16(10)).Return([]*storage.BannedProfile{
+		{Address: "owner", Source: storage.BanSourceHades, Reason: "fraud detected", BannedAt: date},
+	}, nil)
+
+	l, err := s.ListBannedProfiles(ctx, "from", 10)
+	require.NoError(t, err)
+	require.Equal(t, []*entities.BannedProfile{
+		{Address: "owner", Source: "hades", Reason: "fraud detected", BannedAt: date},
+	}, l)
+}
+
+func TestService_BanProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := storagemock.NewMockIndexStorage(ctrl)
+
+	s := New(nil, nil, is, nil, nil, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
+
+	is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.IndexStorage) error) error {
+		return f(is)
+	}).Times(2)
+	is.EXPECT().SetProfileBanned(gomock.Any(), "owner", storage.BanSourceManual, "spam").Return(nil)
+	is.EXPECT().CreateAdminAuditItem(gomock.Any(), &storage.AdminAuditItem{
+		Actor:   "admin",
+		Action:  "ban",
+		Address: "owner",
+		Reason:  "spam",
+	}).Return(nil)
+
+	require.NoError(t, s.BanProfile(ctx, "admin", "owner", "spam"))
+
+	is.EXPECT().SetProfileBanned(gomock.Any(), "owner", storage.BanSourceManual, "spam").Return(errTest)
+
+	require.ErrorIs(t, s.BanProfile(ctx, "admin", "owner", "spam"), errTest)
+}
+
+func TestService_UnbanProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := storagemock.NewMockIndexStorage(ctrl)
+
+	s := New(nil, nil, is, nil, nil, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
+
+	is.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.IndexStorage) error) error {
+		return f(is)
+	}).Times(2)
+	is.EXPECT().UnsetProfileBanned(gomock.Any(), "owner").Return(nil)
+	is.EXPECT().DeleteProfileBanPDV(gomock.Any(), "owner").Return(nil)
+	is.EXPECT().CreateAdminAuditItem(gomock.Any(), &storage.AdminAuditItem{
+		Actor:   "admin",
+		Action:  "unban",
+		Address: "owner",
+		Reason:  "appeal",
+	}).Return(nil)
+
+	require.NoError(t, s.UnbanProfile(ctx, "admin", "owner", "appeal"))
+
+	is.EXPECT().UnsetProfileBanned(gomock.Any(), "owner").Return(storage.ErrNotFound)
+
+	require.ErrorIs(t, s.UnbanProfile(ctx, "admin", "owner", "appeal"), ErrNotFound)
+}
+
 func TestService_GetProfiles(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -614,7 +924,7 @@ func TestService_GetProfiles(t *testing.T) {
 	p := producermock.NewMockProducer(ctrl)
 	hades := hadesmock.NewMockHades(ctrl)
 
-	s := New(cr, fs, is, p, hades, rewardsMap, pdvRewardsInterval)
+	s := New(ownerCrypto(ctrl, cr), fs, is, p, hades, nil, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
 
 	is.EXPECT().GetProfiles(ctx, []string{"1", "2"}).Return([]*storage.Profile{
 		{
@@ -669,14 +979,22 @@ func TestService_GetProfiles(t *testing.T) {
 	}, pp)
 }
 
-func TestService_GetRewardsMap(t *testing.T) {
-	rm := RewardMap{
-		"m": sdk.NewDecWithPrec(1, 6),
-		"t": sdk.NewDecWithPrec(2, 6),
+func TestService_GetRewards(t *testing.T) {
+	current := RewardMapVersion{
+		Version:       1,
+		EffectiveFrom: time.Now().UTC().Add(-time.Hour),
+		Rewards:       RewardMap{"m": sdk.NewDecWithPrec(1, 6)},
+	}
+	upcoming := RewardMapVersion{
+		Version:       2,
+		EffectiveFrom: time.Now().UTC().Add(time.Hour),
+		Rewards:       RewardMap{"m": sdk.NewDecWithPrec(2, 6)},
 	}
-	s := service{rewardMap: rm}
+	s := service{rewards: NewRewardSchedule(upcoming, current)}
 
-	require.EqualValues(t, rm, s.GetRewardsMap())
+	c, u := s.GetRewards()
+	require.Equal(t, current, c)
+	require.Equal(t, []RewardMapVersion{upcoming}, u)
 }
 
 func mustDate(s string) *types.Date {
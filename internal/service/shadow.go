@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Decentr-net/cerberus/internal/hades"
+	"github.com/Decentr-net/cerberus/internal/storage"
+	logging "github.com/Decentr-net/logrus/context"
+)
+
+// shadowTimeout is how long shadow antifraud check and verdict saving could take.
+const shadowTimeout = 10 * time.Second
+
+// checkShadow checks pdv with shadow antifraud and saves the verdict, the verdict doesn't affect pdv.
+// The check runs in the background, so it doesn't delay the response and isn't cancelled with the request.
+func (s *service) checkShadow(ctx context.Context, req *hades.AntiFraudRequest) {
+	log := logging.GetLogger(ctx).WithField("owner", req.Address).WithField("id", req.ID)
+
+	ctx, cancel := context.WithTimeout(logging.WithLogger(context.Background(), log), shadowTimeout)
+
+	go func() {
+		defer cancel()
+
+		start := time.Now()
+		resp, err := s.shadow.AntiFraud(ctx, req)
+
+		v := &storage.FraudVerdict{
+			Address:   req.Address,
+			ID:        req.ID,
+			Verdict:   storage.FraudVerdictClean,
+			LatencyMS: time.Since(start).Milliseconds(),
+		}
+
+		switch {
+		case err != nil:
+			log.WithError(err).Warn("failed to shadow anti fraud")
+			v.Verdict = storage.FraudVerdictError
+		case resp.IsFraud:
+			v.Verdict = storage.FraudVerdictFraud
+			v.Rule = resp.Rule
+		}
+
+		if err := s.is.CreateFraudVerdict(ctx, v); err != nil {
+			log.WithError(err).Error("failed to create fraud verdict")
+		}
+	}()
+}
+
+// ShadowReport compares shadow antifraud verdicts with live outcomes.
+type ShadowReport struct {
+	Since time.Time `json:"since"`
+	Total int       `json:"total"`
+	// Errors is a count of failed shadow checks, they aren't compared with live outcomes.
+	Errors     int `json:"errors"`
+	BothFraud  int `json:"both_fraud"`
+	BothClean  int `json:"both_clean"`
+	ShadowOnly int `json:"shadow_only"`
+	LiveOnly   int `json:"live_only"`
+
+	AvgLatencyMS int64 `json:"avg_latency_ms"`
+	MaxLatencyMS int64 `json:"max_latency_ms"`
+
+	// Rules is a count of shadow fraud verdicts by rule.
+	Rules map[string]int `json:"rules"`
+	// Mismatches are verdicts which differ from live outcomes.
+	Mismatches []*ShadowReportItem `json:"mismatches"`
+}
+
+// ShadowReportItem is a shadow verdict of pdv with the live outcome.
+type ShadowReportItem struct {
+	Address string `json:"address"`
+	ID      uint64 `json:"id"`
+	Verdict string `json:"verdict"`
+	Rule    string `json:"rule,omitempty"`
+	// LiveFraud is true when pdv triggered the owner's ban.
+	LiveFraud bool `json:"live_fraud"`
+	// Banned is true when the owner is banned now, e.g. by admin.
+	Banned    bool      `json:"banned"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MakeShadowReport compares shadow verdicts made since the time with live outcomes.
+func MakeShadowReport(ctx context.Context, is storage.IndexStorage, since time.Time) (*ShadowReport, error) {
+	list, err := is.GetFraudVerdictOutcomeList(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fraud verdict outcome list: %w", err)
+	}
+
+	r := ShadowReport{
+		Since:      since,
+		Total:      len(list),
+		Rules:      make(map[string]int),
+		Mismatches: []*ShadowReportItem{},
+	}
+
+	var latency int64
+	for _, v := range list {
+		latency += v.LatencyMS
+		if v.LatencyMS > r.MaxLatencyMS {
+			r.MaxLatencyMS = v.LatencyMS
+		}
+
+		shadowFraud := v.Verdict == storage.FraudVerdictFraud
+
+		switch {
+		case v.Verdict == storage.FraudVerdictError:
+			r.Errors++
+			continue
+		case shadowFraud && v.LiveFraud:
+			r.BothFraud++
+		case shadowFraud:
+			r.ShadowOnly++
+		case v.LiveFraud:
+			r.LiveOnly++
+		default:
+			r.BothClean++
+		}
+
+		if shadowFraud {
+			r.Rules[v.Rule]++
+		}
+
+		if shadowFraud != v.LiveFraud {
+			r.Mismatches = append(r.Mismatches, &ShadowReportItem{
+				Address:   v.Address,
+				ID:        v.ID,
+				Verdict:   string(v.Verdict),
+				Rule:      v.Rule,
+				LiveFraud: v.LiveFraud,
+				Banned:    v.Banned,
+				CreatedAt: v.CreatedAt,
+			})
+		}
+	}
+
+	if len(list) > 0 {
+		r.AvgLatencyMS = latency / int64(len(list))
+	}
+
+	return &r, nil
+}
+
+// WriteCSV writes report's mismatches as csv.
+func (r *ShadowReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"address", "id", "verdict", "rule", "live_fraud", "banned", "created_at"}); err != nil {
+		return err
+	}
+
+	for _, v := range r.Mismatches {
+		if err := cw.Write([]string{
+			v.Address,
+			strconv.FormatUint(v.ID, 10),
+			v.Verdict,
+			v.Rule,
+			strconv.FormatBool(v.LiveFraud),
+			strconv.FormatBool(v.Banned),
+			v.CreatedAt.Format(time.RFC3339),
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cryptomock "github.com/Decentr-net/cerberus/internal/crypto/mock"
+	hadesclient "github.com/Decentr-net/cerberus/internal/hades"
+	hadesmock "github.com/Decentr-net/cerberus/internal/hades/mock"
+	producermock "github.com/Decentr-net/cerberus/internal/producer/mock"
+	"github.com/Decentr-net/cerberus/internal/storage"
+	storagemock "github.com/Decentr-net/cerberus/internal/storage/mock"
+	"github.com/Decentr-net/cerberus/pkg/schema"
+)
+
+func TestService_SavePDV_Shadow(t *testing.T) {
+	tt := []struct {
+		name    string
+		resp    *hadesclient.AntiFraudResponse
+		err     error
+		verdict storage.FraudVerdictResult
+		rule    string
+	}{
+		{
+			name:    "fraud",
+			resp:    &hadesclient.AntiFraudResponse{IsFraud: true, Rule: "rule"},
+			verdict: storage.FraudVerdictFraud,
+			rule:    "rule",
+		},
+		{
+			name:    "clean",
+			resp:    &hadesclient.AntiFraudResponse{},
+			verdict: storage.FraudVerdictClean,
+		},
+		{
+			name:    "error",
+			err:     errTest,
+			verdict: storage.FraudVerdictError,
+		},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fs := storagemock.NewMockFileStorage(ctrl)
+			is := storagemock.NewMockIndexStorage(ctrl)
+			cr := cryptomock.NewMockCrypto(ctrl)
+			p := producermock.NewMockProducer(ctrl)
+			hades := hadesmock.NewMockHades(ctrl)
+			shadow := hadesmock.NewMockHades(ctrl)
+
+			s := New(ownerCrypto(ctrl, cr), fs, is, p, hades, shadow, rewards, nil, NewBlacklistCache(is), pdvRewardsInterval, FraudCheckFailOpen, 0)
+
+			expectWritePDV(t, cr, fs)
+			is.EXPECT().IsProfileBanned(gomock.Any(), testOwner).Return(false, nil)
+			hades.EXPECT().AntiFraud(ctx, gomock.Any()).Return(&hadesclient.AntiFraudResponse{}, nil)
+			// shadow check isn't bound to the request context
+			shadow.EXPECT().AntiFraud(gomock.Any(), gomock.Any()).Return(tc.resp, tc.err)
+
+			done := make(chan struct{})
+			is.EXPECT().CreateFraudVerdict(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, v *storage.FraudVerdict) error {
+				defer close(done)
+
+				assert.Equal(t, testOwner, v.Address)
+				assert.NotZero(t, v.ID)
+				assert.Equal(t, tc.verdict, v.Verdict)
+				assert.Equal(t, tc.rule, v.Rule)
+				return nil
+			})
+
+			// shadow verdict doesn't affect pdv
+			p.EXPECT().Produce(ctx, gomock.Any()).Return(nil)
+
+			_, _, err := s.SavePDV(ctx, schema.NewPDVWrapper(testDevice, pdv), testOwnerSdkAddr)
+			require.NoError(t, err)
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("shadow verdict isn't saved")
+			}
+		})
+	}
+}
+
+func TestMakeShadowReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	is := storagemock.NewMockIndexStorage(ctrl)
+
+	since := time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	verdict := func(id uint64, verdict storage.FraudVerdictResult, rule string, latency int64, liveFraud bool) *storage.FraudVerdictOutcome {
+		return &storage.FraudVerdictOutcome{
+			FraudVerdict: storage.FraudVerdict{
+				Address:   "address",
+				ID:        id,
+				Verdict:   verdict,
+				Rule:      rule,
+				LatencyMS: latency,
+				CreatedAt: since,
+			},
+			LiveFraud: liveFraud,
+		}
+	}
+
+	is.EXPECT().GetFraudVerdictOutcomeList(ctx, since).Return([]*storage.FraudVerdictOutcome{
+		verdict(1, storage.FraudVerdictFraud, "rule1", 10, true),
+		verdict(2, storage.FraudVerdictFraud, "rule2", 20, false),
+		verdict(3, storage.FraudVerdictClean, "", 30, true),
+		verdict(4, storage.FraudVerdictClean, "", 40, false),
+		verdict(5, storage.FraudVerdictError, "", 100, true),
+	}, nil)
+
+	r, err := MakeShadowReport(ctx, is, since)
+	require.NoError(t, err)
+	require.Equal(t, &ShadowReport{
+		Since:        since,
+		Total:        5,
+		Errors:       1,
+		BothFraud:    1,
+		BothClean:    1,
+		ShadowOnly:   1,
+		LiveOnly:     1,
+		AvgLatencyMS: 40,
+		MaxLatencyMS: 100,
+		Rules:        map[string]int{"rule1": 1, "rule2": 1},
+		Mismatches: []*ShadowReportItem{
+			{Address: "address", ID: 2, Verdict: "fraud", Rule: "rule2", CreatedAt: since},
+			{Address: "address", ID: 3, Verdict: "clean", LiveFraud: true, CreatedAt: since},
+		},
+	}, r)
+
+	var b bytes.Buffer
+	require.NoError(t, r.WriteCSV(&b))
+	require.Equal(t, `address,id,verdict,rule,live_fraud,banned,created_at
+address,2,fraud,rule2,false,false,2022-07-01T00:00:00Z
+address,3,clean,,true,false,2022-07-01T00:00:00Z
+`, b.String())
+}
@@ -14,6 +14,9 @@ import (
 // ErrNotFound means that file is not found.
 var ErrNotFound = errors.New("not found")
 
+// ErrAlreadyExists means that object already exists.
+var ErrAlreadyExists = errors.New("already exists")
+
 // FileStorage is interface which provides access to user's data.
 type FileStorage interface {
 	health.Pinger
@@ -21,5 +24,6 @@ type FileStorage interface {
 	Read(ctx context.Context, path string) (io.ReadCloser, error)
 	Write(ctx context.Context, data io.Reader, size int64, path string, contentType string, isPublicRead bool) (string, error)
 
+	Delete(ctx context.Context, path string) error
 	DeleteData(ctx context.Context, address string) error
 }
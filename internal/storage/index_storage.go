@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
 	"github.com/Decentr-net/cerberus/internal/entities"
 )
 
@@ -18,38 +20,225 @@ type IndexStorage interface {
 	GetProfile(ctx context.Context, addr string) (*Profile, error)
 	GetProfiles(ctx context.Context, addr []string) ([]*Profile, error)
 	SetProfile(ctx context.Context, p *SetProfileParams) error
-	SetProfileBanned(ctx context.Context, addr string) error
+	SetProfileBanned(ctx context.Context, addr string, source BanSource, reason string) error
+	UnsetProfileBanned(ctx context.Context, addr string) error
 	IsProfileBanned(ctx context.Context, addr string) (bool, error)
+	ListBannedProfiles(ctx context.Context, from string, limit uint16) ([]*BannedProfile, error)
 	DeleteProfile(ctx context.Context, addr string) error
 
 	ListPDV(ctx context.Context, owner string, from uint64, limit uint16) ([]uint64, error)
+	ListAllPDV(ctx context.Context, after *PDVIndex, limit uint16) ([]*PDVIndex, error)
 	DeletePDV(ctx context.Context, owner string) error
 
 	GetPDVMeta(ctx context.Context, address string, id uint64) (*entities.PDVMeta, error)
 	SetPDVMeta(ctx context.Context, address string, id uint64, tx string, device string, m *entities.PDVMeta) error
 
-	GetPDVDelta(ctx context.Context, address string) (float64, error)
-	GetPDVTotalDelta(ctx context.Context) (float64, error)
-	GetPDVDeltaList(ctx context.Context) ([]*PDVDelta, error)
+	GetPDVDelta(ctx context.Context, address string) (sdk.Dec, error)
+	GetPDVTotalDelta(ctx context.Context) (sdk.Dec, error)
+	GetPDVDeltaList(ctx context.Context, since, until time.Time) ([]*PDVDelta, error)
 
-	CreateRewardsQueueItem(ctx context.Context, addr string, reward int64) error
+	CreateRewardsQueueItem(ctx context.Context, addr string, reward int64, periodStart, periodEnd time.Time) error
 	GetRewardsQueueItemList(ctx context.Context) ([]*RewardsQueueItem, error)
 	DeleteRewardsQueueItem(ctx context.Context, addr string) error
+	SetRewardsQueueItemsSent(ctx context.Context, addrs []string, sentAt time.Time) error
+	SetRewardsQueueItemsTx(ctx context.Context, addrs []string, tx string) error
+	ResetRewardsQueueItems(ctx context.Context, addrs []string) error
+
+	CreatePDVRewardsCarryOverItem(ctx context.Context, addr string, delta sdk.Dec) error
+	DeletePDVRewardsCarryOverList(ctx context.Context) error
+
+	CreatePDVRewardsHistoryItem(ctx context.Context, item *PDVRewardsHistoryItem) error
+	ListPDVRewardsHistory(ctx context.Context, addr string, from uint64, limit uint16) ([]*PDVRewardsHistoryItem, error)
+
+	CreateProfileBanPDV(ctx context.Context, addr string, id uint64) error
+	ListProfileBanPDV(ctx context.Context, addr string) ([]uint64, error)
+	DeleteProfileBanPDV(ctx context.Context, addr string) error
+
+	CreateAdminAuditItem(ctx context.Context, item *AdminAuditItem) error
+
+	GetDataKey(ctx context.Context, owner string) ([]byte, error)
+	CreateDataKey(ctx context.Context, owner string, key []byte) error
+	ListDataKeys(ctx context.Context, after string, limit uint16) ([]*DataKey, error)
+	UpdateDataKey(ctx context.Context, owner string, old, key []byte) error
+	DeleteDataKey(ctx context.Context, owner string) error
+
+	CreateQuarantineItem(ctx context.Context, body, reason string, receiveCount int) error
+	GetQuarantineItemList(ctx context.Context) ([]*QuarantineItem, error)
+	DeleteQuarantineItem(ctx context.Context, id uint64) error
+
+	CreateFraudRecheckItem(ctx context.Context, item *FraudRecheckItem) error
+	GetFraudRecheckItemList(ctx context.Context, limit uint16, now time.Time) ([]*FraudRecheckItem, error)
+	DeleteFraudRecheckItem(ctx context.Context, address string, id uint64) error
+	PostponeFraudRecheckItem(ctx context.Context, address string, id uint64, nextAttemptAt time.Time, reason string) error
+	FailFraudRecheckItem(ctx context.Context, address string, id uint64, reason string) error
+
+	GetPDVFingerprintList(ctx context.Context, address string, fingerprints [][]byte, since time.Time) ([][]byte, error)
+	SetPDVFingerprints(ctx context.Context, address string, fingerprints [][]byte) error
+	DeletePDVFingerprints(ctx context.Context, before time.Time) error
+
+	CreateFraudVerdict(ctx context.Context, v *FraudVerdict) error
+	GetFraudVerdictOutcomeList(ctx context.Context, since time.Time) ([]*FraudVerdictOutcome, error)
+
+	GetBlacklistEntryList(ctx context.Context) ([]*BlacklistEntry, error)
+	CreateBlacklistEntry(ctx context.Context, e *BlacklistEntry) (uint64, error)
+	DeleteBlacklistEntry(ctx context.Context, id uint64) (*BlacklistEntry, error)
+
+	CreateRewardTx(ctx context.Context, items []*RewardTxItem) (uint64, error)
+	SetRewardTxStatus(ctx context.Context, id uint64, status RewardTxStatus, txHash, reason string) error
+	HasActiveRewardTx(ctx context.Context, address string, id uint64) (bool, error)
+	GetUnsettledRewardTxList(ctx context.Context, before time.Time) ([]*RewardTx, error)
 
 	GetPDVRewardsDistributedDate(ctx context.Context) (time.Time, error)
 	SetPDVRewardsDistributedDate(ctx context.Context, date time.Time) error
 }
 
+// PDVIndex ...
+type PDVIndex struct {
+	Owner string `db:"owner"`
+	ID    uint64 `db:"id"`
+}
+
 // PDVDelta ...
 type PDVDelta struct {
 	Address string
-	Delta   float64
+	Delta   sdk.Dec
+	Banned  bool
+	// AccountCreatedAt is a creation date of the owner's profile, it's nil if the profile doesn't exist.
+	AccountCreatedAt *time.Time
 }
 
 // RewardsQueueItem ...
 type RewardsQueueItem struct {
+	Address     string    `db:"address"`
+	Reward      int64     `db:"reward"`
+	PeriodStart time.Time `db:"period_start"`
+	PeriodEnd   time.Time `db:"period_end"`
+	// TxHash is a hash of stakes tx which sent the reward, it's empty if tx isn't sent or broadcast result is unknown.
+	TxHash string `db:"tx_hash"`
+	// SentAt is a time when the reward was being sent, it's nil if the reward isn't sent yet.
+	SentAt *time.Time `db:"sent_at"`
+}
+
+// PDVRewardsHistoryItem is a distributed PDV reward.
+type PDVRewardsHistoryItem struct {
+	ID          uint64    `db:"id"`
+	Address     string    `db:"address"`
+	Amount      int64     `db:"amount"`
+	PeriodStart time.Time `db:"period_start"`
+	PeriodEnd   time.Time `db:"period_end"`
+	TxHash      string    `db:"tx_hash"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// DataKey is owner's data key wrapped by key provider.
+type DataKey struct {
+	Owner string `db:"owner"`
+	Key   []byte `db:"key"`
+}
+
+// QuarantineItem is a message which can't be processed.
+type QuarantineItem struct {
+	ID           uint64    `db:"id"`
+	Body         string    `db:"body"`
+	Reason       string    `db:"reason"`
+	ReceiveCount int       `db:"receive_count"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+// FraudRecheckItem is a pdv which skipped antifraud check.
+type FraudRecheckItem struct {
+	Address string `db:"address"`
+	ID      uint64 `db:"pdv_id"`
+	// Held is true when pdv message is produced only after the check.
+	Held bool `db:"held"`
+	// Message is pdv message with encrypted data.
+	Message []byte `db:"message"`
+	// Attempts is count of failed rechecks.
+	Attempts  int       `db:"attempts"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// FraudVerdictResult is a result of antifraud check.
+type FraudVerdictResult string
+
+const (
+	// FraudVerdictClean means that fraud isn't detected.
+	FraudVerdictClean FraudVerdictResult = "clean"
+	// FraudVerdictFraud means that fraud is detected.
+	FraudVerdictFraud FraudVerdictResult = "fraud"
+	// FraudVerdictError means that antifraud check failed.
+	FraudVerdictError FraudVerdictResult = "error"
+)
+
+// FraudVerdict is a result of shadow antifraud check which doesn't affect pdv.
+type FraudVerdict struct {
+	Address string             `db:"address"`
+	ID      uint64             `db:"pdv_id"`
+	Verdict FraudVerdictResult `db:"verdict"`
+	// Rule is a name of the rule which detected fraud.
+	Rule      string    `db:"rule"`
+	LatencyMS int64     `db:"latency_ms"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// FraudVerdictOutcome is a shadow verdict with the live outcome of pdv.
+type FraudVerdictOutcome struct {
+	FraudVerdict
+	// LiveFraud is true when pdv triggered the owner's ban.
+	LiveFraud bool `db:"live_fraud"`
+	// Banned is true when the owner is banned.
+	Banned bool `db:"banned"`
+}
+
+// BlacklistKind is a kind of blacklisted pdv attribute.
+type BlacklistKind string
+
+const (
+	// BlacklistKindCookieSource is a host of the page where cookie was set.
+	BlacklistKindCookieSource BlacklistKind = "cookie_source"
+	// BlacklistKindSearchEngine is a search engine of search history.
+	BlacklistKindSearchEngine BlacklistKind = "search_engine"
+)
+
+// BlacklistEntry is a pattern of worthless pdv attribute.
+type BlacklistEntry struct {
+	ID      uint64        `db:"id"`
+	Kind    BlacklistKind `db:"kind"`
+	Pattern string        `db:"pattern"`
+	// CreatedBy is a name of the admin who added the entry.
+	CreatedBy string    `db:"created_by"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// RewardTxStatus is a status of rewards distribution transaction.
+type RewardTxStatus string
+
+const (
+	// RewardTxPending is a status of transaction which is going to be broadcast.
+	RewardTxPending RewardTxStatus = "pending"
+	// RewardTxBroadcast is a status of broadcast transaction which result isn't saved yet.
+	RewardTxBroadcast RewardTxStatus = "broadcast"
+	// RewardTxConfirmed is a status of transaction which is included into the blockchain and which result is saved.
+	RewardTxConfirmed RewardTxStatus = "confirmed"
+	// RewardTxFailed is a status of transaction which didn't reward anyone.
+	RewardTxFailed RewardTxStatus = "failed"
+)
+
+// RewardTx is a rewards distribution transaction from the ledger.
+type RewardTx struct {
+	ID        uint64
+	TxHash    string
+	Status    RewardTxStatus
+	UpdatedAt time.Time
+	Items     []*RewardTxItem
+}
+
+// RewardTxItem is a pdv rewarded by transaction.
+type RewardTxItem struct {
 	Address string
-	Reward  int64
+	ID      uint64
+	Device  string
+	Meta    *entities.PDVMeta
 }
 
 // Profile ...
@@ -67,6 +256,33 @@ type Profile struct {
 	CreatedAt time.Time
 }
 
+// BanSource is a source of the profile ban.
+type BanSource string
+
+const (
+	// BanSourceHades is a source of bans made automatically for fraud detected by Hades.
+	BanSourceHades BanSource = "hades"
+	// BanSourceManual is a source of bans made by admins.
+	BanSourceManual BanSource = "manual"
+)
+
+// BannedProfile is a ban of the profile.
+type BannedProfile struct {
+	Address  string    `db:"address"`
+	Source   BanSource `db:"ban_source"`
+	Reason   string    `db:"ban_reason"`
+	BannedAt time.Time `db:"banned_at"`
+}
+
+// AdminAuditItem is an action made with the profile.
+type AdminAuditItem struct {
+	// Actor is a name of the admin, or hades for automatic bans.
+	Actor   string `db:"actor"`
+	Action  string `db:"action"`
+	Address string `db:"address"`
+	Reason  string `db:"reason"`
+}
+
 // SetProfileParams ...
 type SetProfileParams struct {
 	Address   string
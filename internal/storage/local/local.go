@@ -0,0 +1,176 @@
+// Package local contains implementation FileStorage interface with local filesystem.
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Decentr-net/cerberus/internal/storage"
+)
+
+const (
+	dirPerm  = 0o700
+	tempName = ".tmp-*"
+
+	// nestedSuffix is a suffix of keys which are nested under another object in s3 (e.g. image thumbnails).
+	nestedSuffix = "/thumb"
+)
+
+var _ storage.FileStorage = &local{}
+
+var errInvalidPath = errors.New("invalid path")
+
+type local struct {
+	root string
+}
+
+// NewStorage returns local filesystem implementation of FileStorage interface.
+// Files are stored under root directory with the same layout as keys in s3 storage except nested objects (see filepath).
+func NewStorage(root string) (storage.FileStorage, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	logrus.WithField("root", root).Debug("create local storage root")
+	if err := os.MkdirAll(root, dirPerm); err != nil {
+		return nil, fmt.Errorf("failed to create root directory: %w", err)
+	}
+
+	return &local{
+		root: root,
+	}, nil
+}
+
+func (s local) Ping(_ context.Context) error {
+	fi, err := os.Stat(s.root)
+	if err != nil || !fi.IsDir() {
+		return errors.New("local storage root is not accessible") // nolint:goerr113
+	}
+	return nil
+}
+
+// Read returns ReadCloser with file content from local storage.
+func (s local) Read(_ context.Context, path string) (io.ReadCloser, error) {
+	p, err := s.filepath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, storage.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	if fi, err := f.Stat(); err != nil || fi.IsDir() {
+		f.Close() // nolint
+		return nil, storage.ErrNotFound
+	}
+
+	return f, nil
+}
+
+// Write puts file into local storage.
+// File is written into temporary file and renamed then, so readers never see partially written data.
+// Negative size means that size is unknown.
+func (s local) Write(ctx context.Context, r io.Reader, size int64, path string, _ string, _ bool) (string, error) {
+	p, err := s.filepath(path)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), dirPerm); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p), tempName)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // nolint
+
+	if err := func() error {
+		defer tmp.Close() // nolint
+
+		n, err := io.Copy(tmp, r)
+		if err != nil {
+			return fmt.Errorf("failed to write data: %w", err)
+		}
+
+		if size >= 0 && n != size {
+			return fmt.Errorf("size mismatch: expected %d, got %d", size, n) // nolint:goerr113
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		return tmp.Sync()
+	}(); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return "", fmt.Errorf("failed to rename temporary file: %w", err)
+	}
+
+	return fmt.Sprintf("file://%s", filepath.ToSlash(p)), nil
+}
+
+// Delete ...
+func (s local) Delete(_ context.Context, path string) error {
+	p, err := s.filepath(path)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// DeleteData ...
+func (s local) DeleteData(_ context.Context, address string) error {
+	if address == "" || strings.ContainsAny(address, `/\`) {
+		return errInvalidPath
+	}
+
+	p, err := s.filepath(address)
+	if err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(p); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", address, err)
+	}
+
+	return nil
+}
+
+// filepath converts storage path into path on filesystem and checks it doesn't leave the root.
+// A file can't be a directory at the same time, so nested object x/thumb is stored as x.thumb next to x.
+func (s local) filepath(path string) (string, error) {
+	if strings.HasSuffix(path, nestedSuffix) {
+		path = strings.TrimSuffix(path, nestedSuffix) + "." + strings.TrimPrefix(nestedSuffix, "/")
+	}
+
+	p := filepath.Join(s.root, filepath.FromSlash(path))
+	if !strings.HasPrefix(p, s.root+string(filepath.Separator)) {
+		return "", errInvalidPath
+	}
+
+	return p, nil
+}
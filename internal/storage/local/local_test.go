@@ -0,0 +1,196 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Decentr-net/cerberus/internal/storage"
+)
+
+var ctx = context.Background()
+
+func TestLocal_Write_Read(t *testing.T) {
+	s, err := NewStorage(t.TempDir())
+	require.NoError(t, err)
+
+	text := []byte("cerberus")
+
+	path, err := s.Write(ctx, bytes.NewReader(text), 8, "owner/pdv/fffffffffffffffe", "binary/octet-stream", false)
+	require.NoError(t, err)
+	require.NotEmpty(t, path)
+
+	rc, err := s.Read(ctx, "owner/pdv/fffffffffffffffe")
+	require.NoError(t, err)
+
+	b, err := ioutil.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, text, b)
+
+	assert.NoError(t, rc.Close())
+}
+
+func TestLocal_Write_UnknownSize(t *testing.T) {
+	s, err := NewStorage(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = s.Write(ctx, strings.NewReader("example"), -1, "file", "image/jpeg", true)
+	require.NoError(t, err)
+
+	rc, err := s.Read(ctx, "file")
+	require.NoError(t, err)
+	defer rc.Close()
+
+	b, err := ioutil.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, "example", string(b))
+}
+
+func TestLocal_Write_SizeMismatch(t *testing.T) {
+	root := t.TempDir()
+
+	s, err := NewStorage(root)
+	require.NoError(t, err)
+
+	_, err = s.Write(ctx, strings.NewReader("example"), 100, "file", "image/jpeg", false)
+	require.Error(t, err)
+
+	_, err = s.Read(ctx, "file")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	// temporary file should be removed
+	ff, err := ioutil.ReadDir(root)
+	require.NoError(t, err)
+	require.Empty(t, ff)
+}
+
+func TestLocal_Write_Overwrite(t *testing.T) {
+	s, err := NewStorage(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = s.Write(ctx, strings.NewReader("first"), 5, "file", "image/jpeg", false)
+	require.NoError(t, err)
+	_, err = s.Write(ctx, strings.NewReader("second"), 6, "file", "image/jpeg", false)
+	require.NoError(t, err)
+
+	rc, err := s.Read(ctx, "file")
+	require.NoError(t, err)
+	defer rc.Close()
+
+	b, err := ioutil.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(b))
+}
+
+func TestLocal_Read_FileNotFound(t *testing.T) {
+	s, err := NewStorage(t.TempDir())
+	require.NoError(t, err)
+
+	rc, err := s.Read(ctx, "not_found")
+	assert.Nil(t, rc)
+	assert.Equal(t, storage.ErrNotFound, err)
+}
+
+func TestLocal_InvalidPath(t *testing.T) {
+	s, err := NewStorage(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = s.Write(ctx, strings.NewReader("example"), 7, "../file", "image/jpeg", false)
+	require.ErrorIs(t, err, errInvalidPath)
+
+	_, err = s.Read(ctx, "../../etc/passwd")
+	require.ErrorIs(t, err, errInvalidPath)
+
+	require.ErrorIs(t, s.DeleteData(ctx, ".."), errInvalidPath)
+	require.ErrorIs(t, s.DeleteData(ctx, ""), errInvalidPath)
+}
+
+func TestLocal_Delete(t *testing.T) {
+	s, err := NewStorage(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = s.Write(ctx, bytes.NewReader([]byte("cerberus")), 8, "owner/pdv/0000000000000001", "binary/octet-stream", false)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Delete(ctx, "owner/pdv/0000000000000001"))
+
+	_, err = s.Read(ctx, "owner/pdv/0000000000000001")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	// deleting of missing file isn't an error
+	require.NoError(t, s.Delete(ctx, "owner/pdv/0000000000000001"))
+}
+
+func TestLocal_DeleteData(t *testing.T) {
+	root := t.TempDir()
+
+	s, err := NewStorage(root)
+	require.NoError(t, err)
+
+	text := []byte("cerberus")
+
+	for i := 0; i < 100; i++ {
+		_, err := s.Write(ctx, bytes.NewReader(text), 8, fmt.Sprintf("owner/pdv/%016x", i), "binary/octet-stream", false)
+		require.NoError(t, err)
+	}
+	_, err = s.Write(ctx, bytes.NewReader(text), 8, "owner2/pdv/0000000000000001", "binary/octet-stream", false)
+	require.NoError(t, err)
+
+	l, err := ioutil.ReadDir(filepath.Join(root, "owner", "pdv"))
+	require.NoError(t, err)
+	require.Len(t, l, 100)
+
+	require.NoError(t, s.DeleteData(ctx, "owner"))
+
+	_, err = os.Stat(filepath.Join(root, "owner"))
+	require.True(t, os.IsNotExist(err))
+
+	rc, err := s.Read(ctx, "owner2/pdv/0000000000000001")
+	require.NoError(t, err)
+	assert.NoError(t, rc.Close())
+}
+
+func TestLocal_Ping(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "storage")
+
+	s, err := NewStorage(root)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Ping(ctx))
+
+	require.NoError(t, os.RemoveAll(root))
+	require.Error(t, s.Ping(ctx))
+}
+
+func TestLocal_Write_Nested(t *testing.T) {
+	root := t.TempDir()
+
+	s, err := NewStorage(root)
+	require.NoError(t, err)
+
+	_, err = s.Write(ctx, strings.NewReader("hd"), 2, "owner/image", "image/jpeg", true)
+	require.NoError(t, err)
+	_, err = s.Write(ctx, strings.NewReader("thumb"), 5, "owner/image/thumb", "image/jpeg", true)
+	require.NoError(t, err)
+
+	for path, expected := range map[string]string{"owner/image": "hd", "owner/image/thumb": "thumb"} {
+		rc, err := s.Read(ctx, path)
+		require.NoError(t, err)
+
+		b, err := ioutil.ReadAll(rc)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(b))
+		assert.NoError(t, rc.Close())
+	}
+
+	_, err = os.Stat(filepath.Join(root, "owner", "image.thumb"))
+	require.NoError(t, err)
+}
@@ -78,6 +78,20 @@ func (mr *MockFileStorageMockRecorder) Write(ctx, data, size, path, contentType,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockFileStorage)(nil).Write), ctx, data, size, path, contentType, isPublicRead)
 }
 
+// Delete mocks base method
+func (m *MockFileStorage) Delete(ctx context.Context, path string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, path)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockFileStorageMockRecorder) Delete(ctx, path interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFileStorage)(nil).Delete), ctx, path)
+}
+
 // DeleteData mocks base method
 func (m *MockFileStorage) DeleteData(ctx context.Context, address string) error {
 	m.ctrl.T.Helper()
@@ -8,6 +8,7 @@ import (
 	context "context"
 	entities "github.com/Decentr-net/cerberus/internal/entities"
 	storage "github.com/Decentr-net/cerberus/internal/storage"
+	types "github.com/cosmos/cosmos-sdk/types"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 	time "time"
@@ -124,17 +125,31 @@ func (mr *MockIndexStorageMockRecorder) SetProfile(ctx, p interface{}) *gomock.C
 }
 
 // SetProfileBanned mocks base method
-func (m *MockIndexStorage) SetProfileBanned(ctx context.Context, addr string) error {
+func (m *MockIndexStorage) SetProfileBanned(ctx context.Context, addr string, source storage.BanSource, reason string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetProfileBanned", ctx, addr)
+	ret := m.ctrl.Call(m, "SetProfileBanned", ctx, addr, source, reason)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetProfileBanned indicates an expected call of SetProfileBanned
-func (mr *MockIndexStorageMockRecorder) SetProfileBanned(ctx, addr interface{}) *gomock.Call {
+func (mr *MockIndexStorageMockRecorder) SetProfileBanned(ctx, addr, source, reason interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProfileBanned", reflect.TypeOf((*MockIndexStorage)(nil).SetProfileBanned), ctx, addr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProfileBanned", reflect.TypeOf((*MockIndexStorage)(nil).SetProfileBanned), ctx, addr, source, reason)
+}
+
+// UnsetProfileBanned mocks base method
+func (m *MockIndexStorage) UnsetProfileBanned(ctx context.Context, addr string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsetProfileBanned", ctx, addr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnsetProfileBanned indicates an expected call of UnsetProfileBanned
+func (mr *MockIndexStorageMockRecorder) UnsetProfileBanned(ctx, addr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsetProfileBanned", reflect.TypeOf((*MockIndexStorage)(nil).UnsetProfileBanned), ctx, addr)
 }
 
 // IsProfileBanned mocks base method
@@ -152,6 +167,21 @@ func (mr *MockIndexStorageMockRecorder) IsProfileBanned(ctx, addr interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsProfileBanned", reflect.TypeOf((*MockIndexStorage)(nil).IsProfileBanned), ctx, addr)
 }
 
+// ListBannedProfiles mocks base method
+func (m *MockIndexStorage) ListBannedProfiles(ctx context.Context, from string, limit uint16) ([]*storage.BannedProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBannedProfiles", ctx, from, limit)
+	ret0, _ := ret[0].([]*storage.BannedProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBannedProfiles indicates an expected call of ListBannedProfiles
+func (mr *MockIndexStorageMockRecorder) ListBannedProfiles(ctx, from, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBannedProfiles", reflect.TypeOf((*MockIndexStorage)(nil).ListBannedProfiles), ctx, from, limit)
+}
+
 // DeleteProfile mocks base method
 func (m *MockIndexStorage) DeleteProfile(ctx context.Context, addr string) error {
 	m.ctrl.T.Helper()
@@ -181,6 +211,21 @@ func (mr *MockIndexStorageMockRecorder) ListPDV(ctx, owner, from, limit interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPDV", reflect.TypeOf((*MockIndexStorage)(nil).ListPDV), ctx, owner, from, limit)
 }
 
+// ListAllPDV mocks base method
+func (m *MockIndexStorage) ListAllPDV(ctx context.Context, after *storage.PDVIndex, limit uint16) ([]*storage.PDVIndex, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllPDV", ctx, after, limit)
+	ret0, _ := ret[0].([]*storage.PDVIndex)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAllPDV indicates an expected call of ListAllPDV
+func (mr *MockIndexStorageMockRecorder) ListAllPDV(ctx, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllPDV", reflect.TypeOf((*MockIndexStorage)(nil).ListAllPDV), ctx, after, limit)
+}
+
 // DeletePDV mocks base method
 func (m *MockIndexStorage) DeletePDV(ctx context.Context, owner string) error {
 	m.ctrl.T.Helper()
@@ -225,10 +270,10 @@ func (mr *MockIndexStorageMockRecorder) SetPDVMeta(ctx, address, id, tx, device,
 }
 
 // GetPDVDelta mocks base method
-func (m *MockIndexStorage) GetPDVDelta(ctx context.Context, address string) (float64, error) {
+func (m *MockIndexStorage) GetPDVDelta(ctx context.Context, address string) (types.Dec, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetPDVDelta", ctx, address)
-	ret0, _ := ret[0].(float64)
+	ret0, _ := ret[0].(types.Dec)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -240,10 +285,10 @@ func (mr *MockIndexStorageMockRecorder) GetPDVDelta(ctx, address interface{}) *g
 }
 
 // GetPDVTotalDelta mocks base method
-func (m *MockIndexStorage) GetPDVTotalDelta(ctx context.Context) (float64, error) {
+func (m *MockIndexStorage) GetPDVTotalDelta(ctx context.Context) (types.Dec, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetPDVTotalDelta", ctx)
-	ret0, _ := ret[0].(float64)
+	ret0, _ := ret[0].(types.Dec)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -255,32 +300,32 @@ func (mr *MockIndexStorageMockRecorder) GetPDVTotalDelta(ctx interface{}) *gomoc
 }
 
 // GetPDVDeltaList mocks base method
-func (m *MockIndexStorage) GetPDVDeltaList(ctx context.Context) ([]*storage.PDVDelta, error) {
+func (m *MockIndexStorage) GetPDVDeltaList(ctx context.Context, since, until time.Time) ([]*storage.PDVDelta, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPDVDeltaList", ctx)
+	ret := m.ctrl.Call(m, "GetPDVDeltaList", ctx, since, until)
 	ret0, _ := ret[0].([]*storage.PDVDelta)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPDVDeltaList indicates an expected call of GetPDVDeltaList
-func (mr *MockIndexStorageMockRecorder) GetPDVDeltaList(ctx interface{}) *gomock.Call {
+func (mr *MockIndexStorageMockRecorder) GetPDVDeltaList(ctx, since, until interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPDVDeltaList", reflect.TypeOf((*MockIndexStorage)(nil).GetPDVDeltaList), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPDVDeltaList", reflect.TypeOf((*MockIndexStorage)(nil).GetPDVDeltaList), ctx, since, until)
 }
 
 // CreateRewardsQueueItem mocks base method
-func (m *MockIndexStorage) CreateRewardsQueueItem(ctx context.Context, addr string, reward int64) error {
+func (m *MockIndexStorage) CreateRewardsQueueItem(ctx context.Context, addr string, reward int64, periodStart, periodEnd time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateRewardsQueueItem", ctx, addr, reward)
+	ret := m.ctrl.Call(m, "CreateRewardsQueueItem", ctx, addr, reward, periodStart, periodEnd)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateRewardsQueueItem indicates an expected call of CreateRewardsQueueItem
-func (mr *MockIndexStorageMockRecorder) CreateRewardsQueueItem(ctx, addr, reward interface{}) *gomock.Call {
+func (mr *MockIndexStorageMockRecorder) CreateRewardsQueueItem(ctx, addr, reward, periodStart, periodEnd interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRewardsQueueItem", reflect.TypeOf((*MockIndexStorage)(nil).CreateRewardsQueueItem), ctx, addr, reward)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRewardsQueueItem", reflect.TypeOf((*MockIndexStorage)(nil).CreateRewardsQueueItem), ctx, addr, reward, periodStart, periodEnd)
 }
 
 // GetRewardsQueueItemList mocks base method
@@ -312,6 +357,524 @@ func (mr *MockIndexStorageMockRecorder) DeleteRewardsQueueItem(ctx, addr interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRewardsQueueItem", reflect.TypeOf((*MockIndexStorage)(nil).DeleteRewardsQueueItem), ctx, addr)
 }
 
+// SetRewardsQueueItemsSent mocks base method
+func (m *MockIndexStorage) SetRewardsQueueItemsSent(ctx context.Context, addrs []string, sentAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRewardsQueueItemsSent", ctx, addrs, sentAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRewardsQueueItemsSent indicates an expected call of SetRewardsQueueItemsSent
+func (mr *MockIndexStorageMockRecorder) SetRewardsQueueItemsSent(ctx, addrs, sentAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRewardsQueueItemsSent", reflect.TypeOf((*MockIndexStorage)(nil).SetRewardsQueueItemsSent), ctx, addrs, sentAt)
+}
+
+// SetRewardsQueueItemsTx mocks base method
+func (m *MockIndexStorage) SetRewardsQueueItemsTx(ctx context.Context, addrs []string, tx string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRewardsQueueItemsTx", ctx, addrs, tx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRewardsQueueItemsTx indicates an expected call of SetRewardsQueueItemsTx
+func (mr *MockIndexStorageMockRecorder) SetRewardsQueueItemsTx(ctx, addrs, tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRewardsQueueItemsTx", reflect.TypeOf((*MockIndexStorage)(nil).SetRewardsQueueItemsTx), ctx, addrs, tx)
+}
+
+// ResetRewardsQueueItems mocks base method
+func (m *MockIndexStorage) ResetRewardsQueueItems(ctx context.Context, addrs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetRewardsQueueItems", ctx, addrs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetRewardsQueueItems indicates an expected call of ResetRewardsQueueItems
+func (mr *MockIndexStorageMockRecorder) ResetRewardsQueueItems(ctx, addrs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetRewardsQueueItems", reflect.TypeOf((*MockIndexStorage)(nil).ResetRewardsQueueItems), ctx, addrs)
+}
+
+// CreatePDVRewardsCarryOverItem mocks base method
+func (m *MockIndexStorage) CreatePDVRewardsCarryOverItem(ctx context.Context, addr string, delta types.Dec) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePDVRewardsCarryOverItem", ctx, addr, delta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePDVRewardsCarryOverItem indicates an expected call of CreatePDVRewardsCarryOverItem
+func (mr *MockIndexStorageMockRecorder) CreatePDVRewardsCarryOverItem(ctx, addr, delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePDVRewardsCarryOverItem", reflect.TypeOf((*MockIndexStorage)(nil).CreatePDVRewardsCarryOverItem), ctx, addr, delta)
+}
+
+// DeletePDVRewardsCarryOverList mocks base method
+func (m *MockIndexStorage) DeletePDVRewardsCarryOverList(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePDVRewardsCarryOverList", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePDVRewardsCarryOverList indicates an expected call of DeletePDVRewardsCarryOverList
+func (mr *MockIndexStorageMockRecorder) DeletePDVRewardsCarryOverList(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePDVRewardsCarryOverList", reflect.TypeOf((*MockIndexStorage)(nil).DeletePDVRewardsCarryOverList), ctx)
+}
+
+// CreatePDVRewardsHistoryItem mocks base method
+func (m *MockIndexStorage) CreatePDVRewardsHistoryItem(ctx context.Context, item *storage.PDVRewardsHistoryItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePDVRewardsHistoryItem", ctx, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePDVRewardsHistoryItem indicates an expected call of CreatePDVRewardsHistoryItem
+func (mr *MockIndexStorageMockRecorder) CreatePDVRewardsHistoryItem(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePDVRewardsHistoryItem", reflect.TypeOf((*MockIndexStorage)(nil).CreatePDVRewardsHistoryItem), ctx, item)
+}
+
+// ListPDVRewardsHistory mocks base method
+func (m *MockIndexStorage) ListPDVRewardsHistory(ctx context.Context, addr string, from uint64, limit uint16) ([]*storage.PDVRewardsHistoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPDVRewardsHistory", ctx, addr, from, limit)
+	ret0, _ := ret[0].([]*storage.PDVRewardsHistoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPDVRewardsHistory indicates an expected call of ListPDVRewardsHistory
+func (mr *MockIndexStorageMockRecorder) ListPDVRewardsHistory(ctx, addr, from, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPDVRewardsHistory", reflect.TypeOf((*MockIndexStorage)(nil).ListPDVRewardsHistory), ctx, addr, from, limit)
+}
+
+// CreateProfileBanPDV mocks base method
+func (m *MockIndexStorage) CreateProfileBanPDV(ctx context.Context, addr string, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProfileBanPDV", ctx, addr, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateProfileBanPDV indicates an expected call of CreateProfileBanPDV
+func (mr *MockIndexStorageMockRecorder) CreateProfileBanPDV(ctx, addr, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProfileBanPDV", reflect.TypeOf((*MockIndexStorage)(nil).CreateProfileBanPDV), ctx, addr, id)
+}
+
+// ListProfileBanPDV mocks base method
+func (m *MockIndexStorage) ListProfileBanPDV(ctx context.Context, addr string) ([]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProfileBanPDV", ctx, addr)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProfileBanPDV indicates an expected call of ListProfileBanPDV
+func (mr *MockIndexStorageMockRecorder) ListProfileBanPDV(ctx, addr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProfileBanPDV", reflect.TypeOf((*MockIndexStorage)(nil).ListProfileBanPDV), ctx, addr)
+}
+
+// DeleteProfileBanPDV mocks base method
+func (m *MockIndexStorage) DeleteProfileBanPDV(ctx context.Context, addr string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProfileBanPDV", ctx, addr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProfileBanPDV indicates an expected call of DeleteProfileBanPDV
+func (mr *MockIndexStorageMockRecorder) DeleteProfileBanPDV(ctx, addr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProfileBanPDV", reflect.TypeOf((*MockIndexStorage)(nil).DeleteProfileBanPDV), ctx, addr)
+}
+
+// CreateAdminAuditItem mocks base method
+func (m *MockIndexStorage) CreateAdminAuditItem(ctx context.Context, item *storage.AdminAuditItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAdminAuditItem", ctx, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAdminAuditItem indicates an expected call of CreateAdminAuditItem
+func (mr *MockIndexStorageMockRecorder) CreateAdminAuditItem(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAdminAuditItem", reflect.TypeOf((*MockIndexStorage)(nil).CreateAdminAuditItem), ctx, item)
+}
+
+// GetDataKey mocks base method
+func (m *MockIndexStorage) GetDataKey(ctx context.Context, owner string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDataKey", ctx, owner)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDataKey indicates an expected call of GetDataKey
+func (mr *MockIndexStorageMockRecorder) GetDataKey(ctx, owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDataKey", reflect.TypeOf((*MockIndexStorage)(nil).GetDataKey), ctx, owner)
+}
+
+// CreateDataKey mocks base method
+func (m *MockIndexStorage) CreateDataKey(ctx context.Context, owner string, key []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDataKey", ctx, owner, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDataKey indicates an expected call of CreateDataKey
+func (mr *MockIndexStorageMockRecorder) CreateDataKey(ctx, owner, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDataKey", reflect.TypeOf((*MockIndexStorage)(nil).CreateDataKey), ctx, owner, key)
+}
+
+// ListDataKeys mocks base method
+func (m *MockIndexStorage) ListDataKeys(ctx context.Context, after string, limit uint16) ([]*storage.DataKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDataKeys", ctx, after, limit)
+	ret0, _ := ret[0].([]*storage.DataKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDataKeys indicates an expected call of ListDataKeys
+func (mr *MockIndexStorageMockRecorder) ListDataKeys(ctx, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDataKeys", reflect.TypeOf((*MockIndexStorage)(nil).ListDataKeys), ctx, after, limit)
+}
+
+// UpdateDataKey mocks base method
+func (m *MockIndexStorage) UpdateDataKey(ctx context.Context, owner string, old, key []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDataKey", ctx, owner, old, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDataKey indicates an expected call of UpdateDataKey
+func (mr *MockIndexStorageMockRecorder) UpdateDataKey(ctx, owner, old, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDataKey", reflect.TypeOf((*MockIndexStorage)(nil).UpdateDataKey), ctx, owner, old, key)
+}
+
+// DeleteDataKey mocks base method
+func (m *MockIndexStorage) DeleteDataKey(ctx context.Context, owner string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDataKey", ctx, owner)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDataKey indicates an expected call of DeleteDataKey
+func (mr *MockIndexStorageMockRecorder) DeleteDataKey(ctx, owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDataKey", reflect.TypeOf((*MockIndexStorage)(nil).DeleteDataKey), ctx, owner)
+}
+
+// CreateQuarantineItem mocks base method
+func (m *MockIndexStorage) CreateQuarantineItem(ctx context.Context, body, reason string, receiveCount int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateQuarantineItem", ctx, body, reason, receiveCount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateQuarantineItem indicates an expected call of CreateQuarantineItem
+func (mr *MockIndexStorageMockRecorder) CreateQuarantineItem(ctx, body, reason, receiveCount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateQuarantineItem", reflect.TypeOf((*MockIndexStorage)(nil).CreateQuarantineItem), ctx, body, reason, receiveCount)
+}
+
+// GetQuarantineItemList mocks base method
+func (m *MockIndexStorage) GetQuarantineItemList(ctx context.Context) ([]*storage.QuarantineItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuarantineItemList", ctx)
+	ret0, _ := ret[0].([]*storage.QuarantineItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuarantineItemList indicates an expected call of GetQuarantineItemList
+func (mr *MockIndexStorageMockRecorder) GetQuarantineItemList(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuarantineItemList", reflect.TypeOf((*MockIndexStorage)(nil).GetQuarantineItemList), ctx)
+}
+
+// DeleteQuarantineItem mocks base method
+func (m *MockIndexStorage) DeleteQuarantineItem(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteQuarantineItem", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteQuarantineItem indicates an expected call of DeleteQuarantineItem
+func (mr *MockIndexStorageMockRecorder) DeleteQuarantineItem(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteQuarantineItem", reflect.TypeOf((*MockIndexStorage)(nil).DeleteQuarantineItem), ctx, id)
+}
+
+// CreateFraudRecheckItem mocks base method
+func (m *MockIndexStorage) CreateFraudRecheckItem(ctx context.Context, item *storage.FraudRecheckItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFraudRecheckItem", ctx, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFraudRecheckItem indicates an expected call of CreateFraudRecheckItem
+func (mr *MockIndexStorageMockRecorder) CreateFraudRecheckItem(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFraudRecheckItem", reflect.TypeOf((*MockIndexStorage)(nil).CreateFraudRecheckItem), ctx, item)
+}
+
+// GetFraudRecheckItemList mocks base method
+func (m *MockIndexStorage) GetFraudRecheckItemList(ctx context.Context, limit uint16, now time.Time) ([]*storage.FraudRecheckItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFraudRecheckItemList", ctx, limit, now)
+	ret0, _ := ret[0].([]*storage.FraudRecheckItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFraudRecheckItemList indicates an expected call of GetFraudRecheckItemList
+func (mr *MockIndexStorageMockRecorder) GetFraudRecheckItemList(ctx, limit, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFraudRecheckItemList", reflect.TypeOf((*MockIndexStorage)(nil).GetFraudRecheckItemList), ctx, limit, now)
+}
+
+// DeleteFraudRecheckItem mocks base method
+func (m *MockIndexStorage) DeleteFraudRecheckItem(ctx context.Context, address string, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFraudRecheckItem", ctx, address, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFraudRecheckItem indicates an expected call of DeleteFraudRecheckItem
+func (mr *MockIndexStorageMockRecorder) DeleteFraudRecheckItem(ctx, address, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFraudRecheckItem", reflect.TypeOf((*MockIndexStorage)(nil).DeleteFraudRecheckItem), ctx, address, id)
+}
+
+// PostponeFraudRecheckItem mocks base method
+func (m *MockIndexStorage) PostponeFraudRecheckItem(ctx context.Context, address string, id uint64, nextAttemptAt time.Time, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostponeFraudRecheckItem", ctx, address, id, nextAttemptAt, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostponeFraudRecheckItem indicates an expected call of PostponeFraudRecheckItem
+func (mr *MockIndexStorageMockRecorder) PostponeFraudRecheckItem(ctx, address, id, nextAttemptAt, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostponeFraudRecheckItem", reflect.TypeOf((*MockIndexStorage)(nil).PostponeFraudRecheckItem), ctx, address, id, nextAttemptAt, reason)
+}
+
+// FailFraudRecheckItem mocks base method
+func (m *MockIndexStorage) FailFraudRecheckItem(ctx context.Context, address string, id uint64, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailFraudRecheckItem", ctx, address, id, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailFraudRecheckItem indicates an expected call of FailFraudRecheckItem
+func (mr *MockIndexStorageMockRecorder) FailFraudRecheckItem(ctx, address, id, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailFraudRecheckItem", reflect.TypeOf((*MockIndexStorage)(nil).FailFraudRecheckItem), ctx, address, id, reason)
+}
+
+// GetPDVFingerprintList mocks base method
+func (m *MockIndexStorage) GetPDVFingerprintList(ctx context.Context, address string, fingerprints [][]byte, since time.Time) ([][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPDVFingerprintList", ctx, address, fingerprints, since)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPDVFingerprintList indicates an expected call of GetPDVFingerprintList
+func (mr *MockIndexStorageMockRecorder) GetPDVFingerprintList(ctx, address, fingerprints, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPDVFingerprintList", reflect.TypeOf((*MockIndexStorage)(nil).GetPDVFingerprintList), ctx, address, fingerprints, since)
+}
+
+// SetPDVFingerprints mocks base method
+func (m *MockIndexStorage) SetPDVFingerprints(ctx context.Context, address string, fingerprints [][]byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPDVFingerprints", ctx, address, fingerprints)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPDVFingerprints indicates an expected call of SetPDVFingerprints
+func (mr *MockIndexStorageMockRecorder) SetPDVFingerprints(ctx, address, fingerprints interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPDVFingerprints", reflect.TypeOf((*MockIndexStorage)(nil).SetPDVFingerprints), ctx, address, fingerprints)
+}
+
+// DeletePDVFingerprints mocks base method
+func (m *MockIndexStorage) DeletePDVFingerprints(ctx context.Context, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePDVFingerprints", ctx, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePDVFingerprints indicates an expected call of DeletePDVFingerprints
+func (mr *MockIndexStorageMockRecorder) DeletePDVFingerprints(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePDVFingerprints", reflect.TypeOf((*MockIndexStorage)(nil).DeletePDVFingerprints), ctx, before)
+}
+
+// CreateFraudVerdict mocks base method
+func (m *MockIndexStorage) CreateFraudVerdict(ctx context.Context, v *storage.FraudVerdict) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFraudVerdict", ctx, v)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFraudVerdict indicates an expected call of CreateFraudVerdict
+func (mr *MockIndexStorageMockRecorder) CreateFraudVerdict(ctx, v interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFraudVerdict", reflect.TypeOf((*MockIndexStorage)(nil).CreateFraudVerdict), ctx, v)
+}
+
+// GetFraudVerdictOutcomeList mocks base method
+func (m *MockIndexStorage) GetFraudVerdictOutcomeList(ctx context.Context, since time.Time) ([]*storage.FraudVerdictOutcome, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFraudVerdictOutcomeList", ctx, since)
+	ret0, _ := ret[0].([]*storage.FraudVerdictOutcome)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFraudVerdictOutcomeList indicates an expected call of GetFraudVerdictOutcomeList
+func (mr *MockIndexStorageMockRecorder) GetFraudVerdictOutcomeList(ctx, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFraudVerdictOutcomeList", reflect.TypeOf((*MockIndexStorage)(nil).GetFraudVerdictOutcomeList), ctx, since)
+}
+
+// GetBlacklistEntryList mocks base method
+func (m *MockIndexStorage) GetBlacklistEntryList(ctx context.Context) ([]*storage.BlacklistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlacklistEntryList", ctx)
+	ret0, _ := ret[0].([]*storage.BlacklistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlacklistEntryList indicates an expected call of GetBlacklistEntryList
+func (mr *MockIndexStorageMockRecorder) GetBlacklistEntryList(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlacklistEntryList", reflect.TypeOf((*MockIndexStorage)(nil).GetBlacklistEntryList), ctx)
+}
+
+// CreateBlacklistEntry mocks base method
+func (m *MockIndexStorage) CreateBlacklistEntry(ctx context.Context, e *storage.BlacklistEntry) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBlacklistEntry", ctx, e)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBlacklistEntry indicates an expected call of CreateBlacklistEntry
+func (mr *MockIndexStorageMockRecorder) CreateBlacklistEntry(ctx, e interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBlacklistEntry", reflect.TypeOf((*MockIndexStorage)(nil).CreateBlacklistEntry), ctx, e)
+}
+
+// DeleteBlacklistEntry mocks base method
+func (m *MockIndexStorage) DeleteBlacklistEntry(ctx context.Context, id uint64) (*storage.BlacklistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBlacklistEntry", ctx, id)
+	ret0, _ := ret[0].(*storage.BlacklistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBlacklistEntry indicates an expected call of DeleteBlacklistEntry
+func (mr *MockIndexStorageMockRecorder) DeleteBlacklistEntry(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBlacklistEntry", reflect.TypeOf((*MockIndexStorage)(nil).DeleteBlacklistEntry), ctx, id)
+}
+
+// CreateRewardTx mocks base method
+func (m *MockIndexStorage) CreateRewardTx(ctx context.Context, items []*storage.RewardTxItem) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRewardTx", ctx, items)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRewardTx indicates an expected call of CreateRewardTx
+func (mr *MockIndexStorageMockRecorder) CreateRewardTx(ctx, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRewardTx", reflect.TypeOf((*MockIndexStorage)(nil).CreateRewardTx), ctx, items)
+}
+
+// SetRewardTxStatus mocks base method
+func (m *MockIndexStorage) SetRewardTxStatus(ctx context.Context, id uint64, status storage.RewardTxStatus, txHash, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRewardTxStatus", ctx, id, status, txHash, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRewardTxStatus indicates an expected call of SetRewardTxStatus
+func (mr *MockIndexStorageMockRecorder) SetRewardTxStatus(ctx, id, status, txHash, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRewardTxStatus", reflect.TypeOf((*MockIndexStorage)(nil).SetRewardTxStatus), ctx, id, status, txHash, reason)
+}
+
+// HasActiveRewardTx mocks base method
+func (m *MockIndexStorage) HasActiveRewardTx(ctx context.Context, address string, id uint64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasActiveRewardTx", ctx, address, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasActiveRewardTx indicates an expected call of HasActiveRewardTx
+func (mr *MockIndexStorageMockRecorder) HasActiveRewardTx(ctx, address, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasActiveRewardTx", reflect.TypeOf((*MockIndexStorage)(nil).HasActiveRewardTx), ctx, address, id)
+}
+
+// GetUnsettledRewardTxList mocks base method
+func (m *MockIndexStorage) GetUnsettledRewardTxList(ctx context.Context, before time.Time) ([]*storage.RewardTx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnsettledRewardTxList", ctx, before)
+	ret0, _ := ret[0].([]*storage.RewardTx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnsettledRewardTxList indicates an expected call of GetUnsettledRewardTxList
+func (mr *MockIndexStorageMockRecorder) GetUnsettledRewardTxList(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnsettledRewardTxList", reflect.TypeOf((*MockIndexStorage)(nil).GetUnsettledRewardTxList), ctx, before)
+}
+
 // GetPDVRewardsDistributedDate mocks base method
 func (m *MockIndexStorage) GetPDVRewardsDistributedDate(ctx context.Context) (time.Time, error) {
 	m.ctrl.T.Helper()
@@ -10,6 +10,7 @@ import (
 	"math"
 	"time"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
@@ -41,6 +42,28 @@ type profileDTO struct {
 	CreatedAt time.Time      `db:"created_at"`
 }
 
+type pdvDeltaDTO struct {
+	Address          string      `db:"address"`
+	Delta            string      `db:"delta"`
+	Banned           bool        `db:"banned"`
+	AccountCreatedAt pq.NullTime `db:"account_created_at"`
+}
+
+type rewardTxDTO struct {
+	ID        uint64                 `db:"id"`
+	TxHash    sql.NullString         `db:"tx_hash"`
+	Status    storage.RewardTxStatus `db:"status"`
+	UpdatedAt time.Time              `db:"updated_at"`
+}
+
+type rewardTxItemDTO struct {
+	TxID   uint64          `db:"tx_id"`
+	Owner  string          `db:"owner"`
+	PDVID  uint64          `db:"pdv_id"`
+	Device string          `db:"device"`
+	Meta   json.RawMessage `db:"meta"`
+}
+
 // New creates new instance of pg.
 func New(db *sql.DB) *pg { // nolint:golint
 	return &pg{
@@ -143,13 +166,55 @@ func (s pg) GetProfiles(ctx context.Context, addr []string) ([]*storage.Profile,
 	return out, nil
 }
 
-func (s pg) SetProfileBanned(ctx context.Context, addr string) error {
-	if _, err := s.ext.ExecContext(ctx, `UPDATE profile SET banned = true WHERE address = $1`, addr); err != nil {
+// SetProfileBanned bans the profile, ban of already banned profile is updated.
+// Empty profile is created if it doesn't exist, so accounts without profile can be banned too.
+func (s pg) SetProfileBanned(ctx context.Context, addr string, source storage.BanSource, reason string) error {
+	if _, err := s.ext.ExecContext(ctx, `
+		INSERT INTO profile (address, banned, ban_source, ban_reason, banned_at)
+		VALUES ($1, true, $2, $3, CURRENT_TIMESTAMP)
+		ON CONFLICT (address) DO UPDATE SET
+			banned = true, ban_source = EXCLUDED.ban_source, ban_reason = EXCLUDED.ban_reason, banned_at = EXCLUDED.banned_at
+	`, addr, source, reason); err != nil {
+		return fmt.Errorf("failed to upsert: %w", err)
+	}
+
+	return nil
+}
+
+// UnsetProfileBanned unbans the profile. It returns ErrNotFound if profile isn't banned.
+func (s pg) UnsetProfileBanned(ctx context.Context, addr string) error {
+	res, err := s.ext.ExecContext(ctx, `
+		UPDATE profile SET banned = false, ban_source = NULL, ban_reason = NULL, banned_at = NULL
+		WHERE address = $1 AND banned
+	`, addr)
+	if err != nil {
 		return fmt.Errorf("failed to update: %w", err)
 	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	} else if n == 0 {
+		return storage.ErrNotFound
+	}
+
 	return nil
 }
 
+// ListBannedProfiles returns banned profiles sorted by address. Listing starts after from address.
+func (s pg) ListBannedProfiles(ctx context.Context, from string, limit uint16) ([]*storage.BannedProfile, error) {
+	out := []*storage.BannedProfile{}
+	if err := sqlx.SelectContext(ctx, s.ext, &out, `
+		SELECT address, ban_source, ban_reason, banned_at FROM profile
+		WHERE banned AND address > $1
+		ORDER BY address
+		LIMIT $2
+	`, from, limit); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	return out, nil
+}
+
 func (s pg) IsProfileBanned(ctx context.Context, addr string) (bool, error) {
 	var banned bool
 	err := sqlx.GetContext(ctx, s.ext, &banned, `SELECT EXISTS (SELECT * FROM profile WHERE address = $1 AND banned = true)`, addr)
@@ -225,6 +290,25 @@ func (s pg) ListPDV(ctx context.Context, owner string, from uint64, limit uint16
 	return out, nil
 }
 
+// ListAllPDV returns pdv of all owners ordered by owner and id. Listing starts after the passed pdv, nil means from the beginning.
+func (s pg) ListAllPDV(ctx context.Context, after *storage.PDVIndex, limit uint16) ([]*storage.PDVIndex, error) {
+	if after == nil {
+		after = &storage.PDVIndex{}
+	}
+
+	var out []*storage.PDVIndex
+	if err := sqlx.SelectContext(ctx, s.ext, &out, `
+		SELECT owner, id FROM pdv
+		WHERE (owner, id) > ($1, $2)
+		ORDER BY owner, id
+		LIMIT $3
+	`, after.Owner, after.ID, limit); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	return out, nil
+}
+
 func (s pg) GetPDVMeta(ctx context.Context, address string, id uint64) (*entities.PDVMeta, error) {
 	var meta json.RawMessage
 	if err := sqlx.GetContext(ctx, s.ext, &meta, `
@@ -251,12 +335,15 @@ func (s pg) SetPDVMeta(ctx context.Context, address string, id uint64, tx string
 		return fmt.Errorf("failed to marshal meta: %w", err)
 	}
 
-	reward, _ := m.Reward.Float64()
+	reward := sdk.ZeroDec()
+	if !m.Reward.IsNil() {
+		reward = m.Reward
+	}
 
 	if _, err := s.ext.ExecContext(ctx, `
 		INSERT INTO pdv(owner, id, tx, meta, reward, device) VALUES($1, $2, $3, $4, $5, $6) ON CONFLICT (owner, id) DO UPDATE
 			SET tx = EXCLUDED.tx, meta = EXCLUDED.meta, reward = EXCLUDED.reward
-	`, address, id, tx, b, reward, device); err != nil {
+	`, address, id, tx, b, reward.String(), device); err != nil {
 		return fmt.Errorf("failed to insert: %w", err)
 	}
 
@@ -270,28 +357,34 @@ func (s pg) DeletePDV(ctx context.Context, address string) error {
 	return nil
 }
 
-func (s pg) GetPDVDelta(ctx context.Context, address string) (float64, error) {
-	var delta float64
-	err := sqlx.GetContext(ctx, s.ext, &delta, `
-		SELECT COALESCE(SUM(reward), 0) FROM pdv
+func (s pg) GetPDVDelta(ctx context.Context, address string) (sdk.Dec, error) {
+	var delta string
+	if err := sqlx.GetContext(ctx, s.ext, &delta, `
+		SELECT ROUND(COALESCE(SUM(reward), 0), 18)::TEXT FROM pdv
         WHERE
               owner NOT IN (SELECT address FROM profile WHERE banned) AND
               created_at > (SELECT date FROM pdv_rewards_distributed_date) AND 
               owner = $1
               
-    `, address)
-	return delta, err
+    `, address); err != nil {
+		return sdk.Dec{}, fmt.Errorf("failed to query: %w", err)
+	}
+
+	return sdk.NewDecFromStr(delta)
 }
 
-func (s pg) GetPDVTotalDelta(ctx context.Context) (float64, error) {
-	var total float64
-	err := sqlx.GetContext(ctx, s.ext, &total, `
-		SELECT COALESCE(SUM(reward), 0) FROM pdv
+func (s pg) GetPDVTotalDelta(ctx context.Context) (sdk.Dec, error) {
+	var total string
+	if err := sqlx.GetContext(ctx, s.ext, &total, `
+		SELECT ROUND(COALESCE(SUM(reward), 0), 18)::TEXT FROM pdv
         WHERE
               owner NOT IN (SELECT address FROM profile WHERE banned) AND
               created_at > (SELECT date FROM pdv_rewards_distributed_date)
-    `)
-	return total, err
+    `); err != nil {
+		return sdk.Dec{}, fmt.Errorf("failed to query: %w", err)
+	}
+
+	return sdk.NewDecFromStr(total)
 }
 
 func (s pg) GetPDVRewardsDistributedDate(ctx context.Context) (time.Time, error) {
@@ -310,27 +403,71 @@ func (s pg) SetPDVRewardsDistributedDate(ctx context.Context, date time.Time) er
 	return nil
 }
 
-func (s pg) GetPDVDeltaList(ctx context.Context) ([]*storage.PDVDelta, error) {
-	var out []*storage.PDVDelta
-	if err := sqlx.SelectContext(ctx, s.ext, &out, `
-		SELECT owner AS address, SUM(reward) AS delta  FROM pdv
-        WHERE
-              owner NOT IN (SELECT address FROM profile WHERE banned) AND
-              created_at > (SELECT date FROM pdv_rewards_distributed_date) 
-        GROUP BY owner
-        HAVING SUM(reward) > 0
-		ORDER BY owner
-	`); err != nil {
+// GetPDVDeltaList returns rewards of every owner for PDV created in (since, until] with carried over deltas,
+// banned owners are included.
+func (s pg) GetPDVDeltaList(ctx context.Context, since, until time.Time) ([]*storage.PDVDelta, error) {
+	var deltas []*pdvDeltaDTO
+	if err := sqlx.SelectContext(ctx, s.ext, &deltas, `
+		SELECT d.address, ROUND(SUM(d.delta), 18)::TEXT AS delta, COALESCE(p.banned, FALSE) AS banned, p.created_at AS account_created_at
+		FROM (
+			SELECT owner AS address, reward AS delta FROM pdv
+			WHERE created_at > $1 AND created_at <= $2
+			UNION ALL
+			SELECT address, delta FROM pdv_rewards_carry_over
+		) d
+		LEFT JOIN profile p ON p.address = d.address
+		GROUP BY d.address, p.banned, p.created_at
+		HAVING SUM(d.delta) > 0
+		ORDER BY d.address
+	`, since, until); err != nil {
 		return nil, fmt.Errorf("failed to select: %w", err)
 	}
 
+	out := make([]*storage.PDVDelta, len(deltas))
+	for i, v := range deltas {
+		delta, err := sdk.NewDecFromStr(v.Delta)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse delta of %s: %w", v.Address, err)
+		}
+
+		out[i] = &storage.PDVDelta{
+			Address: v.Address,
+			Delta:   delta,
+			Banned:  v.Banned,
+		}
+
+		if v.AccountCreatedAt.Valid {
+			out[i].AccountCreatedAt = &v.AccountCreatedAt.Time
+		}
+	}
+
 	return out, nil
 }
 
-func (s pg) CreateRewardsQueueItem(ctx context.Context, addr string, reward int64) error {
+// CreatePDVRewardsCarryOverItem saves delta which should be added to the next distribution.
+func (s pg) CreatePDVRewardsCarryOverItem(ctx context.Context, addr string, delta sdk.Dec) error {
+	if _, err := s.ext.ExecContext(ctx, `
+		INSERT INTO pdv_rewards_carry_over(address, delta) VALUES($1, $2)
+	`, addr, delta.String()); err != nil {
+		return fmt.Errorf("failed to insert: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePDVRewardsCarryOverList deletes all carried over deltas.
+func (s pg) DeletePDVRewardsCarryOverList(ctx context.Context) error {
+	if _, err := s.ext.ExecContext(ctx, `DELETE FROM pdv_rewards_carry_over`); err != nil {
+		return fmt.Errorf("failed to delete: %w", err)
+	}
+
+	return nil
+}
+
+func (s pg) CreateRewardsQueueItem(ctx context.Context, addr string, reward int64, periodStart, periodEnd time.Time) error {
 	_, err := s.ext.ExecContext(ctx, `
-	INSERT INTO rewards_queue(address, reward) VALUES($1, $2)
-	`, addr, reward)
+	INSERT INTO rewards_queue(address, reward, period_start, period_end) VALUES($1, $2, $3, $4)
+	`, addr, reward, periodStart, periodEnd)
 	return err
 }
 
@@ -338,7 +475,7 @@ func (s pg) GetRewardsQueueItemList(ctx context.Context) ([]*storage.RewardsQueu
 	var out []*storage.RewardsQueueItem
 
 	if err := sqlx.SelectContext(ctx, s.ext, &out, `
-		SELECT address, reward FROM rewards_queue ORDER BY created_at
+		SELECT address, reward, period_start, period_end, tx_hash, sent_at FROM rewards_queue ORDER BY created_at
 	`); err != nil {
 		return nil, fmt.Errorf("failed to select: %w", err)
 	}
@@ -353,6 +490,518 @@ func (s pg) DeleteRewardsQueueItem(ctx context.Context, addr string) error {
 	return err
 }
 
+// SetRewardsQueueItemsSent marks rewards as being sent, it should be called before the broadcast.
+func (s pg) SetRewardsQueueItemsSent(ctx context.Context, addrs []string, sentAt time.Time) error {
+	if _, err := s.ext.ExecContext(ctx, `
+		UPDATE rewards_queue SET sent_at = $2, tx_hash = '' WHERE address = ANY($1)
+	`, pq.Array(addrs), sentAt); err != nil {
+		return fmt.Errorf("failed to update: %w", err)
+	}
+
+	return nil
+}
+
+// SetRewardsQueueItemsTx saves hash of stakes tx which sent rewards.
+func (s pg) SetRewardsQueueItemsTx(ctx context.Context, addrs []string, tx string) error {
+	if _, err := s.ext.ExecContext(ctx, `
+		UPDATE rewards_queue SET tx_hash = $2 WHERE address = ANY($1)
+	`, pq.Array(addrs), tx); err != nil {
+		return fmt.Errorf("failed to update: %w", err)
+	}
+
+	return nil
+}
+
+// ResetRewardsQueueItems marks rewards as not sent, so they are sent again.
+func (s pg) ResetRewardsQueueItems(ctx context.Context, addrs []string) error {
+	if _, err := s.ext.ExecContext(ctx, `
+		UPDATE rewards_queue SET sent_at = NULL, tx_hash = '' WHERE address = ANY($1)
+	`, pq.Array(addrs)); err != nil {
+		return fmt.Errorf("failed to update: %w", err)
+	}
+
+	return nil
+}
+
+// CreatePDVRewardsHistoryItem saves distributed reward.
+func (s pg) CreatePDVRewardsHistoryItem(ctx context.Context, item *storage.PDVRewardsHistoryItem) error {
+	if _, err := s.ext.ExecContext(ctx, `
+		INSERT INTO pdv_rewards_history(address, amount, period_start, period_end, tx_hash) VALUES($1, $2, $3, $4, $5)
+	`, item.Address, item.Amount, item.PeriodStart, item.PeriodEnd, item.TxHash); err != nil {
+		return fmt.Errorf("failed to insert: %w", err)
+	}
+
+	return nil
+}
+
+// ListPDVRewardsHistory returns distributed rewards of the address ordered from the latest one.
+func (s pg) ListPDVRewardsHistory(ctx context.Context, addr string, from uint64, limit uint16) ([]*storage.PDVRewardsHistoryItem, error) {
+	if from == 0 {
+		from = math.MaxInt64
+	}
+
+	out := []*storage.PDVRewardsHistoryItem{}
+	if err := sqlx.SelectContext(ctx, s.ext, &out, `
+		SELECT id, address, amount, period_start, period_end, tx_hash, created_at FROM pdv_rewards_history
+		WHERE address = $1 AND id < $2
+		ORDER BY id DESC
+		LIMIT $3
+	`, addr, from, limit); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	return out, nil
+}
+
+// CreateProfileBanPDV saves pdv which triggered the profile ban.
+func (s pg) CreateProfileBanPDV(ctx context.Context, addr string, id uint64) error {
+	if _, err := s.ext.ExecContext(ctx, `
+		INSERT INTO profile_ban_pdv(address, pdv_id) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, addr, id); err != nil {
+		return fmt.Errorf("failed to insert: %w", err)
+	}
+
+	return nil
+}
+
+// ListProfileBanPDV returns ids of pdv which triggered the profile ban.
+func (s pg) ListProfileBanPDV(ctx context.Context, addr string) ([]uint64, error) {
+	out := []uint64{}
+	if err := sqlx.SelectContext(ctx, s.ext, &out, `
+		SELECT pdv_id FROM profile_ban_pdv WHERE address = $1 ORDER BY pdv_id DESC
+	`, addr); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	return out, nil
+}
+
+// DeleteProfileBanPDV deletes pdv which triggered the profile ban.
+func (s pg) DeleteProfileBanPDV(ctx context.Context, addr string) error {
+	if _, err := s.ext.ExecContext(ctx, `DELETE FROM profile_ban_pdv WHERE address = $1`, addr); err != nil {
+		return fmt.Errorf("failed to delete: %w", err)
+	}
+
+	return nil
+}
+
+// CreateAdminAuditItem writes the action into the audit.
+func (s pg) CreateAdminAuditItem(ctx context.Context, item *storage.AdminAuditItem) error {
+	if _, err := sqlx.NamedExecContext(ctx, s.ext, `
+		INSERT INTO admin_audit(actor, action, address, reason)
+		VALUES (:actor, :action, :address, :reason)
+	`, item); err != nil {
+		return fmt.Errorf("failed to insert: %w", err)
+	}
+
+	return nil
+}
+
+// GetDataKey returns owner's wrapped data key.
+func (s pg) GetDataKey(ctx context.Context, owner string) ([]byte, error) {
+	var key []byte
+	if err := sqlx.GetContext(ctx, s.ext, &key, `SELECT key FROM data_key WHERE owner = $1`, owner); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get: %w", err)
+	}
+
+	return key, nil
+}
+
+// CreateDataKey creates owner's wrapped data key. It does nothing if the owner already has a key.
+func (s pg) CreateDataKey(ctx context.Context, owner string, key []byte) error {
+	if _, err := s.ext.ExecContext(ctx, `
+		INSERT INTO data_key(owner, key) VALUES($1, $2) ON CONFLICT (owner) DO NOTHING
+	`, owner, key); err != nil {
+		return fmt.Errorf("failed to insert: %w", err)
+	}
+
+	return nil
+}
+
+// ListDataKeys returns data keys ordered by owner. Listing starts after the passed owner, empty means from the beginning.
+func (s pg) ListDataKeys(ctx context.Context, after string, limit uint16) ([]*storage.DataKey, error) {
+	var out []*storage.DataKey
+	if err := sqlx.SelectContext(ctx, s.ext, &out, `
+		SELECT owner, key FROM data_key
+		WHERE owner > $1
+		ORDER BY owner
+		LIMIT $2
+	`, after, limit); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	return out, nil
+}
+
+// UpdateDataKey replaces owner's wrapped data key if it's still equal to old one.
+// It returns ErrNotFound if the key was deleted or changed concurrently.
+func (s pg) UpdateDataKey(ctx context.Context, owner string, old, key []byte) error {
+	res, err := s.ext.ExecContext(ctx, `
+		UPDATE data_key SET key = $3 WHERE owner = $1 AND key = $2
+	`, owner, old, key)
+	if err != nil {
+		return fmt.Errorf("failed to update: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	} else if n == 0 {
+		return storage.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteDataKey deletes owner's data key, so owner's data can't be decrypted anymore.
+func (s pg) DeleteDataKey(ctx context.Context, owner string) error {
+	if _, err := s.ext.ExecContext(ctx, `DELETE FROM data_key WHERE owner = $1`, owner); err != nil {
+		return fmt.Errorf("failed to delete: %w", err)
+	}
+
+	return nil
+}
+
+// CreateQuarantineItem puts message which can't be processed into quarantine.
+func (s pg) CreateQuarantineItem(ctx context.Context, body, reason string, receiveCount int) error {
+	if _, err := s.ext.ExecContext(ctx, `
+		INSERT INTO pdv_quarantine(body, reason, receive_count) VALUES($1, $2, $3)
+	`, body, reason, receiveCount); err != nil {
+		return fmt.Errorf("failed to insert: %w", err)
+	}
+
+	return nil
+}
+
+// GetQuarantineItemList returns quarantined messages ordered by creation.
+func (s pg) GetQuarantineItemList(ctx context.Context) ([]*storage.QuarantineItem, error) {
+	var out []*storage.QuarantineItem
+	if err := sqlx.SelectContext(ctx, s.ext, &out, `
+		SELECT id, body, reason, receive_count, created_at FROM pdv_quarantine ORDER BY id
+	`); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	return out, nil
+}
+
+// DeleteQuarantineItem deletes message from quarantine.
+func (s pg) DeleteQuarantineItem(ctx context.Context, id uint64) error {
+	if _, err := s.ext.ExecContext(ctx, `DELETE FROM pdv_quarantine WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete: %w", err)
+	}
+
+	return nil
+}
+
+// CreateFraudRecheckItem saves pdv which skipped antifraud check.
+func (s pg) CreateFraudRecheckItem(ctx context.Context, item *storage.FraudRecheckItem) error {
+	if _, err := sqlx.NamedExecContext(ctx, s.ext, `
+		INSERT INTO pdv_fraud_recheck(address, pdv_id, held, message)
+		VALUES (:address, :pdv_id, :held, :message)
+		ON CONFLICT DO NOTHING
+	`, item); err != nil {
+		return fmt.Errorf("failed to insert: %w", err)
+	}
+
+	return nil
+}
+
+// GetFraudRecheckItemList returns the oldest pdv which skipped antifraud check and are due to be rechecked at now.
+// Failed items are skipped.
+func (s pg) GetFraudRecheckItemList(ctx context.Context, limit uint16, now time.Time) ([]*storage.FraudRecheckItem, error) {
+	var out []*storage.FraudRecheckItem
+	if err := sqlx.SelectContext(ctx, s.ext, &out, `
+		SELECT address, pdv_id, held, message, attempts, created_at FROM pdv_fraud_recheck
+		WHERE NOT failed AND next_attempt_at <= $2
+		ORDER BY created_at, address, pdv_id
+		LIMIT $1
+	`, limit, now); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	return out, nil
+}
+
+// DeleteFraudRecheckItem deletes checked pdv.
+func (s pg) DeleteFraudRecheckItem(ctx context.Context, address string, id uint64) error {
+	if _, err := s.ext.ExecContext(ctx, `
+		DELETE FROM pdv_fraud_recheck WHERE address = $1 AND pdv_id = $2
+	`, address, id); err != nil {
+		return fmt.Errorf("failed to delete: %w", err)
+	}
+
+	return nil
+}
+
+// PostponeFraudRecheckItem counts failed recheck and postpones the next one until nextAttemptAt.
+func (s pg) PostponeFraudRecheckItem(ctx context.Context, address string, id uint64, nextAttemptAt time.Time, reason string) error {
+	if _, err := s.ext.ExecContext(ctx, `
+		UPDATE pdv_fraud_recheck SET attempts = attempts + 1, next_attempt_at = $3, last_error = $4
+		WHERE address = $1 AND pdv_id = $2
+	`, address, id, nextAttemptAt, reason); err != nil {
+		return fmt.Errorf("failed to update: %w", err)
+	}
+
+	return nil
+}
+
+// FailFraudRecheckItem counts failed recheck and marks pdv as failed, so it isn't rechecked anymore.
+func (s pg) FailFraudRecheckItem(ctx context.Context, address string, id uint64, reason string) error {
+	if _, err := s.ext.ExecContext(ctx, `
+		UPDATE pdv_fraud_recheck SET attempts = attempts + 1, failed = true, last_error = $3
+		WHERE address = $1 AND pdv_id = $2
+	`, address, id, reason); err != nil {
+		return fmt.Errorf("failed to update: %w", err)
+	}
+
+	return nil
+}
+
+// GetPDVFingerprintList returns fingerprints of the address which were seen since the time.
+func (s pg) GetPDVFingerprintList(ctx context.Context, address string, fingerprints [][]byte, since time.Time) ([][]byte, error) {
+	var out pq.ByteaArray
+	if err := sqlx.SelectContext(ctx, s.ext, &out, `
+		SELECT fingerprint FROM pdv_fingerprint
+		WHERE address = $1 AND fingerprint = ANY($2) AND created_at >= $3
+	`, address, pq.ByteaArray(fingerprints), since); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	return out, nil
+}
+
+// SetPDVFingerprints saves fingerprints of the address, time of already saved fingerprints is updated.
+// Fingerprints should be unique.
+func (s pg) SetPDVFingerprints(ctx context.Context, address string, fingerprints [][]byte) error {
+	if _, err := s.ext.ExecContext(ctx, `
+		INSERT INTO pdv_fingerprint(address, fingerprint)
+		SELECT $1, UNNEST($2::BYTEA[])
+		ON CONFLICT (address, fingerprint) DO UPDATE SET created_at = CURRENT_TIMESTAMP
+	`, address, pq.ByteaArray(fingerprints)); err != nil {
+		return fmt.Errorf("failed to insert: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePDVFingerprints deletes fingerprints seen before the time.
+func (s pg) DeletePDVFingerprints(ctx context.Context, before time.Time) error {
+	if _, err := s.ext.ExecContext(ctx, `
+		DELETE FROM pdv_fingerprint WHERE created_at < $1
+	`, before); err != nil {
+		return fmt.Errorf("failed to delete: %w", err)
+	}
+
+	return nil
+}
+
+// CreateFraudVerdict saves verdict of shadow antifraud check.
+func (s pg) CreateFraudVerdict(ctx context.Context, v *storage.FraudVerdict) error {
+	if _, err := sqlx.NamedExecContext(ctx, s.ext, `
+		INSERT INTO fraud_verdict(address, pdv_id, verdict, rule, latency_ms)
+		VALUES (:address, :pdv_id, :verdict, :rule, :latency_ms)
+		ON CONFLICT DO NOTHING
+	`, v); err != nil {
+		return fmt.Errorf("failed to insert: %w", err)
+	}
+
+	return nil
+}
+
+// GetFraudVerdictOutcomeList returns shadow verdicts made since the time with live outcomes.
+// Live fraud is pdv which triggered the owner's ban and wasn't unbanned.
+func (s pg) GetFraudVerdictOutcomeList(ctx context.Context, since time.Time) ([]*storage.FraudVerdictOutcome, error) {
+	var out []*storage.FraudVerdictOutcome
+	if err := sqlx.SelectContext(ctx, s.ext, &out, `
+		SELECT
+			v.address, v.pdv_id, v.verdict, v.rule, v.latency_ms, v.created_at,
+			b.pdv_id IS NOT NULL AS live_fraud,
+			COALESCE(p.banned, FALSE) AS banned
+		FROM fraud_verdict v
+		LEFT JOIN profile_ban_pdv b ON b.address = v.address AND b.pdv_id = v.pdv_id
+		LEFT JOIN profile p ON p.address = v.address
+		WHERE v.created_at >= $1
+		ORDER BY v.created_at, v.address, v.pdv_id
+	`, since); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	return out, nil
+}
+
+// GetBlacklistEntryList returns all blacklist entries ordered by id.
+func (s pg) GetBlacklistEntryList(ctx context.Context) ([]*storage.BlacklistEntry, error) {
+	out := []*storage.BlacklistEntry{}
+	if err := sqlx.SelectContext(ctx, s.ext, &out, `
+		SELECT id, kind, pattern, created_by, created_at FROM blacklist ORDER BY id
+	`); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	return out, nil
+}
+
+// CreateBlacklistEntry creates blacklist entry and returns its id.
+// It returns ErrAlreadyExists if the entry with the same kind and pattern exists.
+func (s pg) CreateBlacklistEntry(ctx context.Context, e *storage.BlacklistEntry) (uint64, error) {
+	var id uint64
+	if err := sqlx.GetContext(ctx, s.ext, &id, `
+		INSERT INTO blacklist(kind, pattern, created_by) VALUES ($1, $2, $3)
+		ON CONFLICT (kind, pattern) DO NOTHING
+		RETURNING id
+	`, e.Kind, e.Pattern, e.CreatedBy); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, storage.ErrAlreadyExists
+		}
+		return 0, fmt.Errorf("failed to insert: %w", err)
+	}
+
+	return id, nil
+}
+
+// DeleteBlacklistEntry deletes blacklist entry and returns it.
+func (s pg) DeleteBlacklistEntry(ctx context.Context, id uint64) (*storage.BlacklistEntry, error) {
+	var e storage.BlacklistEntry
+	if err := sqlx.GetContext(ctx, s.ext, &e, `
+		DELETE FROM blacklist WHERE id = $1
+		RETURNING id, kind, pattern, created_by, created_at
+	`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to delete: %w", err)
+	}
+
+	return &e, nil
+}
+
+// CreateRewardTx writes pending rewards distribution transaction into the ledger.
+func (s pg) CreateRewardTx(ctx context.Context, items []*storage.RewardTxItem) (uint64, error) {
+	var id uint64
+	if err := sqlx.GetContext(ctx, s.ext, &id, `
+		INSERT INTO reward_tx(status) VALUES($1) RETURNING id
+	`, storage.RewardTxPending); err != nil {
+		return 0, fmt.Errorf("failed to insert tx: %w", err)
+	}
+
+	for _, v := range items {
+		b, err := json.Marshal(v.Meta)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal meta: %w", err)
+		}
+
+		if _, err := s.ext.ExecContext(ctx, `
+			INSERT INTO reward_tx_item(tx_id, owner, pdv_id, device, meta) VALUES($1, $2, $3, $4, $5)
+		`, id, v.Address, v.ID, v.Device, b); err != nil {
+			return 0, fmt.Errorf("failed to insert item: %w", err)
+		}
+	}
+
+	return id, nil
+}
+
+// SetRewardTxStatus updates status of rewards distribution transaction. Empty txHash keeps the previous one.
+func (s pg) SetRewardTxStatus(ctx context.Context, id uint64, status storage.RewardTxStatus, txHash, reason string) error {
+	res, err := s.ext.ExecContext(ctx, `
+		UPDATE reward_tx SET
+			status = $2,
+			tx_hash = COALESCE(NULLIF($3, ''), tx_hash),
+			reason = NULLIF($4, ''),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`, id, status, txHash, reason)
+	if err != nil {
+		return fmt.Errorf("failed to update: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	} else if n == 0 {
+		return storage.ErrNotFound
+	}
+
+	return nil
+}
+
+// HasActiveRewardTx checks if pdv is rewarded or is being rewarded by not failed transaction.
+func (s pg) HasActiveRewardTx(ctx context.Context, address string, id uint64) (bool, error) {
+	var exists bool
+	if err := sqlx.GetContext(ctx, s.ext, &exists, `
+		SELECT EXISTS(
+			SELECT 1 FROM reward_tx_item i
+			JOIN reward_tx t ON t.id = i.tx_id
+			WHERE i.owner = $1 AND i.pdv_id = $2 AND t.status != $3
+		)
+	`, address, id, storage.RewardTxFailed); err != nil {
+		return false, fmt.Errorf("failed to select: %w", err)
+	}
+
+	return exists, nil
+}
+
+// GetUnsettledRewardTxList returns pending and broadcast transactions which were updated before the time.
+func (s pg) GetUnsettledRewardTxList(ctx context.Context, before time.Time) ([]*storage.RewardTx, error) {
+	var txs []*rewardTxDTO
+	if err := sqlx.SelectContext(ctx, s.ext, &txs, `
+		SELECT id, tx_hash, status, updated_at FROM reward_tx
+		WHERE status IN ($1, $2) AND updated_at < $3
+		ORDER BY id
+	`, storage.RewardTxPending, storage.RewardTxBroadcast, before); err != nil {
+		return nil, fmt.Errorf("failed to select txs: %w", err)
+	}
+
+	if len(txs) == 0 {
+		return nil, nil
+	}
+
+	var (
+		ids = make([]int64, len(txs))
+		out = make([]*storage.RewardTx, len(txs))
+		m   = make(map[uint64]*storage.RewardTx, len(txs))
+	)
+
+	for i, v := range txs {
+		ids[i] = int64(v.ID)
+		out[i] = &storage.RewardTx{
+			ID:        v.ID,
+			TxHash:    v.TxHash.String,
+			Status:    v.Status,
+			UpdatedAt: v.UpdatedAt,
+		}
+		m[v.ID] = out[i]
+	}
+
+	var items []*rewardTxItemDTO
+	if err := sqlx.SelectContext(ctx, s.ext, &items, `
+		SELECT tx_id, owner, pdv_id, device, meta FROM reward_tx_item
+		WHERE tx_id = ANY($1)
+		ORDER BY tx_id, owner, pdv_id
+	`, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("failed to select items: %w", err)
+	}
+
+	for _, v := range items {
+		var meta entities.PDVMeta
+		if err := json.Unmarshal(v.Meta, &meta); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal meta: %w", err)
+		}
+
+		tx := m[v.TxID]
+		tx.Items = append(tx.Items, &storage.RewardTxItem{
+			Address: v.Owner,
+			ID:      v.PDVID,
+			Device:  v.Device,
+			Meta:    &meta,
+		})
+	}
+
+	return out, nil
+}
+
 func stringsUnique(s []string) []string {
 	m := make(map[string]struct{}, len(s))
 	out := make([]string, 0, len(s))
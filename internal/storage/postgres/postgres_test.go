@@ -120,6 +120,18 @@ func migrate(username, password, hostname, dbname string, port int) {
 func cleanup() {
 	db.MustExecContext(ctx, `DELETE FROM profile`)
 	db.MustExecContext(ctx, `DELETE FROM pdv`)
+	db.MustExecContext(ctx, `DELETE FROM data_key`)
+	db.MustExecContext(ctx, `DELETE FROM pdv_quarantine`)
+	db.MustExecContext(ctx, `DELETE FROM reward_tx`)
+	db.MustExecContext(ctx, `DELETE FROM rewards_queue`)
+	db.MustExecContext(ctx, `DELETE FROM pdv_rewards_history`)
+	db.MustExecContext(ctx, `DELETE FROM pdv_rewards_carry_over`)
+	db.MustExecContext(ctx, `DELETE FROM profile_ban_pdv`)
+	db.MustExecContext(ctx, `DELETE FROM admin_audit`)
+	db.MustExecContext(ctx, `DELETE FROM pdv_fraud_recheck`)
+	db.MustExecContext(ctx, `DELETE FROM fraud_verdict`)
+	db.MustExecContext(ctx, `DELETE FROM pdv_fingerprint`)
+	db.MustExecContext(ctx, `DELETE FROM blacklist`)
 }
 
 func TestPg_GetHeight(t *testing.T) {
@@ -173,11 +185,78 @@ func TestPg_SetProfileBanned(t *testing.T) {
 	require.NoError(t, err)
 	require.False(t, banned)
 
-	require.NoError(t, s.SetProfileBanned(ctx, p.Address))
+	require.NoError(t, s.SetProfileBanned(ctx, p.Address, storage.BanSourceManual, "spam"))
 
 	banned, err = s.IsProfileBanned(ctx, p.Address)
 	require.NoError(t, err)
 	require.True(t, banned)
+
+	// profile is kept
+	profile, err := s.GetProfile(ctx, p.Address)
+	require.NoError(t, err)
+	require.Equal(t, p.FirstName, profile.FirstName)
+
+	// account without profile is banned too
+	require.NoError(t, s.SetProfileBanned(ctx, "address_2", storage.BanSourceHades, "fraud"))
+
+	banned, err = s.IsProfileBanned(ctx, "address_2")
+	require.NoError(t, err)
+	require.True(t, banned)
+
+	list, err := s.ListBannedProfiles(ctx, "", 10)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, p.Address, list[0].Address)
+	require.Equal(t, storage.BanSourceManual, list[0].Source)
+	require.Equal(t, "spam", list[0].Reason)
+	require.Equal(t, "address_2", list[1].Address)
+	require.Equal(t, storage.BanSourceHades, list[1].Source)
+	require.False(t, list[0].BannedAt.IsZero())
+
+	list, err = s.ListBannedProfiles(ctx, p.Address, 10)
+	require.NoError(t, err)
+	require.Empty(t, list)
+
+	require.NoError(t, s.UnsetProfileBanned(ctx, p.Address))
+	require.ErrorIs(t, s.UnsetProfileBanned(ctx, p.Address), storage.ErrNotFound)
+
+	banned, err = s.IsProfileBanned(ctx, p.Address)
+	require.NoError(t, err)
+	require.False(t, banned)
+}
+
+func TestPg_ProfileBanPDV(t *testing.T) {
+	t.Cleanup(cleanup)
+
+	require.NoError(t, s.CreateProfileBanPDV(ctx, "address", 1))
+	require.NoError(t, s.CreateProfileBanPDV(ctx, "address", 2))
+	require.NoError(t, s.CreateProfileBanPDV(ctx, "address", 2))
+	require.NoError(t, s.CreateProfileBanPDV(ctx, "address2", 3))
+
+	ids, err := s.ListProfileBanPDV(ctx, "address")
+	require.NoError(t, err)
+	require.Equal(t, []uint64{2, 1}, ids)
+
+	require.NoError(t, s.DeleteProfileBanPDV(ctx, "address"))
+
+	ids, err = s.ListProfileBanPDV(ctx, "address")
+	require.NoError(t, err)
+	require.Empty(t, ids)
+}
+
+func TestPg_CreateAdminAuditItem(t *testing.T) {
+	t.Cleanup(cleanup)
+
+	require.NoError(t, s.CreateAdminAuditItem(ctx, &storage.AdminAuditItem{
+		Actor:   "admin",
+		Action:  "ban",
+		Address: "address",
+		Reason:  "spam",
+	}))
+
+	var actions []string
+	require.NoError(t, db.SelectContext(ctx, &actions, `SELECT actor || ':' || action || ':' || address || ':' || reason FROM admin_audit`))
+	require.Equal(t, []string{"admin:ban:address:spam"}, actions)
 }
 
 func TestPg_SetProfile(t *testing.T) {
@@ -366,7 +445,7 @@ func TestPg_GetPDVDeltaTotal(t *testing.T) {
 	t.Run("zero", func(t *testing.T) {
 		total, err := s.GetPDVTotalDelta(ctx)
 		require.NoError(t, err)
-		require.Zero(t, total)
+		require.True(t, total.IsZero())
 	})
 
 	t.Run("3 different accounts", func(t *testing.T) {
@@ -382,15 +461,14 @@ func TestPg_GetPDVDeltaTotal(t *testing.T) {
 
 		total, err := s.GetPDVTotalDelta(ctx)
 		require.NoError(t, err)
-		require.Equal(t, 6e-06, total)
+		require.Equal(t, sdk.NewDecWithPrec(6, 6).String(), total.String())
 
 		// update distribution date
 		require.NoError(t, s.SetPDVRewardsDistributedDate(ctx, time.Now().UTC()))
 
 		total, err = s.GetPDVTotalDelta(ctx)
 		require.NoError(t, err)
-		require.NoError(t, err)
-		require.Zero(t, total)
+		require.True(t, total.IsZero())
 	})
 }
 
@@ -399,7 +477,7 @@ func TestPg_GetPDVDelta(t *testing.T) {
 
 	total, err := s.GetPDVDelta(ctx, "")
 	require.NoError(t, err)
-	require.Zero(t, total)
+	require.True(t, total.IsZero())
 
 	t.Run("2 pdvs", func(t *testing.T) {
 		const addr = "address"
@@ -413,22 +491,23 @@ func TestPg_GetPDVDelta(t *testing.T) {
 
 		delta, err := s.GetPDVDelta(ctx, addr)
 		require.NoError(t, err)
-		require.Equal(t, 3e-06, delta)
+		require.Equal(t, sdk.NewDecWithPrec(3, 6).String(), delta.String())
 
 		// update distribution date
 		require.NoError(t, s.SetPDVRewardsDistributedDate(ctx, time.Now().UTC()))
 
 		delta, err = s.GetPDVDelta(ctx, addr)
 		require.NoError(t, err)
-		require.NoError(t, err)
-		require.Zero(t, delta)
+		require.True(t, delta.IsZero())
 	})
 }
 
 func TestPg_GetPDVDeltaList(t *testing.T) {
 	t.Cleanup(cleanup)
 
-	deltas, err := s.GetPDVDeltaList(ctx)
+	since, until := time.Now().UTC().Add(-time.Hour), time.Now().UTC().Add(time.Hour)
+
+	deltas, err := s.GetPDVDeltaList(ctx, since, until)
 	require.NoError(t, err)
 	require.Len(t, deltas, 0)
 
@@ -445,25 +524,107 @@ func TestPg_GetPDVDeltaList(t *testing.T) {
 		Reward: sdk.NewDecWithPrec(3, 6),
 	}))
 
-	deltas, err = s.GetPDVDeltaList(ctx)
+	require.NoError(t, s.SetProfile(ctx, &storage.SetProfileParams{
+		Address:   "address3",
+		FirstName: "first_name",
+		LastName:  "last_name",
+		Emails:    []string{"email"},
+		Bio:       "bio",
+		Avatar:    "avatar",
+		Gender:    "male",
+		Birthday:  date("2009-01-02"),
+	}))
+	require.NoError(t, s.SetProfileBanned(ctx, "address3", storage.BanSourceHades, "fraud"))
+
+	deltas, err = s.GetPDVDeltaList(ctx, since, until)
 	require.NoError(t, err)
 	require.Len(t, deltas, 3)
 
-	require.Equal(t, &storage.PDVDelta{Address: "address1", Delta: 0.000001}, deltas[0])
-	require.Equal(t, &storage.PDVDelta{Address: "address2", Delta: 0.000022}, deltas[1])
-	require.Equal(t, &storage.PDVDelta{Address: "address3", Delta: 0.000003}, deltas[2])
+	require.NotNil(t, deltas[2].AccountCreatedAt)
+	deltas[2].AccountCreatedAt = nil
+
+	requireDeltas(t, []*storage.PDVDelta{
+		{Address: "address1", Delta: sdk.NewDecWithPrec(1, 6)},
+		{Address: "address2", Delta: sdk.NewDecWithPrec(22, 6)},
+		{Address: "address3", Delta: sdk.NewDecWithPrec(3, 6), Banned: true},
+	}, deltas)
+
+	// pdv created after the cutoff are left for the next distribution
+	deltas, err = s.GetPDVDeltaList(ctx, since, since.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, deltas, 0)
+}
+
+func TestPg_PDVRewardsCarryOver(t *testing.T) {
+	t.Cleanup(cleanup)
+
+	require.NoError(t, s.SetPDVMeta(ctx, "address1", 1, "trx1", "ios", &entities.PDVMeta{
+		Reward: sdk.NewDecWithPrec(1, 6),
+	}))
+	require.NoError(t, s.CreatePDVRewardsCarryOverItem(ctx, "address1", sdk.NewDecWithPrec(2, 6)))
+	require.NoError(t, s.CreatePDVRewardsCarryOverItem(ctx, "address2", sdk.NewDecWithPrec(5, 6)))
+
+	since, until := time.Now().UTC().Add(-time.Hour), time.Now().UTC().Add(time.Hour)
+
+	deltas, err := s.GetPDVDeltaList(ctx, since, until)
+	require.NoError(t, err)
+	requireDeltas(t, []*storage.PDVDelta{
+		{Address: "address1", Delta: sdk.NewDecWithPrec(3, 6)},
+		{Address: "address2", Delta: sdk.NewDecWithPrec(5, 6)},
+	}, deltas)
+
+	require.NoError(t, s.DeletePDVRewardsCarryOverList(ctx))
+
+	deltas, err = s.GetPDVDeltaList(ctx, since, until)
+	require.NoError(t, err)
+	requireDeltas(t, []*storage.PDVDelta{
+		{Address: "address1", Delta: sdk.NewDecWithPrec(1, 6)},
+	}, deltas)
+}
+
+func requireDeltas(t *testing.T, expected, actual []*storage.PDVDelta) {
+	require.Len(t, actual, len(expected))
+	for i, v := range expected {
+		require.Equal(t, v.Address, actual[i].Address)
+		require.Equal(t, v.Delta.String(), actual[i].Delta.String())
+		require.Equal(t, v.Banned, actual[i].Banned)
+	}
 }
 
 func TestPg_RewardsQueue(t *testing.T) {
 	t.Cleanup(cleanup)
 
-	require.NoError(t, s.CreateRewardsQueueItem(ctx, "address1", 5))
-	require.NoError(t, s.CreateRewardsQueueItem(ctx, "address2", 10))
-	require.NoError(t, s.CreateRewardsQueueItem(ctx, "address3", 15))
+	start, end := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, s.CreateRewardsQueueItem(ctx, "address1", 5, start, end))
+	require.NoError(t, s.CreateRewardsQueueItem(ctx, "address2", 10, start, end))
+	require.NoError(t, s.CreateRewardsQueueItem(ctx, "address3", 15, start, end))
 
 	items, err := s.GetRewardsQueueItemList(ctx)
 	require.NoError(t, err)
 	require.Len(t, items, 3)
+	require.True(t, start.Equal(items[0].PeriodStart))
+	require.True(t, end.Equal(items[0].PeriodEnd))
+	require.Nil(t, items[0].SentAt)
+
+	sentAt := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, s.SetRewardsQueueItemsSent(ctx, []string{"address1", "address2"}, sentAt))
+	require.NoError(t, s.SetRewardsQueueItemsTx(ctx, []string{"address1"}, "tx"))
+	require.NoError(t, s.ResetRewardsQueueItems(ctx, []string{"address3"}))
+
+	items, err = s.GetRewardsQueueItemList(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "tx", items[0].TxHash)
+	require.True(t, sentAt.Equal(*items[0].SentAt))
+	require.Empty(t, items[1].TxHash)
+	require.True(t, sentAt.Equal(*items[1].SentAt))
+	require.Nil(t, items[2].SentAt)
+
+	require.NoError(t, s.ResetRewardsQueueItems(ctx, []string{"address1"}))
+	items, err = s.GetRewardsQueueItemList(ctx)
+	require.NoError(t, err)
+	require.Empty(t, items[0].TxHash)
+	require.Nil(t, items[0].SentAt)
 
 	require.NoError(t, s.DeleteRewardsQueueItem(ctx, "address1"))
 	require.NoError(t, s.DeleteRewardsQueueItem(ctx, "address2"))
@@ -474,6 +635,45 @@ func TestPg_RewardsQueue(t *testing.T) {
 	require.Len(t, items, 0)
 }
 
+func TestPg_PDVRewardsHistory(t *testing.T) {
+	t.Cleanup(cleanup)
+
+	start, end := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, s.CreatePDVRewardsHistoryItem(ctx, &storage.PDVRewardsHistoryItem{
+			Address:     "address1",
+			Amount:      int64(i),
+			PeriodStart: start,
+			PeriodEnd:   end,
+			TxHash:      fmt.Sprintf("tx%d", i),
+		}))
+	}
+	require.NoError(t, s.CreatePDVRewardsHistoryItem(ctx, &storage.PDVRewardsHistoryItem{
+		Address: "address2",
+		Amount:  10,
+		TxHash:  "tx",
+	}))
+
+	items, err := s.ListPDVRewardsHistory(ctx, "address1", 0, 2)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.EqualValues(t, 3, items[0].Amount)
+	require.Equal(t, "tx3", items[0].TxHash)
+	require.True(t, start.Equal(items[0].PeriodStart))
+	require.True(t, end.Equal(items[0].PeriodEnd))
+	require.EqualValues(t, 2, items[1].Amount)
+
+	items, err = s.ListPDVRewardsHistory(ctx, "address1", items[1].ID, 2)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.EqualValues(t, 1, items[0].Amount)
+
+	items, err = s.ListPDVRewardsHistory(ctx, "address3", 0, 2)
+	require.NoError(t, err)
+	require.Empty(t, items)
+}
+
 func TestPg_ListPDV(t *testing.T) {
 	t.Cleanup(cleanup)
 
@@ -499,6 +699,147 @@ func TestPg_ListPDV(t *testing.T) {
 	require.Empty(t, ids)
 }
 
+func TestPg_ListAllPDV(t *testing.T) {
+	t.Cleanup(cleanup)
+
+	for _, owner := range []string{"2", "1"} {
+		for i := 1; i <= 2; i++ {
+			require.NoError(t, s.SetPDVMeta(ctx, owner, uint64(i), "tx", "ios", &entities.PDVMeta{
+				ObjectTypes: map[schema.Type]uint16{
+					"cookie": 1,
+				},
+				Reward: sdk.NewDecWithPrec(1, 6),
+			}))
+		}
+	}
+
+	list, err := s.ListAllPDV(ctx, nil, 3)
+	require.NoError(t, err)
+	require.Equal(t, []*storage.PDVIndex{{Owner: "1", ID: 1}, {Owner: "1", ID: 2}, {Owner: "2", ID: 1}}, list)
+
+	list, err = s.ListAllPDV(ctx, list[2], 3)
+	require.NoError(t, err)
+	require.Equal(t, []*storage.PDVIndex{{Owner: "2", ID: 2}}, list)
+
+	list, err = s.ListAllPDV(ctx, list[0], 3)
+	require.NoError(t, err)
+	require.Empty(t, list)
+}
+
+func TestPg_DataKey(t *testing.T) {
+	t.Cleanup(cleanup)
+
+	_, err := s.GetDataKey(ctx, "1")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	require.NoError(t, s.CreateDataKey(ctx, "1", []byte{1}))
+	require.NoError(t, s.CreateDataKey(ctx, "1", []byte{2}))
+
+	key, err := s.GetDataKey(ctx, "1")
+	require.NoError(t, err)
+	require.Equal(t, []byte{1}, key)
+
+	require.NoError(t, s.CreateDataKey(ctx, "2", []byte{3}))
+
+	keys, err := s.ListDataKeys(ctx, "", 1)
+	require.NoError(t, err)
+	require.Equal(t, []*storage.DataKey{{Owner: "1", Key: []byte{1}}}, keys)
+
+	keys, err = s.ListDataKeys(ctx, "1", 10)
+	require.NoError(t, err)
+	require.Equal(t, []*storage.DataKey{{Owner: "2", Key: []byte{3}}}, keys)
+
+	// key is updated only if it isn't changed concurrently
+	require.ErrorIs(t, s.UpdateDataKey(ctx, "1", []byte{2}, []byte{4}), storage.ErrNotFound)
+	require.NoError(t, s.UpdateDataKey(ctx, "1", []byte{1}, []byte{4}))
+
+	key, err = s.GetDataKey(ctx, "1")
+	require.NoError(t, err)
+	require.Equal(t, []byte{4}, key)
+
+	require.NoError(t, s.DeleteDataKey(ctx, "1"))
+
+	_, err = s.GetDataKey(ctx, "1")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestPg_Quarantine(t *testing.T) {
+	t.Cleanup(cleanup)
+
+	require.NoError(t, s.CreateQuarantineItem(ctx, "body1", "reason1", 1))
+	require.NoError(t, s.CreateQuarantineItem(ctx, "body2", "reason2", 10))
+
+	items, err := s.GetQuarantineItemList(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.Equal(t, "body1", items[0].Body)
+	require.Equal(t, "reason1", items[0].Reason)
+	require.Equal(t, 1, items[0].ReceiveCount)
+	require.Equal(t, "body2", items[1].Body)
+
+	require.NoError(t, s.DeleteQuarantineItem(ctx, items[0].ID))
+
+	items, err = s.GetQuarantineItemList(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, "body2", items[0].Body)
+}
+
+func TestPg_RewardTx(t *testing.T) {
+	t.Cleanup(cleanup)
+
+	meta := &entities.PDVMeta{
+		ObjectTypes: map[schema.Type]uint16{
+			"cookie": 1,
+		},
+		Reward: sdk.NewDecWithPrec(1, 6),
+	}
+
+	id, err := s.CreateRewardTx(ctx, []*storage.RewardTxItem{
+		{Address: "1", ID: 1, Device: "ios", Meta: meta},
+		{Address: "2", ID: 2, Device: "android", Meta: meta},
+	})
+	require.NoError(t, err)
+
+	ok, err := s.HasActiveRewardTx(ctx, "1", 1)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = s.HasActiveRewardTx(ctx, "1", 2)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, s.SetRewardTxStatus(ctx, id, storage.RewardTxBroadcast, "hash", ""))
+
+	txs, err := s.GetUnsettledRewardTxList(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, txs)
+
+	txs, err = s.GetUnsettledRewardTxList(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	require.Equal(t, id, txs[0].ID)
+	require.Equal(t, "hash", txs[0].TxHash)
+	require.Equal(t, storage.RewardTxBroadcast, txs[0].Status)
+	require.Len(t, txs[0].Items, 2)
+	require.Equal(t, "1", txs[0].Items[0].Address)
+	require.EqualValues(t, 1, txs[0].Items[0].ID)
+	require.Equal(t, "ios", txs[0].Items[0].Device)
+	require.Equal(t, meta, txs[0].Items[0].Meta)
+
+	require.NoError(t, s.SetRewardTxStatus(ctx, id, storage.RewardTxFailed, "", "reason"))
+
+	txs, err = s.GetUnsettledRewardTxList(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, txs)
+
+	ok, err = s.HasActiveRewardTx(ctx, "1", 1)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.ErrorIs(t, s.SetRewardTxStatus(ctx, id+1, storage.RewardTxFailed, "", ""), storage.ErrNotFound)
+}
+
 func TestPg_DeletePDV(t *testing.T) {
 	t.Cleanup(cleanup)
 
@@ -525,3 +866,165 @@ func date(d string) *time.Time {
 	}
 	return &t
 }
+
+func TestPg_FraudRecheck(t *testing.T) {
+	t.Cleanup(cleanup)
+
+	require.NoError(t, s.CreateFraudRecheckItem(ctx, &storage.FraudRecheckItem{
+		Address: "address", ID: 1, Held: true, Message: []byte("message1"),
+	}))
+	require.NoError(t, s.CreateFraudRecheckItem(ctx, &storage.FraudRecheckItem{
+		Address: "address", ID: 2, Message: []byte("message2"),
+	}))
+
+	now := time.Now().UTC()
+
+	items, err := s.GetFraudRecheckItemList(ctx, 10, now)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.Equal(t, uint64(1), items[0].ID)
+	require.True(t, items[0].Held)
+	require.Equal(t, []byte("message1"), items[0].Message)
+	require.Zero(t, items[0].Attempts)
+	require.Equal(t, uint64(2), items[1].ID)
+	require.False(t, items[1].Held)
+
+	require.NoError(t, s.DeleteFraudRecheckItem(ctx, "address", 1))
+
+	items, err = s.GetFraudRecheckItemList(ctx, 10, now)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, uint64(2), items[0].ID)
+
+	// postponed item isn't due
+	require.NoError(t, s.PostponeFraudRecheckItem(ctx, "address", 2, now.Add(time.Hour), "error"))
+
+	items, err = s.GetFraudRecheckItemList(ctx, 10, now)
+	require.NoError(t, err)
+	require.Empty(t, items)
+
+	items, err = s.GetFraudRecheckItemList(ctx, 10, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, 1, items[0].Attempts)
+
+	// failed item isn't rechecked anymore
+	require.NoError(t, s.FailFraudRecheckItem(ctx, "address", 2, "error"))
+
+	items, err = s.GetFraudRecheckItemList(ctx, 10, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, items)
+}
+
+func TestPg_PDVFingerprint(t *testing.T) {
+	t.Cleanup(cleanup)
+
+	since := time.Now().UTC().Add(-time.Hour)
+
+	require.NoError(t, s.SetPDVFingerprints(ctx, "address", [][]byte{[]byte("1"), []byte("2")}))
+	require.NoError(t, s.SetPDVFingerprints(ctx, "address", [][]byte{[]byte("2"), []byte("3")}))
+	require.NoError(t, s.SetPDVFingerprints(ctx, "address2", [][]byte{[]byte("4")}))
+
+	list, err := s.GetPDVFingerprintList(ctx, "address", [][]byte{[]byte("1"), []byte("3"), []byte("4")}, since)
+	require.NoError(t, err)
+	require.ElementsMatch(t, [][]byte{[]byte("1"), []byte("3")}, list)
+
+	list, err = s.GetPDVFingerprintList(ctx, "address", [][]byte{[]byte("1")}, time.Now().UTC().Add(time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, list)
+
+	require.NoError(t, s.DeletePDVFingerprints(ctx, time.Now().UTC().Add(time.Hour)))
+
+	list, err = s.GetPDVFingerprintList(ctx, "address2", [][]byte{[]byte("4")}, since)
+	require.NoError(t, err)
+	require.Empty(t, list)
+}
+
+func TestPg_Blacklist(t *testing.T) {
+	t.Cleanup(cleanup)
+	cleanup() // the migration adds default entries
+
+	id, err := s.CreateBlacklistEntry(ctx, &storage.BlacklistEntry{
+		Kind: storage.BlacklistKindCookieSource, Pattern: "*.youtube.com", CreatedBy: "admin",
+	})
+	require.NoError(t, err)
+
+	_, err = s.CreateBlacklistEntry(ctx, &storage.BlacklistEntry{
+		Kind: storage.BlacklistKindCookieSource, Pattern: "*.youtube.com", CreatedBy: "admin2",
+	})
+	require.ErrorIs(t, err, storage.ErrAlreadyExists)
+
+	_, err = s.CreateBlacklistEntry(ctx, &storage.BlacklistEntry{
+		Kind: storage.BlacklistKindSearchEngine, Pattern: "bing", CreatedBy: "admin",
+	})
+	require.NoError(t, err)
+
+	list, err := s.GetBlacklistEntryList(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, id, list[0].ID)
+	require.Equal(t, storage.BlacklistKindCookieSource, list[0].Kind)
+	require.Equal(t, "*.youtube.com", list[0].Pattern)
+	require.Equal(t, "admin", list[0].CreatedBy)
+	require.Equal(t, storage.BlacklistKindSearchEngine, list[1].Kind)
+
+	e, err := s.DeleteBlacklistEntry(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, "*.youtube.com", e.Pattern)
+
+	_, err = s.DeleteBlacklistEntry(ctx, id)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	list, err = s.GetBlacklistEntryList(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+}
+
+func TestPg_FraudVerdict(t *testing.T) {
+	t.Cleanup(cleanup)
+
+	require.NoError(t, s.SetProfile(ctx, &storage.SetProfileParams{
+		Address:   "address",
+		FirstName: "first_name",
+		Emails:    []string{"email"},
+		Gender:    "male",
+		Birthday:  date("2009-01-02"),
+	}))
+	require.NoError(t, s.SetProfileBanned(ctx, "address", storage.BanSourceHades, "fraud detected"))
+	require.NoError(t, s.CreateProfileBanPDV(ctx, "address", 1))
+
+	require.NoError(t, s.CreateFraudVerdict(ctx, &storage.FraudVerdict{
+		Address: "address", ID: 1, Verdict: storage.FraudVerdictFraud, Rule: "rule", LatencyMS: 10,
+	}))
+	require.NoError(t, s.CreateFraudVerdict(ctx, &storage.FraudVerdict{
+		Address: "address", ID: 2, Verdict: storage.FraudVerdictClean, LatencyMS: 20,
+	}))
+	require.NoError(t, s.CreateFraudVerdict(ctx, &storage.FraudVerdict{
+		Address: "address2", ID: 3, Verdict: storage.FraudVerdictError,
+	}))
+
+	items, err := s.GetFraudVerdictOutcomeList(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+
+	require.Equal(t, "address", items[0].Address)
+	require.Equal(t, uint64(1), items[0].ID)
+	require.Equal(t, storage.FraudVerdictFraud, items[0].Verdict)
+	require.Equal(t, "rule", items[0].Rule)
+	require.EqualValues(t, 10, items[0].LatencyMS)
+	require.True(t, items[0].LiveFraud)
+	require.True(t, items[0].Banned)
+
+	require.Equal(t, uint64(2), items[1].ID)
+	require.Equal(t, storage.FraudVerdictClean, items[1].Verdict)
+	require.False(t, items[1].LiveFraud)
+	require.True(t, items[1].Banned)
+
+	require.Equal(t, "address2", items[2].Address)
+	require.False(t, items[2].LiveFraud)
+	require.False(t, items[2].Banned)
+
+	items, err = s.GetFraudVerdictOutcomeList(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, items)
+}
@@ -15,6 +15,9 @@ import (
 	"github.com/Decentr-net/cerberus/internal/storage"
 )
 
+// partSize is size of part for multipart upload, it's a size of buffer allocated by upload as well.
+const partSize = 16 * 1024 * 1024
+
 var _ storage.FileStorage = &s3{}
 
 type s3 struct {
@@ -69,9 +72,11 @@ func (s s3) Read(ctx context.Context, path string) (io.ReadCloser, error) {
 }
 
 // Write puts file into s3 storage.
+// Negative size means that size is unknown, file is uploaded with multipart upload by parts of partSize then.
 func (s s3) Write(ctx context.Context, r io.Reader, size int64, path string, contentType string, isPublicRead bool) (string, error) {
 	opt := minio.PutObjectOptions{
-		DisableMultipart: true,
+		DisableMultipart: size >= 0,
+		PartSize:         partSize,
 		ContentType:      contentType,
 	}
 	if isPublicRead {
@@ -85,6 +90,11 @@ func (s s3) Write(ctx context.Context, r io.Reader, size int64, path string, con
 	return fmt.Sprintf("https://%s.s3.amazonaws.com/%s", i.Bucket, i.Key), nil
 }
 
+// Delete ...
+func (s s3) Delete(ctx context.Context, path string) error {
+	return s.c.RemoveObject(ctx, s.b, path, minio.RemoveObjectOptions{})
+}
+
 // DeleteData ...
 func (s s3) DeleteData(ctx context.Context, address string) error {
 	ch := s.c.ListObjects(ctx, s.b, minio.ListObjectsOptions{
@@ -111,6 +111,25 @@ func TestS3_Write(t *testing.T) {
 	require.NotEmpty(t, path)
 }
 
+func TestS3_Write_UnknownSize(t *testing.T) {
+	s, err := NewStorage(c, bucket)
+	require.NoError(t, err)
+
+	text := bytes.Repeat([]byte("cerberus"), partSize/4) // two parts
+
+	_, err = s.Write(ctx, ioutil.NopCloser(bytes.NewReader(text)), -1, "unknown_size", "binary/octet-stream", false)
+	require.NoError(t, err)
+
+	rc, err := s.Read(ctx, "unknown_size")
+	require.NoError(t, err)
+
+	b, err := ioutil.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, text, b)
+
+	assert.NoError(t, rc.Close())
+}
+
 func TestS3_Read(t *testing.T) {
 	s, err := NewStorage(c, bucket)
 	require.NoError(t, err)
@@ -154,6 +173,19 @@ func TestS3_Write_Read(t *testing.T) {
 	assert.NoError(t, rc.Close())
 }
 
+func TestS3_Delete(t *testing.T) {
+	s, err := NewStorage(c, bucket)
+	require.NoError(t, err)
+
+	_, err = s.Write(ctx, bytes.NewReader([]byte("cerberus")), 8, "owner/pdv/0000000000000001", "binary/octet-stream", false)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Delete(ctx, "owner/pdv/0000000000000001"))
+
+	_, err = s.Read(ctx, "owner/pdv/0000000000000001")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+}
+
 func TestS3_DeleteData(t *testing.T) {
 	s, err := NewStorage(c, bucket)
 	require.NoError(t, err)
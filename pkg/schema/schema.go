@@ -9,6 +9,7 @@ import (
 
 	"github.com/Decentr-net/cerberus/pkg/schema/types"
 	v1 "github.com/Decentr-net/cerberus/pkg/schema/v1"
+	v2 "github.com/Decentr-net/cerberus/pkg/schema/v2"
 )
 
 // nolint
@@ -18,11 +19,14 @@ type (
 	Type    = types.Type
 	Source  = types.Source
 	Version = types.Version
+
+	FieldError = types.FieldError
 )
 
 // nolint
 const (
 	V1 = v1.Version
+	V2 = v2.Version
 )
 
 // nolint
@@ -32,6 +36,12 @@ const (
 	PDVLocationType      = types.PDVLocationType
 	PDVProfileType       = types.PDVProfileType
 	PDVSearchHistoryType = types.PDVSearchHistoryType
+
+	PDVBrowsingHistoryType = types.PDVBrowsingHistoryType
+	PDVBookmarkType        = types.PDVBookmarkType
+	PDVInstalledAppType    = types.PDVInstalledAppType
+	PDVDeviceInfoType      = types.PDVDeviceInfoType
+	PDVInterestType        = types.PDVInterestType
 )
 
 // nolint
@@ -41,12 +51,19 @@ type (
 	V1Location      = v1.Location
 	V1Profile       = v1.Profile
 	V1SearchHistory = v1.SearchHistory
+
+	V2BrowsingHistory = v2.BrowsingHistory
+	V2Bookmark        = v2.Bookmark
+	V2InstalledApp    = v2.InstalledApp
+	V2DeviceInfo      = v2.DeviceInfo
+	V2Interest        = v2.Interest
 )
 
 // nolint: gochecknoglobals
 var (
 	pdvObjectSchemes = map[Version]PDV{
 		V1: v1.PDV{},
+		V2: v2.PDV{},
 	}
 )
 
@@ -117,11 +134,24 @@ func (p *PDVWrapper) UnmarshalJSON(b []byte) error {
 
 // Validate returns true if pdv is valid.
 func (p PDVWrapper) Validate() bool {
+	return len(p.ValidateWithErrors()) == 0
+}
+
+// ValidateWithErrors returns errors of invalid fields, paths start with device or pdv (e.g. pdv[1].name).
+func (p PDVWrapper) ValidateWithErrors() []FieldError {
+	var errs []FieldError
+
 	switch p.Device {
 	case "", "ios", "android", "desktop":
-		return p.pdv.Validate()
+	default:
+		errs = append(errs, types.InvalidError("device", "must be ios, android or desktop"))
+	}
+
+	if p.pdv == nil {
+		return append(errs, types.RequiredError("pdv"))
 	}
-	return false
+
+	return append(errs, types.NestFieldErrors("pdv", p.pdv.ValidateWithErrors())...)
 }
 
 // Version ...
@@ -134,6 +164,64 @@ func (p PDVWrapper) Data() []Data {
 	return p.pdv.Data()
 }
 
+// ToV2 returns a copy of the wrapper with pdv converted to v2.
+func (p PDVWrapper) ToV2() (PDVWrapper, error) {
+	pdv, err := ToV2(p.pdv)
+	if err != nil {
+		return PDVWrapper{}, err
+	}
+
+	return NewPDVWrapper(p.Device, pdv), nil
+}
+
+// ToV2 converts pdv to v2.
+func ToV2(p PDV) (PDV, error) {
+	switch v := p.(type) {
+	case v1.PDV:
+		return v2.FromV1(v), nil
+	case *v1.PDV:
+		return v2.FromV1(*v), nil
+	case v2.PDV:
+		return v, nil
+	case *v2.PDV:
+		return *v, nil
+	default:
+		return nil, fmt.Errorf("failed to convert pdv %T to v2", p)
+	}
+}
+
+// GetPDVErrors returns errors of invalid pdv, paths start with pdv and the index of pdv (e.g. pdv[1].name).
+func GetPDVErrors(b []byte) ([]FieldError, error) {
+	var i struct {
+		Version Version `json:"version"`
+
+		PDV json.RawMessage `json:"pdv"`
+	}
+
+	if err := json.Unmarshal(b, &i); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal PDV meta: %w", err)
+	}
+
+	var (
+		errs []FieldError
+		err  error
+	)
+
+	switch i.Version {
+	case V1:
+		errs, err = v1.GetPDVErrors(i.PDV)
+	case V2:
+		errs, err = v2.GetPDVErrors(i.PDV)
+	default:
+		return nil, fmt.Errorf("invalid version")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NestFieldErrors("pdv", errs), nil
+}
+
 // GetInvalidPDV return indices  of invalid pdv.
 func GetInvalidPDV(b []byte) ([]int, error) {
 	var i struct {
@@ -149,6 +237,8 @@ func GetInvalidPDV(b []byte) ([]int, error) {
 	switch i.Version {
 	case V1:
 		return v1.GetInvalidPDV(i.PDV)
+	case V2:
+		return v2.GetInvalidPDV(i.PDV)
 	default:
 		return nil, fmt.Errorf("invalid version")
 	}
@@ -117,3 +117,140 @@ func Test_GetInvalidPDV(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, s, []int{0})
 }
+
+func TestPDVWrapper_V2(t *testing.T) {
+	data := `
+{
+	"version": "v2",
+	"device": "desktop",
+	"pdv": [
+		{
+			"timestamp": "2021-05-11T11:05:18Z",
+			"type": "searchHistory",
+			"engine": "decentr",
+			"domain": "decentr.xyz",
+			"query": "the best crypto"
+		},
+		{
+			"timestamp": "2021-05-11T11:05:18Z",
+			"type": "browsingHistory",
+			"url": "https://decentr.xyz/",
+			"title": "Decentr",
+			"visitCount": 3,
+			"duration": 120
+		}
+	]
+}`
+	var p PDVWrapper
+	require.NoError(t, json.Unmarshal([]byte(data), &p))
+	require.Equal(t, V2, p.Version())
+	require.True(t, p.Validate())
+
+	require.IsType(t, &V1SearchHistory{}, p.Data()[0])
+	require.IsType(t, &V2BrowsingHistory{}, p.Data()[1])
+
+	d, err := json.Marshal(p)
+	require.NoError(t, err)
+	assert.JSONEq(t, data, string(d))
+}
+
+func TestPDVWrapper_ToV2(t *testing.T) {
+	var p PDVWrapper
+	require.NoError(t, json.Unmarshal([]byte(`
+{
+	"version": "v1",
+	"device": "ios",
+	"pdv": [
+		{
+			"type": "advertiserId",
+			"advertiser": "decentr",
+			"name": "12345qwert",
+			"value": "12345value"
+		}
+	]
+}`), &p))
+
+	v2, err := p.ToV2()
+	require.NoError(t, err)
+	require.Equal(t, V2, v2.Version())
+	require.Equal(t, "ios", v2.Device)
+	require.Equal(t, p.Data(), v2.Data())
+
+	same, err := v2.ToV2()
+	require.NoError(t, err)
+	require.Equal(t, v2, same)
+
+	_, err = PDVWrapper{}.ToV2()
+	require.Error(t, err)
+}
+
+func Test_GetInvalidPDV_V2(t *testing.T) {
+	s, err := GetInvalidPDV([]byte(`
+{
+	"version": "v2",
+	"pdv": [
+		{
+			"type": "installedApp",
+			"kind": "extension",
+			"id": "decentr",
+			"name": "Decentr"
+		},
+		{
+			"type": "installedApp",
+			"kind": "extension",
+			"name": "Decentr"
+		}
+	]
+}`))
+	require.NoError(t, err)
+	require.Equal(t, []int{1}, s)
+}
+
+func TestPDVWrapper_ValidateWithErrors(t *testing.T) {
+	var p PDVWrapper
+	require.NoError(t, json.Unmarshal([]byte(`
+{
+	"version": "v1",
+	"device": "tv",
+	"pdv": [
+		{
+			"type": "advertiserId",
+			"advertiser": "decentr",
+			"name": "12345qwert",
+			"value": "12345value"
+		},
+		{
+			"type": "profile",
+			"emails": ["dev@decentr.xyz", "dev"]
+		}
+	]
+}`), &p))
+
+	require.False(t, p.Validate())
+	require.Equal(t, []FieldError{
+		{Path: "device", Code: "invalid", Message: "must be ios, android or desktop"},
+		{Path: "pdv[1].emails[1]", Code: "invalid", Message: "must be a valid email"},
+	}, p.ValidateWithErrors())
+
+	require.Equal(t, []FieldError{{Path: "pdv", Code: "required", Message: "is required"}}, PDVWrapper{}.ValidateWithErrors())
+}
+
+func Test_GetPDVErrors(t *testing.T) {
+	errs, err := GetPDVErrors([]byte(`
+{
+	"version": "v1",
+	"pdv": [
+		{
+			"timestamp": "2021-05-11T11:05:18Z",
+			"type": "searchHistory",
+			"engine": "decentr",
+			"domain": "decentr.xyz"
+		}
+	]
+}`))
+	require.NoError(t, err)
+	require.Equal(t, []FieldError{{Path: "pdv[0].query", Code: "required", Message: "is required"}}, errs)
+
+	_, err = GetPDVErrors([]byte(`{"version": "v0", "pdv": []}`))
+	require.Error(t, err)
+}
@@ -28,6 +28,12 @@ const (
 	PDVProfileType       Type = "profile"
 	PDVSearchHistoryType Type = "searchHistory"
 	PDVLocationType      Type = "location"
+
+	PDVBrowsingHistoryType Type = "browsingHistory"
+	PDVBookmarkType        Type = "bookmark"
+	PDVInstalledAppType    Type = "installedApp"
+	PDVDeviceInfoType      Type = "deviceInfo"
+	PDVInterestType        Type = "interest"
 )
 
 const (
@@ -35,6 +41,15 @@ const (
 	DataSizeLimit = 8 * 1024
 )
 
+// nolint
+const (
+	ErrorCodeRequired   = "required"
+	ErrorCodeTooLong    = "too_long"
+	ErrorCodeOutOfRange = "out_of_range"
+	ErrorCodeInvalid    = "invalid"
+	ErrorCodeMalformed  = "malformed"
+)
+
 // Date in ISO-8601 format (yyyy-mm-dd).
 type Date struct {
 	time.Time
@@ -68,14 +83,31 @@ type Source struct {
 	Path string `json:"path"`
 }
 
+// FieldError describes an invalid field.
+// swagger:model FieldError
+type FieldError struct {
+	// Path is JSON path of the field (e.g. pdv[1].source).
+	Path string `json:"path"`
+	// Code is one of required, too_long, out_of_range, invalid, malformed.
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
 // Validate ...
 type Validate interface {
 	Validate() bool
 }
 
+// FieldValidator ...
+type FieldValidator interface {
+	// ValidateWithErrors returns errors of invalid fields, the object is valid if there are no errors.
+	ValidateWithErrors() []FieldError
+}
+
 // PDV is interface for all versions objects.
 type PDV interface {
 	Validate
+	FieldValidator
 
 	Version() Version
 	Data() []Data
@@ -84,6 +116,7 @@ type PDV interface {
 // Data is interface for all PDV data types.
 type Data interface {
 	Validate
+	FieldValidator
 
 	Type() Type
 }
@@ -92,7 +125,8 @@ type Data interface {
 func (t *Type) UnmarshalText(b []byte) error {
 	s := Type(b)
 	switch s {
-	case PDVAdvertiserIDType, PDVCookieType, PDVLocationType, PDVSearchHistoryType, PDVProfileType:
+	case PDVAdvertiserIDType, PDVCookieType, PDVLocationType, PDVSearchHistoryType, PDVProfileType,
+		PDVBrowsingHistoryType, PDVBookmarkType, PDVInstalledAppType, PDVDeviceInfoType, PDVInterestType:
 	default:
 		return errors.New("unknown PDVType")
 	}
@@ -173,7 +207,15 @@ func (m TypeMapper) UnmarshalPDVData(b []byte) (Data, error) {
 
 // Validate ...
 func (s Source) Validate() bool {
-	return valid.IsURL(fmt.Sprintf("%s/%s", s.Host, s.Path))
+	return len(s.ValidateWithErrors()) == 0
+}
+
+// ValidateWithErrors ...
+func (s Source) ValidateWithErrors() []FieldError {
+	if !valid.IsURL(fmt.Sprintf("%s/%s", s.Host, s.Path)) {
+		return []FieldError{InvalidError("", "host and path must be a valid url")}
+	}
+	return nil
 }
 
 // IsValidGender checks if s is a valid gender.
@@ -198,7 +240,78 @@ func IsValidAvatar(str string) bool {
 	return url.Scheme == "http" || url.Scheme == "https"
 }
 
+// IsValidURL checks if str is a http(s) url not longer than maxLength.
+func IsValidURL(str string, maxLength int) bool {
+	if str == "" || len(str) > maxLength {
+		return false
+	}
+
+	u, err := url.Parse(str)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
 // Validate ...
 func (t Timestamp) Validate() bool {
-	return !t.Time.IsZero()
+	return len(t.ValidateWithErrors()) == 0
+}
+
+// ValidateWithErrors ...
+func (t Timestamp) ValidateWithErrors() []FieldError {
+	if t.Time.IsZero() {
+		return []FieldError{RequiredError("timestamp")}
+	}
+	return nil
+}
+
+// Error returns the error as a string.
+func (e FieldError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// RequiredError returns an error of the missing field.
+func RequiredError(path string) FieldError {
+	return FieldError{Path: path, Code: ErrorCodeRequired, Message: "is required"}
+}
+
+// TooLongError returns an error of the field which is longer than max.
+func TooLongError(path string, max int) FieldError {
+	return FieldError{Path: path, Code: ErrorCodeTooLong, Message: fmt.Sprintf("must not be longer than %d", max)}
+}
+
+// OutOfRangeError returns an error of the field which is out of [min, max].
+func OutOfRangeError(path string, min, max interface{}) FieldError {
+	return FieldError{Path: path, Code: ErrorCodeOutOfRange, Message: fmt.Sprintf("must be between %v and %v", min, max)}
+}
+
+// InvalidError returns an error of the field with invalid value.
+func InvalidError(path, message string) FieldError {
+	return FieldError{Path: path, Code: ErrorCodeInvalid, Message: message}
+}
+
+// NestFieldErrors prepends prefix to paths of errs, e.g. "source" and "host" become "source.host", "pdv" and "[1]" become "pdv[1]".
+func NestFieldErrors(prefix string, errs []FieldError) []FieldError {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	out := make([]FieldError, len(errs))
+	for i, v := range errs {
+		switch {
+		case v.Path == "":
+			v.Path = prefix
+		case prefix == "", strings.HasPrefix(v.Path, "["):
+			v.Path = prefix + v.Path
+		default:
+			v.Path = prefix + "." + v.Path
+		}
+		out[i] = v
+	}
+
+	return out
 }
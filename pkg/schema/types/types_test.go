@@ -3,6 +3,7 @@ package types
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -10,6 +11,7 @@ import (
 func TestPDVType_UnmarshalText(t *testing.T) {
 	var p Type
 	require.NoError(t, p.UnmarshalText([]byte(PDVCookieType)))
+	require.NoError(t, p.UnmarshalText([]byte(PDVBrowsingHistoryType)))
 	require.Error(t, p.UnmarshalText([]byte("wrong")))
 }
 
@@ -38,6 +40,10 @@ func (testPDVType) Validate() bool {
 	return true
 }
 
+func (testPDVType) ValidateWithErrors() []FieldError {
+	return nil
+}
+
 func TestDate_UnmarshalText(t *testing.T) {
 	s := struct {
 		D Date
@@ -51,3 +57,42 @@ func TestDate_UnmarshalText(t *testing.T) {
 	require.NoError(t, err)
 	require.JSONEq(t, j, string(b))
 }
+
+func TestIsValidURL(t *testing.T) {
+	require.True(t, IsValidURL("https://decentr.xyz/path?q=1", 100))
+	require.True(t, IsValidURL("http://decentr.xyz", 100))
+	require.False(t, IsValidURL("", 100))
+	require.False(t, IsValidURL("ftp://decentr.xyz", 100))
+	require.False(t, IsValidURL("https://", 100))
+	require.False(t, IsValidURL("decentr.xyz", 100))
+	require.False(t, IsValidURL("https://decentr.xyz/long", 10))
+}
+
+func TestSource_ValidateWithErrors(t *testing.T) {
+	require.Empty(t, Source{Host: "https://decentr.xyz", Path: "/"}.ValidateWithErrors())
+	require.Equal(t, []FieldError{{Code: ErrorCodeInvalid, Message: "host and path must be a valid url"}}, Source{}.ValidateWithErrors())
+}
+
+func TestTimestamp_ValidateWithErrors(t *testing.T) {
+	require.Empty(t, Timestamp{Time: time.Now()}.ValidateWithErrors())
+	require.Equal(t, []FieldError{RequiredError("timestamp")}, Timestamp{}.ValidateWithErrors())
+}
+
+func TestNestFieldErrors(t *testing.T) {
+	require.Nil(t, NestFieldErrors("pdv", nil))
+	require.Equal(t, []FieldError{
+		{Path: "source", Code: ErrorCodeInvalid},
+		{Path: "source.host", Code: ErrorCodeRequired},
+		{Path: "source[1]", Code: ErrorCodeRequired},
+	}, NestFieldErrors("source", []FieldError{
+		{Code: ErrorCodeInvalid},
+		{Path: "host", Code: ErrorCodeRequired},
+		{Path: "[1]", Code: ErrorCodeRequired},
+	}))
+	require.Equal(t, []FieldError{{Path: "name"}}, NestFieldErrors("", []FieldError{{Path: "name"}}))
+}
+
+func TestFieldError_Error(t *testing.T) {
+	require.Equal(t, "pdv[0].name: is required", RequiredError("pdv[0].name").Error())
+	require.Equal(t, "must not be longer than 10", TooLongError("", 10).Error())
+}
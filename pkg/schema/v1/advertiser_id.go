@@ -22,17 +22,30 @@ func (AdvertiserID) Type() types.Type {
 
 // Validate ...
 func (d AdvertiserID) Validate() bool {
-	if d.Advertiser == "" || d.Name == "" || d.Value == "" {
-		return false
-	}
+	return len(d.ValidateWithErrors()) == 0
+}
 
-	if len(d.Advertiser) > maxAdvertiserLength ||
-		len(d.Name) > maxAdvertiserNameLength ||
-		len(d.Value) > maxAdvertiserValueLength {
-		return false
-	}
+// ValidateWithErrors ...
+func (d AdvertiserID) ValidateWithErrors() []types.FieldError {
+	var errs []types.FieldError
+
+	errs = append(errs, validateString("advertiser", d.Advertiser, maxAdvertiserLength)...)
+	errs = append(errs, validateString("name", d.Name, maxAdvertiserNameLength)...)
+	errs = append(errs, validateString("value", d.Value, maxAdvertiserValueLength)...)
 
-	return true
+	return errs
+}
+
+// validateString checks that required string field isn't empty and isn't longer than max bytes.
+func validateString(path, s string, max int) []types.FieldError {
+	switch {
+	case s == "":
+		return []types.FieldError{types.RequiredError(path)}
+	case len(s) > max:
+		return []types.FieldError{types.TooLongError(path, max)}
+	default:
+		return nil
+	}
 }
 
 // MarshalJSON ...
@@ -1,9 +1,12 @@
 package schema
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/Decentr-net/cerberus/pkg/schema/types"
 )
 
 func TestAdvertiserID_Validate(t *testing.T) {
@@ -59,3 +62,13 @@ func TestAdvertiserID_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestAdvertiserID_ValidateWithErrors(t *testing.T) {
+	assert.Equal(t, []types.FieldError{
+		types.TooLongError("advertiser", maxAdvertiserLength),
+		types.RequiredError("value"),
+	}, AdvertiserID{
+		Advertiser: strings.Repeat("a", maxAdvertiserLength+1),
+		Name:       "name",
+	}.ValidateWithErrors())
+}
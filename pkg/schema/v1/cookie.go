@@ -27,11 +27,24 @@ func (Cookie) Type() types.Type {
 
 // Validate ...
 func (d Cookie) Validate() bool { // nolint: gocritic
-	if d.Name == "" || d.Value == "" {
-		return false
+	return len(d.ValidateWithErrors()) == 0
+}
+
+// ValidateWithErrors ...
+func (d Cookie) ValidateWithErrors() []types.FieldError { // nolint: gocritic
+	var errs []types.FieldError
+
+	if d.Name == "" {
+		errs = append(errs, types.RequiredError("name"))
 	}
 
-	return d.Source.Validate() && d.Timestamp.Validate()
+	if d.Value == "" {
+		errs = append(errs, types.RequiredError("value"))
+	}
+
+	errs = append(errs, types.NestFieldErrors("source", d.Source.ValidateWithErrors())...)
+
+	return append(errs, d.Timestamp.ValidateWithErrors()...)
 }
 
 // MarshalJSON ...
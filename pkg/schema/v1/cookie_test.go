@@ -101,3 +101,19 @@ func TestCookie_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestCookie_ValidateWithErrors(t *testing.T) {
+	assert.Empty(t, Cookie{
+		Timestamp: types.Timestamp{Time: time.Now()},
+		Source:    types.Source{Host: "https://decentr.xyz"},
+		Name:      "cookie",
+		Value:     "value",
+	}.ValidateWithErrors())
+
+	assert.Equal(t, []types.FieldError{
+		types.RequiredError("name"),
+		types.RequiredError("value"),
+		types.InvalidError("source", "host and path must be a valid url"),
+		types.RequiredError("timestamp"),
+	}, Cookie{}.ValidateWithErrors())
+}
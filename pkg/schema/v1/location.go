@@ -21,19 +21,26 @@ func (Location) Type() types.Type {
 
 // Validate ...
 func (d Location) Validate() bool {
+	return len(d.ValidateWithErrors()) == 0
+}
+
+// ValidateWithErrors ...
+func (d Location) ValidateWithErrors() []types.FieldError {
+	var errs []types.FieldError
+
 	if d.Latitude < -90 || d.Latitude > 90 {
-		return false
+		errs = append(errs, types.OutOfRangeError("latitude", -90, 90))
 	}
 
 	if d.Longitude < -180 || d.Longitude > 180 {
-		return false
+		errs = append(errs, types.OutOfRangeError("longitude", -180, 180))
 	}
 
-	if d.RequestedBy != nil && !d.RequestedBy.Validate() {
-		return false
+	if d.RequestedBy != nil {
+		errs = append(errs, types.NestFieldErrors("requestedBy", d.RequestedBy.ValidateWithErrors())...)
 	}
 
-	return d.Timestamp.Validate()
+	return append(errs, d.Timestamp.ValidateWithErrors()...)
 }
 
 // MarshalJSON ...
@@ -81,3 +81,16 @@ func TestLocation_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestLocation_ValidateWithErrors(t *testing.T) {
+	assert.Equal(t, []types.FieldError{
+		types.OutOfRangeError("latitude", -90, 90),
+		types.OutOfRangeError("longitude", -180, 180),
+		types.InvalidError("requestedBy", "host and path must be a valid url"),
+	}, Location{
+		Timestamp:   types.Timestamp{Time: time.Now()},
+		Latitude:    -91,
+		Longitude:   181,
+		RequestedBy: &types.Source{},
+	}.ValidateWithErrors())
+}
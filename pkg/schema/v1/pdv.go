@@ -3,6 +3,7 @@ package schema
 
 import (
 	"encoding/json"
+	"fmt"
 	"reflect"
 
 	"github.com/Decentr-net/cerberus/pkg/schema/types"
@@ -71,12 +72,45 @@ func (o PDV) MarshalJSON() ([]byte, error) {
 
 // Validate ...
 func (o PDV) Validate() bool {
-	for _, v := range o {
-		if !v.Validate() {
-			return false
+	return len(o.ValidateWithErrors()) == 0
+}
+
+// ValidateWithErrors ...
+func (o PDV) ValidateWithErrors() []types.FieldError {
+	if len(o) == 0 {
+		return []types.FieldError{types.RequiredError("")}
+	}
+
+	var errs []types.FieldError
+	for i, v := range o {
+		errs = append(errs, types.NestFieldErrors(fmt.Sprintf("[%d]", i), v.ValidateWithErrors())...)
+	}
+	return errs
+}
+
+// GetPDVErrors returns errors of invalid PDV, paths start with the index of PDV (e.g. [1].name).
+func GetPDVErrors(b []byte) ([]types.FieldError, error) {
+	var data []json.RawMessage
+
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+
+	var out []types.FieldError
+
+	for i, v := range data {
+		path := fmt.Sprintf("[%d]", i)
+
+		pdv, err := dataSchemes.UnmarshalPDVData(v)
+		if err != nil {
+			out = append(out, types.FieldError{Path: path, Code: types.ErrorCodeMalformed, Message: err.Error()})
+			continue
 		}
+
+		out = append(out, types.NestFieldErrors(path, pdv.ValidateWithErrors())...)
 	}
-	return len(o) > 0
+
+	return out, nil
 }
 
 // GetInvalidPDV returns indicies of invalid PDV.
@@ -48,3 +48,50 @@ func mustDate(s string) *types.Date {
 
 	return &d
 }
+
+func TestPDV_ValidateWithErrors(t *testing.T) {
+	require.Equal(t, []types.FieldError{types.RequiredError("")}, PDV{}.ValidateWithErrors())
+
+	require.Equal(t, []types.FieldError{
+		types.RequiredError("[1].value"),
+	}, PDV{
+		Cookie{
+			Timestamp: types.Timestamp{Time: time.Now()},
+			Source:    types.Source{Host: "https://decentr.xyz"},
+			Name:      "cookie",
+			Value:     "value",
+		},
+		Cookie{
+			Timestamp: types.Timestamp{Time: time.Now()},
+			Source:    types.Source{Host: "https://decentr.xyz"},
+			Name:      "cookie",
+		},
+	}.ValidateWithErrors())
+}
+
+func TestGetPDVErrors(t *testing.T) {
+	errs, err := GetPDVErrors([]byte(`[
+		{
+			"type": "advertiserId",
+			"advertiser": "decentr",
+			"name": "12345qwert",
+			"value": "12345value"
+		},
+		{
+			"type": "advertiserId",
+			"advertiser": "decentr",
+			"name": "12345qwert"
+		},
+		{
+			"type": "unknown"
+		}
+	]`))
+	require.NoError(t, err)
+	require.Equal(t, []types.FieldError{
+		types.RequiredError("[1].value"),
+		{Path: "[2]", Code: types.ErrorCodeMalformed, Message: "failed to unmarshal PDV Data meta: unknown PDVType"},
+	}, errs)
+
+	_, err = GetPDVErrors([]byte(`{}`))
+	require.Error(t, err)
+}
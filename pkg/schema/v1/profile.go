@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"fmt"
 	"time"
 	"unicode/utf8"
 
@@ -12,6 +13,8 @@ import (
 const (
 	maxFirstNameLength = 64
 	maxLastNameLength  = 64
+
+	minBirthdayYear = 1900
 )
 
 // Profile is PDVData implementation for profile's data.
@@ -37,24 +40,48 @@ func (d Profile) MarshalJSON() ([]byte, error) { // nolint: gocritic
 
 // Validate ...
 func (d Profile) Validate() bool { // nolint: gocritic
+	return len(d.ValidateWithErrors()) == 0
+}
+
+// ValidateWithErrors ...
+func (d Profile) ValidateWithErrors() []types.FieldError { // nolint: gocritic
+	var errs []types.FieldError
+
+	if utf8.RuneCountInString(d.FirstName) > maxFirstNameLength {
+		errs = append(errs, types.TooLongError("firstName", maxFirstNameLength))
+	}
+
+	if utf8.RuneCountInString(d.LastName) > maxLastNameLength {
+		errs = append(errs, types.TooLongError("lastName", maxLastNameLength))
+	}
+
 	if len(d.Emails) == 0 {
-		return false
+		errs = append(errs, types.RequiredError("emails"))
 	}
 
-	for _, v := range d.Emails {
+	for i, v := range d.Emails {
 		if !valid.IsEmail(v) {
-			return false
+			errs = append(errs, types.InvalidError(fmt.Sprintf("emails[%d]", i), "must be a valid email"))
 		}
 	}
 
-	isBirthdayValid := true
+	if !types.IsValidGender(d.Gender) {
+		errs = append(errs, types.InvalidError("gender", "must be male or female"))
+	}
+
+	if !types.IsValidAvatar(d.Avatar) {
+		errs = append(errs, types.InvalidError("avatar", "must be a http(s) url"))
+	}
+
 	if d.Birthday != nil {
-		isBirthdayValid = d.Birthday.Year() > 1900 && d.Birthday.Year() < time.Now().Year()
+		if y := d.Birthday.Year(); y <= minBirthdayYear || y >= time.Now().Year() {
+			errs = append(errs, types.FieldError{
+				Path:    "birthday",
+				Code:    types.ErrorCodeOutOfRange,
+				Message: fmt.Sprintf("year must be between %d and %d", minBirthdayYear+1, time.Now().Year()-1),
+			})
+		}
 	}
 
-	return types.IsValidGender(d.Gender) &&
-		types.IsValidAvatar(d.Avatar) &&
-		utf8.RuneCountInString(d.FirstName) <= maxFirstNameLength &&
-		utf8.RuneCountInString(d.LastName) <= maxLastNameLength &&
-		isBirthdayValid
+	return errs
 }
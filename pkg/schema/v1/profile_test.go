@@ -1,9 +1,14 @@
 package schema
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/Decentr-net/cerberus/pkg/schema/types"
 )
 
 func TestProfile_Validate(t *testing.T) {
@@ -153,3 +158,27 @@ func TestProfile_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestProfile_ValidateWithErrors(t *testing.T) {
+	require.Empty(t, Profile{Emails: []string{"test@decentr.xyz"}}.ValidateWithErrors())
+
+	require.Equal(t, []types.FieldError{
+		types.TooLongError("firstName", maxFirstNameLength),
+		types.InvalidError("emails[1]", "must be a valid email"),
+		types.InvalidError("gender", "must be male or female"),
+		types.InvalidError("avatar", "must be a http(s) url"),
+		{
+			Path:    "birthday",
+			Code:    types.ErrorCodeOutOfRange,
+			Message: fmt.Sprintf("year must be between 1901 and %d", time.Now().Year()-1),
+		},
+	}, Profile{
+		FirstName: strings.Repeat("a", maxFirstNameLength+1),
+		Emails:    []string{"test@decentr.xyz", "test"},
+		Gender:    "unknown",
+		Avatar:    "ftp://decentr.xyz/avatar.png",
+		Birthday:  mustDate("1900-01-01"),
+	}.ValidateWithErrors())
+
+	require.Equal(t, []types.FieldError{types.RequiredError("emails")}, Profile{}.ValidateWithErrors())
+}
@@ -26,17 +26,18 @@ func (SearchHistory) Type() types.Type {
 
 // Validate ...
 func (d SearchHistory) Validate() bool {
-	if d.Engine == "" || d.Query == "" || d.Domain == "" {
-		return false
-	}
+	return len(d.ValidateWithErrors()) == 0
+}
+
+// ValidateWithErrors ...
+func (d SearchHistory) ValidateWithErrors() []types.FieldError {
+	var errs []types.FieldError
 
-	if len(d.Engine) > maxSearchEngineLength ||
-		len(d.Query) > maxSearchQueryLength ||
-		len(d.Domain) > maxDomainLength {
-		return false
-	}
+	errs = append(errs, validateString("domain", d.Domain, maxDomainLength)...)
+	errs = append(errs, validateString("engine", d.Engine, maxSearchEngineLength)...)
+	errs = append(errs, validateString("query", d.Query, maxSearchQueryLength)...)
 
-	return d.Timestamp.Validate()
+	return append(errs, d.Timestamp.ValidateWithErrors()...)
 }
 
 // MarshalJSON ...
@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -75,3 +76,14 @@ func TestSearchHistory_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchHistory_ValidateWithErrors(t *testing.T) {
+	assert.Equal(t, []types.FieldError{
+		types.RequiredError("domain"),
+		types.TooLongError("engine", maxSearchEngineLength),
+		types.RequiredError("timestamp"),
+	}, SearchHistory{
+		Engine: strings.Repeat("a", maxSearchEngineLength+1),
+		Query:  "the best crypto",
+	}.ValidateWithErrors())
+}
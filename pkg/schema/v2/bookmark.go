@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"unicode/utf8"
+
+	"github.com/Decentr-net/cerberus/pkg/schema/types"
+)
+
+const maxFolderLength = 255
+
+// Bookmark is user's bookmark, timestamp is the time when the bookmark was added.
+type Bookmark struct {
+	types.Timestamp
+
+	URL    string `json:"url"`
+	Title  string `json:"title"`
+	Folder string `json:"folder"`
+}
+
+// Type ...
+func (Bookmark) Type() types.Type {
+	return types.PDVBookmarkType
+}
+
+// Validate ...
+func (d Bookmark) Validate() bool {
+	return len(d.ValidateWithErrors()) == 0
+}
+
+// ValidateWithErrors ...
+func (d Bookmark) ValidateWithErrors() []types.FieldError {
+	errs := validateURL("url", d.URL)
+
+	if utf8.RuneCountInString(d.Title) > maxTitleLength {
+		errs = append(errs, types.TooLongError("title", maxTitleLength))
+	}
+
+	if utf8.RuneCountInString(d.Folder) > maxFolderLength {
+		errs = append(errs, types.TooLongError("folder", maxFolderLength))
+	}
+
+	return append(errs, d.Timestamp.ValidateWithErrors()...)
+}
+
+// MarshalJSON ...
+func (d Bookmark) MarshalJSON() ([]byte, error) {
+	return types.MarshalPDVData(d)
+}
@@ -0,0 +1,63 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Decentr-net/cerberus/pkg/schema/types"
+)
+
+func TestBookmark_Validate(t *testing.T) {
+	tt := []struct {
+		name  string
+		d     Bookmark
+		valid bool
+	}{
+		{
+			name: "valid",
+			d: Bookmark{
+				Timestamp: types.Timestamp{Time: time.Now()},
+				URL:       "https://decentr.xyz/",
+				Title:     "Decentr",
+				Folder:    "crypto",
+			},
+			valid: true,
+		},
+		{
+			name: "invalid url",
+			d: Bookmark{
+				Timestamp: types.Timestamp{Time: time.Now()},
+				URL:       "javascript:alert(1)",
+			},
+			valid: false,
+		},
+		{
+			name: "too long folder",
+			d: Bookmark{
+				Timestamp: types.Timestamp{Time: time.Now()},
+				URL:       "https://decentr.xyz/",
+				Folder:    strings.Repeat("a", maxFolderLength+1),
+			},
+			valid: false,
+		},
+		{
+			name: "invalid time",
+			d: Bookmark{
+				URL: "https://decentr.xyz/",
+			},
+			valid: false,
+		},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.valid, tc.d.Validate())
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"unicode/utf8"
+
+	"github.com/Decentr-net/cerberus/pkg/schema/types"
+)
+
+const (
+	maxURLLength   = 2048
+	maxTitleLength = 1000
+)
+
+// BrowsingHistory is a visited page.
+type BrowsingHistory struct {
+	types.Timestamp
+
+	URL        string `json:"url"`
+	Title      string `json:"title"`
+	VisitCount uint32 `json:"visitCount"`
+	// Duration is time spent on the page in seconds.
+	Duration uint64 `json:"duration"`
+}
+
+// Type ...
+func (BrowsingHistory) Type() types.Type {
+	return types.PDVBrowsingHistoryType
+}
+
+// Validate ...
+func (d BrowsingHistory) Validate() bool {
+	return len(d.ValidateWithErrors()) == 0
+}
+
+// ValidateWithErrors ...
+func (d BrowsingHistory) ValidateWithErrors() []types.FieldError {
+	errs := validateURL("url", d.URL)
+
+	if utf8.RuneCountInString(d.Title) > maxTitleLength {
+		errs = append(errs, types.TooLongError("title", maxTitleLength))
+	}
+
+	if d.VisitCount == 0 {
+		errs = append(errs, types.RequiredError("visitCount"))
+	}
+
+	return append(errs, d.Timestamp.ValidateWithErrors()...)
+}
+
+// validateURL checks that required url field is a valid http(s) url.
+func validateURL(path, s string) []types.FieldError {
+	switch {
+	case s == "":
+		return []types.FieldError{types.RequiredError(path)}
+	case len(s) > maxURLLength:
+		return []types.FieldError{types.TooLongError(path, maxURLLength)}
+	case !types.IsValidURL(s, maxURLLength):
+		return []types.FieldError{types.InvalidError(path, "must be a http(s) url")}
+	default:
+		return nil
+	}
+}
+
+// MarshalJSON ...
+func (d BrowsingHistory) MarshalJSON() ([]byte, error) {
+	return types.MarshalPDVData(d)
+}
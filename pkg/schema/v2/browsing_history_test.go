@@ -0,0 +1,102 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Decentr-net/cerberus/pkg/schema/types"
+)
+
+func TestBrowsingHistory_Validate(t *testing.T) {
+	tt := []struct {
+		name  string
+		d     BrowsingHistory
+		valid bool
+	}{
+		{
+			name: "valid",
+			d: BrowsingHistory{
+				Timestamp:  types.Timestamp{Time: time.Now()},
+				URL:        "https://decentr.xyz/",
+				Title:      "Decentr",
+				VisitCount: 1,
+				Duration:   60,
+			},
+			valid: true,
+		},
+		{
+			name: "empty title",
+			d: BrowsingHistory{
+				Timestamp:  types.Timestamp{Time: time.Now()},
+				URL:        "https://decentr.xyz/",
+				VisitCount: 1,
+			},
+			valid: true,
+		},
+		{
+			name: "invalid url",
+			d: BrowsingHistory{
+				Timestamp:  types.Timestamp{Time: time.Now()},
+				URL:        "chrome://settings",
+				VisitCount: 1,
+			},
+			valid: false,
+		},
+		{
+			name: "too long title",
+			d: BrowsingHistory{
+				Timestamp:  types.Timestamp{Time: time.Now()},
+				URL:        "https://decentr.xyz/",
+				Title:      strings.Repeat("a", maxTitleLength+1),
+				VisitCount: 1,
+			},
+			valid: false,
+		},
+		{
+			name: "zero visit count",
+			d: BrowsingHistory{
+				Timestamp: types.Timestamp{Time: time.Now()},
+				URL:       "https://decentr.xyz/",
+			},
+			valid: false,
+		},
+		{
+			name: "invalid time",
+			d: BrowsingHistory{
+				URL:        "https://decentr.xyz/",
+				VisitCount: 1,
+			},
+			valid: false,
+		},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.valid, tc.d.Validate())
+		})
+	}
+}
+
+func TestBrowsingHistory_ValidateWithErrors(t *testing.T) {
+	assert.Equal(t, []types.FieldError{
+		types.InvalidError("url", "must be a http(s) url"),
+		types.RequiredError("visitCount"),
+	}, BrowsingHistory{
+		Timestamp: types.Timestamp{Time: time.Now()},
+		URL:       "chrome://settings",
+	}.ValidateWithErrors())
+
+	assert.Equal(t, []types.FieldError{
+		types.TooLongError("url", maxURLLength),
+	}, BrowsingHistory{
+		Timestamp:  types.Timestamp{Time: time.Now()},
+		URL:        "https://decentr.xyz/" + strings.Repeat("a", maxURLLength),
+		VisitCount: 1,
+	}.ValidateWithErrors())
+}
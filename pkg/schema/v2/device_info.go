@@ -0,0 +1,113 @@
+package schema
+
+import (
+	"regexp"
+	"strings"
+
+	valid "github.com/asaskevich/govalidator"
+
+	"github.com/Decentr-net/cerberus/pkg/schema/types"
+)
+
+const (
+	maxOSLength        = 50
+	maxOSVersionLength = 50
+	maxLanguageLength  = 35
+	maxTimezoneLength  = 64
+	maxScreenSize      = 16384
+)
+
+// timezoneRegexp matches IANA time zone names (e.g. UTC, Europe/Berlin, America/Argentina/Buenos_Aires).
+var timezoneRegexp = regexp.MustCompile(`^[A-Za-z]+(/[A-Za-z0-9_+\-]+)*$`) // nolint:gochecknoglobals
+
+// Screen is a screen resolution in pixels.
+type Screen struct {
+	Width  uint32 `json:"width"`
+	Height uint32 `json:"height"`
+}
+
+// DeviceInfo is information about user's device.
+type DeviceInfo struct {
+	types.Timestamp
+
+	OS        string `json:"os"`
+	OSVersion string `json:"osVersion"`
+	// Language is BCP 47 language tag (e.g. en-US).
+	Language string `json:"language"`
+	Screen   Screen `json:"screen"`
+	// Timezone is IANA time zone name.
+	Timezone string `json:"timezone"`
+}
+
+// Type ...
+func (DeviceInfo) Type() types.Type {
+	return types.PDVDeviceInfoType
+}
+
+// Validate ...
+func (d DeviceInfo) Validate() bool {
+	return len(d.ValidateWithErrors()) == 0
+}
+
+// ValidateWithErrors ...
+func (d DeviceInfo) ValidateWithErrors() []types.FieldError {
+	var errs []types.FieldError
+
+	switch {
+	case d.OS == "":
+		errs = append(errs, types.RequiredError("os"))
+	case len(d.OS) > maxOSLength:
+		errs = append(errs, types.TooLongError("os", maxOSLength))
+	}
+
+	if len(d.OSVersion) > maxOSVersionLength {
+		errs = append(errs, types.TooLongError("osVersion", maxOSVersionLength))
+	}
+
+	switch {
+	case d.Language == "":
+		errs = append(errs, types.RequiredError("language"))
+	case len(d.Language) > maxLanguageLength:
+		errs = append(errs, types.TooLongError("language", maxLanguageLength))
+	case !valid.IsISO693Alpha2(strings.SplitN(d.Language, "-", 2)[0]):
+		errs = append(errs, types.InvalidError("language", "must be BCP 47 language tag"))
+	}
+
+	switch {
+	case d.Timezone == "":
+		errs = append(errs, types.RequiredError("timezone"))
+	case len(d.Timezone) > maxTimezoneLength:
+		errs = append(errs, types.TooLongError("timezone", maxTimezoneLength))
+	case !timezoneRegexp.MatchString(d.Timezone):
+		errs = append(errs, types.InvalidError("timezone", "must be IANA time zone name"))
+	}
+
+	errs = append(errs, types.NestFieldErrors("screen", d.Screen.ValidateWithErrors())...)
+
+	return append(errs, d.Timestamp.ValidateWithErrors()...)
+}
+
+// MarshalJSON ...
+func (d DeviceInfo) MarshalJSON() ([]byte, error) {
+	return types.MarshalPDVData(d)
+}
+
+// Validate ...
+func (s Screen) Validate() bool {
+	return len(s.ValidateWithErrors()) == 0
+}
+
+// ValidateWithErrors ...
+func (s Screen) ValidateWithErrors() []types.FieldError {
+	var errs []types.FieldError
+
+	if s.Width == 0 || s.Width > maxScreenSize {
+		errs = append(errs, types.OutOfRangeError("width", 1, maxScreenSize))
+	}
+
+	if s.Height == 0 || s.Height > maxScreenSize {
+		errs = append(errs, types.OutOfRangeError("height", 1, maxScreenSize))
+	}
+
+	return errs
+}
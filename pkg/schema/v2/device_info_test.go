@@ -0,0 +1,105 @@
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Decentr-net/cerberus/pkg/schema/types"
+)
+
+func TestDeviceInfo_Validate(t *testing.T) {
+	valid := func() DeviceInfo {
+		return DeviceInfo{
+			Timestamp: types.Timestamp{Time: time.Now()},
+			OS:        "macOS",
+			OSVersion: "12.4",
+			Language:  "en-US",
+			Screen:    Screen{Width: 1920, Height: 1080},
+			Timezone:  "America/Argentina/Buenos_Aires",
+		}
+	}
+
+	tt := []struct {
+		name   string
+		modify func(d *DeviceInfo)
+		valid  bool
+	}{
+		{
+			name:   "valid",
+			modify: func(d *DeviceInfo) {},
+			valid:  true,
+		},
+		{
+			name:   "valid language without region",
+			modify: func(d *DeviceInfo) { d.Language = "de" },
+			valid:  true,
+		},
+		{
+			name:   "valid utc",
+			modify: func(d *DeviceInfo) { d.Timezone = "UTC" },
+			valid:  true,
+		},
+		{
+			name:   "empty os",
+			modify: func(d *DeviceInfo) { d.OS = "" },
+			valid:  false,
+		},
+		{
+			name:   "invalid language",
+			modify: func(d *DeviceInfo) { d.Language = "english" },
+			valid:  false,
+		},
+		{
+			name:   "empty language",
+			modify: func(d *DeviceInfo) { d.Language = "" },
+			valid:  false,
+		},
+		{
+			name:   "invalid timezone",
+			modify: func(d *DeviceInfo) { d.Timezone = "+03:00" },
+			valid:  false,
+		},
+		{
+			name:   "empty screen",
+			modify: func(d *DeviceInfo) { d.Screen = Screen{} },
+			valid:  false,
+		},
+		{
+			name:   "too big screen",
+			modify: func(d *DeviceInfo) { d.Screen.Width = maxScreenSize + 1 },
+			valid:  false,
+		},
+		{
+			name:   "invalid time",
+			modify: func(d *DeviceInfo) { d.Timestamp = types.Timestamp{} },
+			valid:  false,
+		},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := valid()
+			tc.modify(&d)
+
+			assert.Equal(t, tc.valid, d.Validate())
+		})
+	}
+}
+
+func TestDeviceInfo_ValidateWithErrors(t *testing.T) {
+	assert.Equal(t, []types.FieldError{
+		types.RequiredError("os"),
+		types.InvalidError("language", "must be BCP 47 language tag"),
+		types.RequiredError("timezone"),
+		types.OutOfRangeError("screen.height", 1, maxScreenSize),
+		types.RequiredError("timestamp"),
+	}, DeviceInfo{
+		Language: "english",
+		Screen:   Screen{Width: 1920},
+	}.ValidateWithErrors())
+}
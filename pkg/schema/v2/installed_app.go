@@ -0,0 +1,75 @@
+package schema
+
+import (
+	"unicode/utf8"
+
+	"github.com/Decentr-net/cerberus/pkg/schema/types"
+)
+
+const (
+	maxAppIDLength      = 255
+	maxAppNameLength    = 255
+	maxAppVersionLength = 50
+)
+
+// AppKind is a kind of installed app.
+type AppKind string
+
+// nolint
+const (
+	AppKindExtension AppKind = "extension"
+	AppKindApp       AppKind = "app"
+)
+
+// InstalledApp is browser extension or app installed on user's device.
+type InstalledApp struct {
+	Kind    AppKind `json:"kind"`
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	Version string  `json:"version"`
+	Enabled bool    `json:"enabled"`
+}
+
+// Type ...
+func (InstalledApp) Type() types.Type {
+	return types.PDVInstalledAppType
+}
+
+// Validate ...
+func (d InstalledApp) Validate() bool {
+	return len(d.ValidateWithErrors()) == 0
+}
+
+// ValidateWithErrors ...
+func (d InstalledApp) ValidateWithErrors() []types.FieldError {
+	var errs []types.FieldError
+
+	if d.Kind != AppKindExtension && d.Kind != AppKindApp {
+		errs = append(errs, types.InvalidError("kind", "must be extension or app"))
+	}
+
+	switch {
+	case d.ID == "":
+		errs = append(errs, types.RequiredError("id"))
+	case len(d.ID) > maxAppIDLength:
+		errs = append(errs, types.TooLongError("id", maxAppIDLength))
+	}
+
+	switch {
+	case d.Name == "":
+		errs = append(errs, types.RequiredError("name"))
+	case utf8.RuneCountInString(d.Name) > maxAppNameLength:
+		errs = append(errs, types.TooLongError("name", maxAppNameLength))
+	}
+
+	if len(d.Version) > maxAppVersionLength {
+		errs = append(errs, types.TooLongError("version", maxAppVersionLength))
+	}
+
+	return errs
+}
+
+// MarshalJSON ...
+func (d InstalledApp) MarshalJSON() ([]byte, error) {
+	return types.MarshalPDVData(d)
+}
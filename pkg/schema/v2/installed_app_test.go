@@ -0,0 +1,81 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstalledApp_Validate(t *testing.T) {
+	tt := []struct {
+		name  string
+		d     InstalledApp
+		valid bool
+	}{
+		{
+			name: "valid extension",
+			d: InstalledApp{
+				Kind:    AppKindExtension,
+				ID:      "decentr",
+				Name:    "Decentr",
+				Version: "1.0.0",
+				Enabled: true,
+			},
+			valid: true,
+		},
+		{
+			name: "valid app",
+			d: InstalledApp{
+				Kind: AppKindApp,
+				ID:   "xyz.decentr.app",
+				Name: "Decentr",
+			},
+			valid: true,
+		},
+		{
+			name: "unknown kind",
+			d: InstalledApp{
+				Kind: "plugin",
+				ID:   "decentr",
+				Name: "Decentr",
+			},
+			valid: false,
+		},
+		{
+			name: "empty id",
+			d: InstalledApp{
+				Kind: AppKindApp,
+				Name: "Decentr",
+			},
+			valid: false,
+		},
+		{
+			name: "empty name",
+			d: InstalledApp{
+				Kind: AppKindApp,
+				ID:   "decentr",
+			},
+			valid: false,
+		},
+		{
+			name: "too long version",
+			d: InstalledApp{
+				Kind:    AppKindApp,
+				ID:      "decentr",
+				Name:    "Decentr",
+				Version: strings.Repeat("1", maxAppVersionLength+1),
+			},
+			valid: false,
+		},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.valid, tc.d.Validate())
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package schema
+
+import (
+	"unicode/utf8"
+
+	"github.com/Decentr-net/cerberus/pkg/schema/types"
+)
+
+const maxCategoryLength = 100
+
+// InterestKind is a kind of interest signal.
+type InterestKind string
+
+// nolint
+const (
+	InterestKindPurchase InterestKind = "purchase"
+	InterestKindInterest InterestKind = "interest"
+)
+
+// Interest is a purchase or interest signal in some category (e.g. a product bought or an article read).
+type Interest struct {
+	types.Timestamp
+
+	Source types.Source `json:"source"`
+
+	Kind     InterestKind `json:"kind"`
+	Category string       `json:"category"`
+}
+
+// Type ...
+func (Interest) Type() types.Type {
+	return types.PDVInterestType
+}
+
+// Validate ...
+func (d Interest) Validate() bool {
+	return len(d.ValidateWithErrors()) == 0
+}
+
+// ValidateWithErrors ...
+func (d Interest) ValidateWithErrors() []types.FieldError {
+	var errs []types.FieldError
+
+	if d.Kind != InterestKindPurchase && d.Kind != InterestKindInterest {
+		errs = append(errs, types.InvalidError("kind", "must be purchase or interest"))
+	}
+
+	switch {
+	case d.Category == "":
+		errs = append(errs, types.RequiredError("category"))
+	case utf8.RuneCountInString(d.Category) > maxCategoryLength:
+		errs = append(errs, types.TooLongError("category", maxCategoryLength))
+	}
+
+	errs = append(errs, types.NestFieldErrors("source", d.Source.ValidateWithErrors())...)
+
+	return append(errs, d.Timestamp.ValidateWithErrors()...)
+}
+
+// MarshalJSON ...
+func (d Interest) MarshalJSON() ([]byte, error) {
+	return types.MarshalPDVData(d)
+}
@@ -0,0 +1,87 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Decentr-net/cerberus/pkg/schema/types"
+)
+
+func TestInterest_Validate(t *testing.T) {
+	tt := []struct {
+		name  string
+		d     Interest
+		valid bool
+	}{
+		{
+			name: "valid purchase",
+			d: Interest{
+				Timestamp: types.Timestamp{Time: time.Now()},
+				Source:    types.Source{Host: "https://decentr.xyz"},
+				Kind:      InterestKindPurchase,
+				Category:  "electronics",
+			},
+			valid: true,
+		},
+		{
+			name: "valid interest",
+			d: Interest{
+				Timestamp: types.Timestamp{Time: time.Now()},
+				Source:    types.Source{Host: "https://decentr.xyz"},
+				Kind:      InterestKindInterest,
+				Category:  "crypto",
+			},
+			valid: true,
+		},
+		{
+			name: "unknown kind",
+			d: Interest{
+				Timestamp: types.Timestamp{Time: time.Now()},
+				Source:    types.Source{Host: "https://decentr.xyz"},
+				Kind:      "like",
+				Category:  "crypto",
+			},
+			valid: false,
+		},
+		{
+			name: "empty category",
+			d: Interest{
+				Timestamp: types.Timestamp{Time: time.Now()},
+				Source:    types.Source{Host: "https://decentr.xyz"},
+				Kind:      InterestKindInterest,
+			},
+			valid: false,
+		},
+		{
+			name: "too long category",
+			d: Interest{
+				Timestamp: types.Timestamp{Time: time.Now()},
+				Source:    types.Source{Host: "https://decentr.xyz"},
+				Kind:      InterestKindInterest,
+				Category:  strings.Repeat("a", maxCategoryLength+1),
+			},
+			valid: false,
+		},
+		{
+			name: "invalid source",
+			d: Interest{
+				Timestamp: types.Timestamp{Time: time.Now()},
+				Kind:      InterestKindInterest,
+				Category:  "crypto",
+			},
+			valid: false,
+		},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.valid, tc.d.Validate())
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package schema
+
+import (
+	v1 "github.com/Decentr-net/cerberus/pkg/schema/v1"
+)
+
+// FromV1 converts v1 batch to v2 one. All v1 data types are part of v2, so data is copied as is.
+func FromV1(p v1.PDV) PDV {
+	out := make(PDV, len(p))
+	copy(out, p)
+
+	return out
+}
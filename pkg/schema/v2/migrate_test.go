@@ -0,0 +1,36 @@
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Decentr-net/cerberus/pkg/schema/types"
+	v1 "github.com/Decentr-net/cerberus/pkg/schema/v1"
+)
+
+func TestFromV1(t *testing.T) {
+	in := v1.PDV{
+		&v1.Cookie{
+			Timestamp: types.Timestamp{Time: time.Now()},
+			Source:    types.Source{Host: "https://decentr.xyz"},
+			Name:      "cookie",
+			Value:     "value",
+		},
+		&v1.SearchHistory{
+			Timestamp: types.Timestamp{Time: time.Now()},
+			Engine:    "decentr",
+			Domain:    "decentr.xyz",
+			Query:     "the best crypto",
+		},
+	}
+
+	out := FromV1(in)
+	require.Equal(t, Version, out.Version())
+	require.Equal(t, in.Data(), out.Data())
+	require.True(t, out.Validate())
+
+	out[0] = nil
+	require.NotNil(t, in[0])
+}
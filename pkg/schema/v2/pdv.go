@@ -0,0 +1,150 @@
+// Package schema implements schema for version v2.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/Decentr-net/cerberus/pkg/schema/types"
+	v1 "github.com/Decentr-net/cerberus/pkg/schema/v1"
+)
+
+// Version ...
+const Version types.Version = "v2"
+
+var _ types.PDV = PDV{}
+
+// nolint
+type (
+	AdvertiserID  = v1.AdvertiserID
+	Cookie        = v1.Cookie
+	Location      = v1.Location
+	Profile       = v1.Profile
+	SearchHistory = v1.SearchHistory
+)
+
+var dataSchemes = types.TypeMapper{ // nolint:gochecknoglobals
+	types.PDVAdvertiserIDType:  reflect.TypeOf(AdvertiserID{}),
+	types.PDVCookieType:        reflect.TypeOf(Cookie{}),
+	types.PDVLocationType:      reflect.TypeOf(Location{}),
+	types.PDVSearchHistoryType: reflect.TypeOf(SearchHistory{}),
+	types.PDVProfileType:       reflect.TypeOf(Profile{}),
+
+	types.PDVBrowsingHistoryType: reflect.TypeOf(BrowsingHistory{}),
+	types.PDVBookmarkType:        reflect.TypeOf(Bookmark{}),
+	types.PDVInstalledAppType:    reflect.TypeOf(InstalledApp{}),
+	types.PDVDeviceInfoType:      reflect.TypeOf(DeviceInfo{}),
+	types.PDVInterestType:        reflect.TypeOf(Interest{}),
+}
+
+// PDV is PDVObject implementation with v2 version.
+type PDV []types.Data
+
+// Version returns version of PDV.
+func (PDV) Version() types.Version {
+	return Version
+}
+
+// Data returns slice of data.
+func (o PDV) Data() []types.Data {
+	return o
+}
+
+// UnmarshalJSON ...
+func (o *PDV) UnmarshalJSON(b []byte) error {
+	var data []json.RawMessage
+
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+
+	out := make([]types.Data, len(data))
+
+	for i, v := range data {
+		d, err := dataSchemes.UnmarshalPDVData(v)
+		if err != nil {
+			return err
+		}
+
+		out[i] = d
+	}
+
+	*o = out
+
+	return nil
+}
+
+// MarshalJSON ...
+func (o PDV) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Version types.Version `json:"version"`
+		PDV     interface{}   `json:"pdv"`
+	}{
+		Version: o.Version(),
+		PDV:     o.Data(),
+	})
+}
+
+// Validate ...
+func (o PDV) Validate() bool {
+	return len(o.ValidateWithErrors()) == 0
+}
+
+// ValidateWithErrors ...
+func (o PDV) ValidateWithErrors() []types.FieldError {
+	if len(o) == 0 {
+		return []types.FieldError{types.RequiredError("")}
+	}
+
+	var errs []types.FieldError
+	for i, v := range o {
+		errs = append(errs, types.NestFieldErrors(fmt.Sprintf("[%d]", i), v.ValidateWithErrors())...)
+	}
+	return errs
+}
+
+// GetPDVErrors returns errors of invalid PDV, paths start with the index of PDV (e.g. [1].url).
+func GetPDVErrors(b []byte) ([]types.FieldError, error) {
+	var data []json.RawMessage
+
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+
+	var out []types.FieldError
+
+	for i, v := range data {
+		path := fmt.Sprintf("[%d]", i)
+
+		pdv, err := dataSchemes.UnmarshalPDVData(v)
+		if err != nil {
+			out = append(out, types.FieldError{Path: path, Code: types.ErrorCodeMalformed, Message: err.Error()})
+			continue
+		}
+
+		out = append(out, types.NestFieldErrors(path, pdv.ValidateWithErrors())...)
+	}
+
+	return out, nil
+}
+
+// GetInvalidPDV returns indicies of invalid PDV.
+func GetInvalidPDV(b []byte) ([]int, error) {
+	var data []json.RawMessage
+
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+
+	out := make([]int, 0, len(data))
+
+	for i, v := range data {
+		pdv, err := dataSchemes.UnmarshalPDVData(v)
+		if err != nil || !pdv.Validate() {
+			out = append(out, i)
+		}
+	}
+
+	return out, nil
+}
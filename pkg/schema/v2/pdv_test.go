@@ -0,0 +1,149 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Decentr-net/cerberus/pkg/schema/types"
+)
+
+func TestPDV_Validate(t *testing.T) {
+	require.True(t, PDV{
+		Cookie{
+			Timestamp: types.Timestamp{Time: time.Now()},
+			Source:    types.Source{Host: "https://decentr.xyz"},
+			Name:      "cookie",
+			Value:     "value",
+		},
+		BrowsingHistory{
+			Timestamp:  types.Timestamp{Time: time.Now()},
+			URL:        "https://decentr.xyz",
+			Title:      "Decentr",
+			VisitCount: 1,
+			Duration:   60,
+		},
+	}.Validate())
+}
+
+func TestPDV_Validate_invalid(t *testing.T) {
+	require.False(t, PDV{}.Validate())
+
+	require.False(t, PDV{
+		BrowsingHistory{
+			Timestamp: types.Timestamp{Time: time.Now()},
+			URL:       "https://decentr.xyz",
+		},
+	}.Validate())
+}
+
+func TestPDV_UnmarshalJSON(t *testing.T) {
+	data := `[
+		{
+			"type": "advertiserId",
+			"advertiser": "decentr",
+			"name": "12345qwert",
+			"value": "12345value"
+		},
+		{
+			"timestamp": "2021-05-11T11:05:18Z",
+			"type": "browsingHistory",
+			"url": "https://decentr.xyz/",
+			"title": "Decentr",
+			"visitCount": 3,
+			"duration": 120
+		},
+		{
+			"timestamp": "2021-05-11T11:05:18Z",
+			"type": "bookmark",
+			"url": "https://decentr.xyz/",
+			"title": "Decentr",
+			"folder": "crypto"
+		},
+		{
+			"type": "installedApp",
+			"kind": "extension",
+			"id": "decentr",
+			"name": "Decentr",
+			"version": "1.0.0",
+			"enabled": true
+		},
+		{
+			"timestamp": "2021-05-11T11:05:18Z",
+			"type": "deviceInfo",
+			"os": "macOS",
+			"osVersion": "12.4",
+			"language": "en-US",
+			"screen": {"width": 1920, "height": 1080},
+			"timezone": "Europe/Berlin"
+		},
+		{
+			"timestamp": "2021-05-11T11:05:18Z",
+			"type": "interest",
+			"source": {
+				"host": "https://decentr.xyz",
+				"path": "/"
+			},
+			"kind": "purchase",
+			"category": "electronics"
+		}
+	]`
+
+	var p PDV
+	require.NoError(t, json.Unmarshal([]byte(data), &p))
+	require.Len(t, p, 6)
+	require.True(t, p.Validate())
+
+	b, err := json.Marshal(p)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"version":"v2","pdv":`+data+`}`, string(b))
+}
+
+func TestGetInvalidPDV(t *testing.T) {
+	s, err := GetInvalidPDV([]byte(`[
+		{
+			"timestamp": "2021-05-11T11:05:18Z",
+			"type": "browsingHistory",
+			"url": "https://decentr.xyz/",
+			"visitCount": 1
+		},
+		{
+			"timestamp": "2021-05-11T11:05:18Z",
+			"type": "browsingHistory",
+			"url": "decentr.xyz",
+			"visitCount": 1
+		},
+		{
+			"type": "unknown"
+		}
+	]`))
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2}, s)
+}
+
+func TestGetPDVErrors(t *testing.T) {
+	errs, err := GetPDVErrors([]byte(`[
+		{
+			"type": "installedApp",
+			"kind": "extension",
+			"id": "decentr",
+			"name": "Decentr"
+		},
+		{
+			"type": "installedApp",
+			"kind": "plugin",
+			"id": "decentr"
+		},
+		"cookie"
+	]`))
+	require.NoError(t, err)
+	require.Len(t, errs, 3)
+	require.Equal(t, []types.FieldError{
+		types.InvalidError("[1].kind", "must be extension or app"),
+		types.RequiredError("[1].name"),
+	}, errs[:2])
+	require.Equal(t, "[2]", errs[2].Path)
+	require.Equal(t, types.ErrorCodeMalformed, errs[2].Code)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Decentr-net/cerberus/internal/crypto/local"
+)
+
+var errInvalidKey = errors.New("key must be 32 bytes in hex")
+
+type KeyFileOpts struct {
+	File       string `long:"file" env:"KEY_PROVIDER_LOCAL_FILE" default:"configs/key.json" description:"path to passphrase-protected key file"`
+	KeyID      string `long:"key-id" env:"KEY_PROVIDER_LOCAL_KEY_ID" default:"1" description:"id of the key file, it's written into wrapped keys"`
+	Passphrase string `long:"passphrase" env:"KEY_PROVIDER_LOCAL_PASSPHRASE" required:"true" description:"passphrase of the key file"`
+}
+
+// InitCommand creates a new key file.
+type InitCommand struct {
+	KeyFileOpts
+}
+
+// Execute implements flags.Commander interface.
+func (c *InitCommand) Execute([]string) error {
+	if err := local.GenerateKeyFile(c.File, c.Passphrase); err != nil {
+		return err
+	}
+
+	logrus.Infof("key file %s is created", c.File)
+
+	return nil
+}
+
+// WrapCommand wraps encrypt key with the key file and prints it in base64.
+type WrapCommand struct {
+	KeyFileOpts
+
+	Key string `long:"key" description:"encrypt key in hex to wrap, e.g. legacy encrypt-key value; new random key is generated if empty"`
+}
+
+// Execute implements flags.Commander interface.
+func (c *WrapCommand) Execute([]string) error {
+	kp, err := local.NewKeyProvider(map[string]string{c.KeyID: c.File}, c.Passphrase, c.KeyID)
+	if err != nil {
+		return err
+	}
+
+	key := make([]byte, 32)
+	if c.Key == "" {
+		if _, err := rand.Read(key); err != nil {
+			return fmt.Errorf("failed to generate key: %w", err)
+		}
+	} else if key, err = hex.DecodeString(c.Key); err != nil || len(key) != 32 {
+		return errInvalidKey
+	}
+
+	wrapped, err := kp.WrapKey(context.Background(), key)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(base64.StdEncoding.EncodeToString(wrapped))
+
+	return nil
+}
+
+// RewrapCommand rewraps encrypt key wrapped with previous key file and prints it in base64.
+type RewrapCommand struct {
+	KeyFileOpts
+
+	Files   map[string]string `long:"files" env:"KEY_PROVIDER_LOCAL_FILES" env-delim:"," description:"previous key files by id, e.g. 1:configs/key.json"`
+	Wrapped string            `long:"wrapped" required:"true" description:"wrapped encrypt key in base64, e.g. encrypt-keys value"`
+}
+
+// Execute implements flags.Commander interface.
+func (c *RewrapCommand) Execute([]string) error {
+	files := map[string]string{c.KeyID: c.File}
+	for id, path := range c.Files {
+		files[id] = path
+	}
+
+	kp, err := local.NewKeyProvider(files, c.Passphrase, c.KeyID)
+	if err != nil {
+		return err
+	}
+
+	wrapped, err := base64.StdEncoding.DecodeString(c.Wrapped)
+	if err != nil {
+		return fmt.Errorf("failed to decode wrapped key: %w", err)
+	}
+
+	key, err := kp.UnwrapKey(context.Background(), wrapped)
+	if err != nil {
+		return err
+	}
+
+	if wrapped, err = kp.WrapKey(context.Background(), key); err != nil {
+		return err
+	}
+
+	fmt.Println(base64.StdEncoding.EncodeToString(wrapped))
+
+	return nil
+}
+
+func main() {
+	parser := flags.NewParser(nil, flags.Default)
+	parser.ShortDescription = "keytool"
+	parser.LongDescription = "keytool manages local key provider's key file and encrypt keys"
+
+	if _, err := parser.AddCommand("init", "create key file", "Creates a new key file protected by passphrase.", &InitCommand{}); err != nil {
+		logrus.WithError(err).Fatal("failed to add init command")
+	}
+	if _, err := parser.AddCommand("wrap", "wrap encrypt key", "Wraps encrypt key with the key file and prints it in base64.", &WrapCommand{}); err != nil {
+		logrus.WithError(err).Fatal("failed to add wrap command")
+	}
+	if _, err := parser.AddCommand("rewrap", "rewrap encrypt key", "Rewraps encrypt key with the key file and prints it in base64.", &RewrapCommand{}); err != nil {
+		logrus.WithError(err).Fatal("failed to add rewrap command")
+	}
+
+	if _, err := parser.Parse(); err != nil {
+		os.Exit(1)
+	}
+}
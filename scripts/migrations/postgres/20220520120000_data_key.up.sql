@@ -0,0 +1,6 @@
+CREATE TABLE data_key
+(
+    owner      TEXT PRIMARY KEY,
+    key        BYTEA     NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
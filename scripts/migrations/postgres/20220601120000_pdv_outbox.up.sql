@@ -0,0 +1,6 @@
+CREATE TABLE pdv_outbox
+(
+    id         BIGSERIAL PRIMARY KEY,
+    body       JSONB     NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
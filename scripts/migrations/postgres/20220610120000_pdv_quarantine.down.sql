@@ -0,0 +1 @@
+DROP TABLE pdv_quarantine;
@@ -0,0 +1,8 @@
+CREATE TABLE pdv_quarantine
+(
+    id            BIGSERIAL PRIMARY KEY,
+    body          TEXT      NOT NULL,
+    reason        TEXT      NOT NULL,
+    receive_count INT       NOT NULL,
+    created_at    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,2 @@
+DROP TABLE reward_tx_item;
+DROP TABLE reward_tx;
@@ -0,0 +1,23 @@
+CREATE TABLE reward_tx
+(
+    id         BIGSERIAL PRIMARY KEY,
+    tx_hash    TEXT,
+    status     TEXT      NOT NULL CHECK (status IN ('pending', 'broadcast', 'confirmed', 'failed')),
+    reason     TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX reward_tx_status_idx ON reward_tx (status) WHERE status IN ('pending', 'broadcast');
+
+CREATE TABLE reward_tx_item
+(
+    tx_id  BIGINT NOT NULL REFERENCES reward_tx (id) ON DELETE CASCADE,
+    owner  TEXT   NOT NULL,
+    pdv_id BIGINT NOT NULL,
+    device TEXT   NOT NULL,
+    meta   JSONB  NOT NULL,
+    PRIMARY KEY (tx_id, owner, pdv_id)
+);
+
+CREATE INDEX reward_tx_item_pdv_idx ON reward_tx_item (owner, pdv_id);
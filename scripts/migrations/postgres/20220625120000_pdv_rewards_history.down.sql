@@ -0,0 +1,5 @@
+DROP TABLE pdv_rewards_history;
+
+ALTER TABLE rewards_queue
+    DROP COLUMN period_start,
+    DROP COLUMN period_end;
@@ -0,0 +1,16 @@
+ALTER TABLE rewards_queue
+    ADD COLUMN period_start TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN period_end   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+CREATE TABLE pdv_rewards_history
+(
+    id           BIGSERIAL PRIMARY KEY,
+    address      TEXT      NOT NULL,
+    amount       BIGINT    NOT NULL,
+    period_start TIMESTAMP NOT NULL,
+    period_end   TIMESTAMP NOT NULL,
+    tx_hash      TEXT      NOT NULL,
+    created_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX pdv_rewards_history_address_idx ON pdv_rewards_history (address, id);
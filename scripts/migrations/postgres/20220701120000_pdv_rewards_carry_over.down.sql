@@ -0,0 +1 @@
+DROP TABLE pdv_rewards_carry_over;
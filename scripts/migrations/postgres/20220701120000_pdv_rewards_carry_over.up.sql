@@ -0,0 +1,6 @@
+CREATE TABLE pdv_rewards_carry_over
+(
+    address    TEXT PRIMARY KEY,
+    delta      DECIMAL   NOT NULL CHECK (delta > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
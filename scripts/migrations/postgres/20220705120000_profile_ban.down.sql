@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP TABLE admin_audit;
+DROP TABLE profile_ban_pdv;
+
+ALTER TABLE profile
+    DROP COLUMN ban_source,
+    DROP COLUMN ban_reason,
+    DROP COLUMN banned_at;
+
+COMMIT;
@@ -0,0 +1,31 @@
+BEGIN;
+
+ALTER TABLE profile
+    ADD COLUMN ban_source TEXT,
+    ADD COLUMN ban_reason TEXT,
+    ADD COLUMN banned_at  TIMESTAMP;
+
+-- only hades could ban profiles before
+UPDATE profile SET ban_source = 'hades', ban_reason = 'fraud detected', banned_at = CURRENT_TIMESTAMP WHERE banned;
+
+CREATE TABLE profile_ban_pdv
+(
+    address    TEXT      NOT NULL,
+    pdv_id     BIGINT    NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (address, pdv_id)
+);
+
+CREATE TABLE admin_audit
+(
+    id         BIGSERIAL PRIMARY KEY,
+    actor      TEXT      NOT NULL,
+    action     TEXT      NOT NULL,
+    address    TEXT      NOT NULL,
+    reason     TEXT      NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX admin_audit_address_idx ON admin_audit (address, id);
+
+COMMIT;
@@ -0,0 +1,9 @@
+CREATE TABLE pdv_fraud_recheck
+(
+    address    TEXT      NOT NULL,
+    pdv_id     BIGINT    NOT NULL,
+    held       BOOL      NOT NULL,
+    message    BYTEA     NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (address, pdv_id)
+);
@@ -0,0 +1 @@
+DROP TABLE fraud_verdict;
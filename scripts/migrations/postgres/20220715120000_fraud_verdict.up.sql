@@ -0,0 +1,16 @@
+BEGIN;
+
+CREATE TABLE fraud_verdict
+(
+    address    TEXT      NOT NULL,
+    pdv_id     BIGINT    NOT NULL,
+    verdict    TEXT      NOT NULL,
+    rule       TEXT      NOT NULL DEFAULT '',
+    latency_ms BIGINT    NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (address, pdv_id)
+);
+
+CREATE INDEX fraud_verdict_created_at_idx ON fraud_verdict (created_at);
+
+COMMIT;
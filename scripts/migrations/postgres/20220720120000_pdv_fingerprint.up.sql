@@ -0,0 +1,13 @@
+BEGIN;
+
+CREATE TABLE pdv_fingerprint
+(
+    address     TEXT      NOT NULL,
+    fingerprint BYTEA     NOT NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (address, fingerprint)
+);
+
+CREATE INDEX pdv_fingerprint_created_at_idx ON pdv_fingerprint (created_at);
+
+COMMIT;
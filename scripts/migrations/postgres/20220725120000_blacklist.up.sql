@@ -0,0 +1,16 @@
+BEGIN;
+
+CREATE TABLE blacklist
+(
+    id         BIGSERIAL PRIMARY KEY,
+    kind       TEXT      NOT NULL,
+    pattern    TEXT      NOT NULL,
+    created_by TEXT      NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (kind, pattern)
+);
+
+-- the blacklist was hard-coded before
+INSERT INTO blacklist(kind, pattern, created_by) VALUES ('cookie_source', 'youtube.com', 'migration');
+
+COMMIT;
@@ -0,0 +1,3 @@
+ALTER TABLE pdv_outbox
+    DROP COLUMN attempts,
+    DROP COLUMN locked_until;
@@ -0,0 +1,3 @@
+ALTER TABLE pdv_outbox
+    ADD COLUMN attempts     INT NOT NULL DEFAULT 0,
+    ADD COLUMN locked_until TIMESTAMP;
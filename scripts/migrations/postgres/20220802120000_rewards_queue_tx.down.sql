@@ -0,0 +1,3 @@
+ALTER TABLE rewards_queue
+    DROP COLUMN tx_hash,
+    DROP COLUMN sent_at;
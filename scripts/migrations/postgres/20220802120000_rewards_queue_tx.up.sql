@@ -0,0 +1,3 @@
+ALTER TABLE rewards_queue
+    ADD COLUMN tx_hash TEXT NOT NULL DEFAULT '',
+    ADD COLUMN sent_at TIMESTAMP;
@@ -0,0 +1,5 @@
+ALTER TABLE pdv_fraud_recheck
+    DROP COLUMN attempts,
+    DROP COLUMN next_attempt_at,
+    DROP COLUMN failed,
+    DROP COLUMN last_error;
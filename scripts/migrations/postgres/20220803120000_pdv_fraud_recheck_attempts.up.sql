@@ -0,0 +1,5 @@
+ALTER TABLE pdv_fraud_recheck
+    ADD COLUMN attempts        INT       NOT NULL DEFAULT 0,
+    ADD COLUMN next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN failed          BOOL      NOT NULL DEFAULT FALSE,
+    ADD COLUMN last_error      TEXT      NOT NULL DEFAULT '';
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/jessevdk/go-flags"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Decentr-net/cerberus/internal/producer"
+	ppostgres "github.com/Decentr-net/cerberus/internal/producer/postgres"
+	psqs "github.com/Decentr-net/cerberus/internal/producer/sqs"
+	"github.com/Decentr-net/cerberus/internal/storage"
+	"github.com/Decentr-net/cerberus/internal/storage/postgres"
+)
+
+type DBOpts struct {
+	Postgres                   string `long:"postgres" env:"POSTGRES" default:"host=localhost port=5432 user=postgres password=root sslmode=disable" description:"postgres dsn"`
+	PostgresMaxOpenConnections int    `long:"postgres.max_open_connections" env:"POSTGRES_MAX_OPEN_CONNECTIONS" default:"0" description:"postgres maximal open connections count, 0 means unlimited"`
+	PostgresMaxIdleConnections int    `long:"postgres.max_idle_connections" env:"POSTGRES_MAX_IDLE_CONNECTIONS" default:"5" description:"postgres maximal idle connections count"`
+}
+
+type SQSOpts struct {
+	SQSRegion         string `long:"sqs.region" env:"SQS_REGION" default:"" description:"sqs region"`
+	SQSAccessKeyID    string `long:"sqs.access-key-id" env:"SQS_ACCESS_KEY_ID" description:"access key id for SQS"`
+	SQSecretAccessKey string `long:"sqs.secret-access-key" env:"SQS_SECRET_ACCESS_KEY" description:"secret access key for SQS"`
+	SQSQueue          string `long:"sqs.queue" env:"SQS_QUEUE" default:"testnet" description:"SQS queue name"`
+}
+
+var opts = struct {
+	DBOpts
+}{}
+
+// ListCommand prints quarantined messages.
+type ListCommand struct{}
+
+// Execute implements flags.Commander interface.
+func (c *ListCommand) Execute([]string) error {
+	items, err := postgres.New(mustGetDB()).GetQuarantineItemList(context.Background())
+	if err != nil {
+		return err
+	}
+
+	for _, v := range items {
+		fmt.Printf("%d\t%s\t%d\t%s\n", v.ID, v.CreatedAt.Format("2006-01-02 15:04:05"), v.ReceiveCount, v.Reason)
+	}
+
+	return nil
+}
+
+// RequeueCommand sends quarantined messages back to SQS queue or postgres outbox.
+type RequeueCommand struct {
+	SQSOpts
+
+	QueueDriver string   `long:"queue.driver" default:"sqs" choice:"sqs" choice:"postgres" description:"queue to send messages to, postgres uses pdv_outbox table"`
+	IDs         []uint64 `long:"id" description:"id of message to requeue, all messages are requeued if empty"`
+}
+
+// Execute implements flags.Commander interface.
+func (c *RequeueCommand) Execute([]string) error {
+	ctx := context.Background()
+	db := mustGetDB()
+	is := postgres.New(db)
+
+	items, err := is.GetQuarantineItemList(ctx)
+	if err != nil {
+		return err
+	}
+
+	var p producer.Producer = ppostgres.New(db)
+	if c.QueueDriver == "sqs" {
+		sess := session.Must(session.NewSession(&aws.Config{
+			Region:      aws.String(c.SQSRegion),
+			Credentials: credentials.NewStaticCredentials(c.SQSAccessKeyID, c.SQSecretAccessKey, ""),
+		}))
+
+		client := sqs.New(sess)
+		queue, err := client.GetQueueUrl(&sqs.GetQueueUrlInput{
+			QueueName: &c.SQSQueue,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get queue url: %w", err)
+		}
+
+		p = psqs.New(client, *queue.QueueUrl)
+	}
+
+	for _, v := range filter(items, c.IDs) {
+		var m producer.PDVMessage
+		if err := json.Unmarshal([]byte(v.Body), &m); err != nil {
+			return fmt.Errorf("failed to unmarshal message %d: %w", v.ID, err)
+		}
+
+		if err := p.Produce(ctx, &m); err != nil {
+			return fmt.Errorf("failed to send message %d: %w", v.ID, err)
+		}
+
+		if err := is.DeleteQuarantineItem(ctx, v.ID); err != nil {
+			return fmt.Errorf("failed to delete message %d: %w", v.ID, err)
+		}
+
+		logrus.Infof("message %d is requeued", v.ID)
+	}
+
+	return nil
+}
+
+func filter(items []*storage.QuarantineItem, ids []uint64) []*storage.QuarantineItem {
+	if len(ids) == 0 {
+		return items
+	}
+
+	m := make(map[uint64]bool, len(ids))
+	for _, id := range ids {
+		m[id] = true
+	}
+
+	var out []*storage.QuarantineItem
+	for _, v := range items {
+		if m[v.ID] {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+func main() {
+	parser := flags.NewParser(&opts, flags.Default)
+	parser.ShortDescription = "quarantine"
+	parser.LongDescription = "quarantine manages PDV messages which processord failed to process"
+
+	if _, err := parser.AddCommand("list", "list messages", "Prints quarantined messages.", &ListCommand{}); err != nil {
+		logrus.WithError(err).Fatal("failed to add list command")
+	}
+	if _, err := parser.AddCommand("requeue", "requeue messages", "Sends quarantined messages back to SQS queue or postgres outbox.", &RequeueCommand{}); err != nil {
+		logrus.WithError(err).Fatal("failed to add requeue command")
+	}
+
+	if _, err := parser.Parse(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func mustGetDB() *sql.DB {
+	db, err := sql.Open("postgres", opts.Postgres)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to create postgres connection")
+	}
+	db.SetMaxOpenConns(opts.PostgresMaxOpenConnections)
+	db.SetMaxIdleConns(opts.PostgresMaxIdleConnections)
+
+	if err := db.PingContext(context.Background()); err != nil {
+		logrus.WithError(err).Fatal("failed to ping postgres")
+	}
+
+	return db
+}